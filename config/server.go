@@ -1,8 +1,33 @@
 package config
 
 type ServerConfig struct {
-	Port string
+	Port        string
+	ServiceName string
 }
 type DBConfig struct {
 	ConnectionString string
 }
+
+// TracingConfig выбирает экспортёр трассировки: "otlp", "stdout" или пустая строка, чтобы выключить её.
+// Адрес коллектора для otlp задаётся стандартными переменными OTEL_EXPORTER_OTLP_*.
+type TracingConfig struct {
+	Exporter    string
+	ServiceName string
+}
+
+// GitHubConfig хранит секрет вебхука GitHub. Пока он пуст, приём событий из GitHub выключен.
+type GitHubConfig struct {
+	WebhookSecret string
+}
+
+// WebhookConfig настраивает исходящие вебхуки. AllowPrivateTargets разрешает подписки на loopback и частные адреса,
+// что нужно только для локальной разработки.
+type WebhookConfig struct {
+	AllowPrivateTargets bool
+}
+
+// AuthConfig хранит служебный токен администратора. Он нужен, чтобы выдать первые токены пользователям;
+// пока он пуст, администратором можно стать только через базу.
+type AuthConfig struct {
+	AdminToken string
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"PRReviewer/internal/core/auth"
+	"PRReviewer/internal/core/dto"
+	"PRReviewer/internal/core/enums"
+	"PRReviewer/internal/core/errs"
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strings"
+)
+
+const bearerPrefix = "Bearer "
+
+type AuthService interface {
+	Authenticate(ctx context.Context, token string) (auth.Caller, error)
+	IssueToken(ctx context.Context, req dto.IssueTokenRequest) (*dto.IssueTokenResponse, error)
+}
+
+// Authenticate определяет инициатора запроса по заголовку Authorization: Bearer <токен>.
+// Запрос без заголовка обрабатывается анонимно, запрос с неверным токеном отклоняется.
+func (h *AuthHandler) Authenticate(c *gin.Context) {
+	header := c.GetHeader("Authorization")
+	if header == "" {
+		c.Next()
+		return
+	}
+
+	token, ok := strings.CutPrefix(header, bearerPrefix)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{Code: enums.CodeUnauthorized, Message: errs.ErrUnauthorized.Error()})
+		return
+	}
+
+	caller, err := h.authSrv.Authenticate(c.Request.Context(), strings.TrimSpace(token))
+	if err != nil {
+		if errors.Is(err, errs.ErrUnauthorized) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{Code: enums.CodeUnauthorized, Message: err.Error()})
+			return
+		}
+		c.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	c.Request = c.Request.WithContext(auth.WithCaller(c.Request.Context(), caller))
+	c.Next()
+}
+
+// RequireAdmin пропускает только запросы, аутентифицированные администратором.
+func (h *AuthHandler) RequireAdmin(c *gin.Context) {
+	caller, ok := auth.CallerFrom(c.Request.Context())
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{Code: enums.CodeUnauthorized, Message: errs.ErrUnauthorized.Error()})
+		return
+	}
+	if !caller.IsAdmin {
+		c.AbortWithStatusJSON(http.StatusForbidden, dto.ErrorResponse{Code: enums.CodeForbidden, Message: errs.ErrForbidden.Error()})
+		return
+	}
+	c.Next()
+}
+
+func (h *AuthHandler) IssueToken(c *gin.Context) {
+	var req dto.IssueTokenRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: err.Error()})
+		return
+	}
+	token, err := h.authSrv.IssueToken(c.Request.Context(), req)
+	if err != nil {
+		if errors.Is(err, errs.ErrNotFound) {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Code: enums.CodeNotFound, Message: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, token)
+}
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"PRReviewer/internal/core/dto"
+	"PRReviewer/internal/core/entities"
+	"PRReviewer/internal/core/enums"
+	"PRReviewer/internal/core/errs"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const (
+	gitHubEventHeader     = "X-GitHub-Event"
+	gitHubSignatureHeader = "X-Hub-Signature-256"
+
+	// maxGitHubPayload — предельный размер тела, который GitHub отправляет в вебхуке.
+	maxGitHubPayload = 25 << 20
+)
+
+type GitHubService interface {
+	HandlePullRequestEvent(ctx context.Context, event dto.GitHubPullRequestEvent) (*entities.PullRequest, error)
+}
+
+// Webhook принимает вебхуки GitHub. Тело проверяется по X-Hub-Signature-256 до разбора.
+// Применённое событие pull_request возвращает pr, всё остальное, включая ping, подтверждается 204.
+func (h *GitHubHandler) Webhook(c *gin.Context) {
+	if len(h.secret) == 0 {
+		c.JSON(http.StatusServiceUnavailable, dto.ErrorResponse{Message: "приём событий из github не настроен"})
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxGitHubPayload))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	if !validGitHubSignature(h.secret, body, c.GetHeader(gitHubSignatureHeader)) {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Code: enums.CodeInvalidSignature, Message: errs.ErrInvalidSignature.Error()})
+		return
+	}
+
+	if c.GetHeader(gitHubEventHeader) != "pull_request" {
+		c.Status(http.StatusNoContent)
+		return
+	}
+
+	var event dto.GitHubPullRequestEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	pr, err := h.githubSrv.HandlePullRequestEvent(c.Request.Context(), event)
+	if err != nil {
+		if errors.Is(err, errs.ErrUnknownGitHubLogin) {
+			c.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{Code: enums.CodeUnknownGitHubLogin, Message: err.Error()})
+			return
+		}
+		if errors.Is(err, errs.ErrNotFound) {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Code: enums.CodeNotFound, Message: err.Error()})
+			return
+		}
+		if errors.Is(err, errs.ErrAlreadyMerged) || errors.Is(err, errs.ErrInvalidTransition) {
+			c.JSON(http.StatusConflict, dto.ErrorResponse{Code: transitionCode(err), Message: err.Error()})
+			return
+		}
+		if errors.Is(err, errs.ErrNoReviewersAvailable) {
+			c.JSON(http.StatusConflict, dto.ErrorResponse{Code: enums.CodeNoCandidate, Message: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	if pr == nil {
+		c.Status(http.StatusNoContent)
+		return
+	}
+	c.JSON(http.StatusOK, pr)
+}
+
+// validGitHubSignature сравнивает подпись "sha256=<hex HMAC-SHA256 тела>" за постоянное время.
+func validGitHubSignature(secret []byte, body []byte, signature string) bool {
+	digest, ok := strings.CutPrefix(signature, "sha256=")
+	if !ok {
+		return false
+	}
+	got, err := hex.DecodeString(digest)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
@@ -1,5 +1,10 @@
 package handlers
 
+import (
+	"sync"
+	"time"
+)
+
 type TeamHandler struct {
 	teamSrv TeamService
 }
@@ -23,3 +28,52 @@ type PullRequestHandler struct {
 func NewPullRequestHandler(prSrv PullRequestService) *PullRequestHandler {
 	return &PullRequestHandler{prSrv}
 }
+
+type AuthHandler struct {
+	authSrv AuthService
+}
+
+func NewAuthHandler(authSrv AuthService) *AuthHandler {
+	return &AuthHandler{authSrv: authSrv}
+}
+
+type StatsHandler struct {
+	statsSrv StatsService
+}
+
+func NewStatsHandler(statsSrv StatsService) *StatsHandler {
+	return &StatsHandler{statsSrv: statsSrv}
+}
+
+type WebhookHandler struct {
+	webhookSrv WebhookService
+}
+
+func NewWebhookHandler(webhookSrv WebhookService) *WebhookHandler {
+	return &WebhookHandler{webhookSrv: webhookSrv}
+}
+
+type StreamHandler struct {
+	streamSrv ReviewStreamService
+	heartbeat time.Duration
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func NewStreamHandler(streamSrv ReviewStreamService, heartbeat time.Duration) *StreamHandler {
+	return &StreamHandler{streamSrv: streamSrv, heartbeat: heartbeat, done: make(chan struct{})}
+}
+
+// Close завершает все открытые потоки; http.Server.Shutdown сам не прерывает долгие ответы.
+func (h *StreamHandler) Close() {
+	h.closeOnce.Do(func() { close(h.done) })
+}
+
+type GitHubHandler struct {
+	githubSrv GitHubService
+	secret    []byte
+}
+
+func NewGitHubHandler(githubSrv GitHubService, secret string) *GitHubHandler {
+	return &GitHubHandler{githubSrv: githubSrv, secret: []byte(secret)}
+}
@@ -13,9 +13,16 @@ import (
 
 type PullRequestService interface {
 	CreatePullRequest(ctx context.Context, request dto.CreatePullRequest) (*entities.PullRequest, error)
-	MergePullRequest(ctx context.Context, requestID string) (*entities.PullRequest, error)
-	ReassignPullRequest(ctx context.Context, requestID string, oldUserID string) (*entities.PullRequest, error)
-	GetUserReviewers(ctx context.Context, userID string) (*dto.GetPullRequestResponse, error)
+	MergePullRequest(ctx context.Context, request dto.MergePullRequest) (*entities.PullRequest, error)
+	ReassignPullRequest(ctx context.Context, request dto.ReassignReviewer) (*entities.ReassignedPullRequest, error)
+	GetUserReviewers(ctx context.Context, query dto.GetUserReviewsQuery) (*dto.GetPullRequestResponse, error)
+	SubmitReview(ctx context.Context, review dto.SubmitReviewRequest) (*entities.PullRequest, error)
+	MarkReadyForReview(ctx context.Context, request dto.PullRequestTransition) (*entities.PullRequest, error)
+	ClosePullRequest(ctx context.Context, request dto.PullRequestTransition) (*entities.PullRequest, error)
+	ReopenPullRequest(ctx context.Context, request dto.PullRequestTransition) (*entities.PullRequest, error)
+	ConvertToDraft(ctx context.Context, request dto.PullRequestTransition) (*entities.PullRequest, error)
+	GetHistory(ctx context.Context, prID string) (*dto.PullRequestHistoryResponse, error)
+	ListPullRequests(ctx context.Context, query dto.ListPullRequestsQuery) (*dto.ListPullRequestsResponse, error)
 }
 
 func (h *PullRequestHandler) CreatePullRequest(c *gin.Context) {
@@ -35,6 +42,18 @@ func (h *PullRequestHandler) CreatePullRequest(c *gin.Context) {
 			c.JSON(http.StatusConflict, dto.ErrorResponse{Code: "PR_EXISTS", Message: err.Error()})
 			return
 		}
+		if errors.Is(err, errs.ErrNoReviewersAvailable) {
+			c.JSON(http.StatusConflict, dto.ErrorResponse{Code: enums.CodeNoCandidate, Message: err.Error()})
+			return
+		}
+		if errors.Is(err, errs.ErrNotTeamMember) {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Code: enums.CodeNotTeamMember, Message: err.Error()})
+			return
+		}
+		if errors.Is(err, errs.ErrTeamArchived) {
+			c.JSON(http.StatusConflict, dto.ErrorResponse{Code: enums.CodeTeamArchived, Message: err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Message: err.Error()})
 		return
 	}
@@ -50,12 +69,29 @@ func (h *PullRequestHandler) MergerPullRequest(c *gin.Context) {
 		return
 	}
 
-	pr, err := h.prSrv.MergePullRequest(c.Request.Context(), req.PullRequestID)
+	pr, err := h.prSrv.MergePullRequest(c.Request.Context(), req)
 	if err != nil {
 		if errors.Is(err, errs.ErrNotFound) {
 			c.JSON(http.StatusNotFound, dto.ErrorResponse{Code: enums.CodeNotFound, Message: err.Error()})
 			return
 		}
+		var policyErr *errs.MergePolicyError
+		if errors.As(err, &policyErr) {
+			c.JSON(http.StatusConflict, dto.ErrorResponse{Code: enums.CodeMergeBlocked, Message: errs.ErrMergePolicyViolation.Error(), Details: policyErr.Unmet})
+			return
+		}
+		if errors.Is(err, errs.ErrUnauthorized) {
+			c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Code: enums.CodeUnauthorized, Message: err.Error()})
+			return
+		}
+		if errors.Is(err, errs.ErrForbidden) {
+			c.JSON(http.StatusForbidden, dto.ErrorResponse{Code: enums.CodeForbidden, Message: err.Error()})
+			return
+		}
+		if errors.Is(err, errs.ErrInvalidTransition) {
+			c.JSON(http.StatusConflict, dto.ErrorResponse{Code: transitionCode(err), Message: err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Message: err.Error()})
 		return
 	}
@@ -69,14 +105,14 @@ func (h *PullRequestHandler) ReassignPullRequest(c *gin.Context) {
 		return
 	}
 
-	pr, err := h.prSrv.ReassignPullRequest(c.Request.Context(), req.PullRequestID, req.OldUserID)
+	pr, err := h.prSrv.ReassignPullRequest(c.Request.Context(), req)
 	if err != nil {
 		if errors.Is(err, errs.ErrNotFound) {
 			c.JSON(http.StatusNotFound, dto.ErrorResponse{Code: enums.CodeNotFound, Message: err.Error()})
 			return
 		}
-		if errors.Is(err, errs.ErrAlreadyMerged) {
-			c.JSON(http.StatusConflict, dto.ErrorResponse{Code: enums.CodeMerged, Message: err.Error()})
+		if errors.Is(err, errs.ErrAlreadyMerged) || errors.Is(err, errs.ErrInvalidTransition) {
+			c.JSON(http.StatusConflict, dto.ErrorResponse{Code: transitionCode(err), Message: err.Error()})
 			return
 		}
 		if errors.Is(err, errs.ErrNoReviewersAvailable) {
@@ -88,6 +124,14 @@ func (h *PullRequestHandler) ReassignPullRequest(c *gin.Context) {
 			c.JSON(http.StatusConflict, dto.ErrorResponse{Code: enums.CodeNotAssigned, Message: err.Error()})
 			return
 		}
+		if errors.Is(err, errs.ErrNotTeamMember) {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Code: enums.CodeNotTeamMember, Message: err.Error()})
+			return
+		}
+		if errors.Is(err, errs.ErrInvalidReviewer) {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Code: enums.CodeInvalidReviewer, Message: err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Message: err.Error()})
 		return
 	}
@@ -95,13 +139,127 @@ func (h *PullRequestHandler) ReassignPullRequest(c *gin.Context) {
 }
 
 func (h *PullRequestHandler) GetReview(c *gin.Context) {
-	var req dto.UserIDQuery
+	var req dto.GetUserReviewsQuery
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	prs, err := h.prSrv.GetUserReviewers(c.Request.Context(), req)
+	if err != nil {
+		if errors.Is(err, errs.ErrNotFound) {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Code: enums.CodeNotFound, Message: err.Error()})
+			return
+		}
+		if errors.Is(err, errs.ErrInvalidCursor) {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Code: enums.CodeInvalidCursor, Message: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, prs)
+}
+
+func (h *PullRequestHandler) SubmitReview(c *gin.Context) {
+	var req dto.SubmitReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	pr, err := h.prSrv.SubmitReview(c.Request.Context(), req)
+	if err != nil {
+		if errors.Is(err, errs.ErrUnauthorized) {
+			c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Code: enums.CodeUnauthorized, Message: err.Error()})
+			return
+		}
+		if errors.Is(err, errs.ErrForbidden) {
+			c.JSON(http.StatusForbidden, dto.ErrorResponse{Code: enums.CodeForbidden, Message: err.Error()})
+			return
+		}
+		if errors.Is(err, errs.ErrNotFound) {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Code: enums.CodeNotFound, Message: err.Error()})
+			return
+		}
+		if errors.Is(err, errs.ErrReviewOnClosedPR) {
+			c.JSON(http.StatusConflict, dto.ErrorResponse{Code: transitionCode(err), Message: err.Error()})
+			return
+		}
+		if errors.Is(err, errs.ErrUserNotAssigned) {
+			c.JSON(http.StatusConflict, dto.ErrorResponse{Code: enums.CodeNotAssigned, Message: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, pr)
+}
+
+func (h *PullRequestHandler) MarkReadyForReview(c *gin.Context) {
+	h.changeStatus(c, h.prSrv.MarkReadyForReview)
+}
+
+func (h *PullRequestHandler) ClosePullRequest(c *gin.Context) {
+	h.changeStatus(c, h.prSrv.ClosePullRequest)
+}
+
+func (h *PullRequestHandler) ReopenPullRequest(c *gin.Context) {
+	h.changeStatus(c, h.prSrv.ReopenPullRequest)
+}
+
+func (h *PullRequestHandler) ConvertToDraft(c *gin.Context) {
+	h.changeStatus(c, h.prSrv.ConvertToDraft)
+}
+
+func (h *PullRequestHandler) changeStatus(c *gin.Context, change func(ctx context.Context, request dto.PullRequestTransition) (*entities.PullRequest, error)) {
+	var req dto.PullRequestTransition
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	pr, err := change(c.Request.Context(), req)
+	if err != nil {
+		if errors.Is(err, errs.ErrNotFound) {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Code: enums.CodeNotFound, Message: err.Error()})
+			return
+		}
+		if errors.Is(err, errs.ErrAlreadyMerged) || errors.Is(err, errs.ErrInvalidTransition) {
+			c.JSON(http.StatusConflict, dto.ErrorResponse{Code: transitionCode(err), Message: err.Error()})
+			return
+		}
+		if errors.Is(err, errs.ErrNoReviewersAvailable) {
+			c.JSON(http.StatusConflict, dto.ErrorResponse{Code: enums.CodeNoCandidate, Message: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, pr)
+}
+
+func transitionCode(err error) enums.Code {
+	switch {
+	case errors.Is(err, errs.ErrAlreadyMerged), errors.Is(err, errs.ErrPRMerged):
+		return enums.CodeMerged
+	case errors.Is(err, errs.ErrPRIsDraft):
+		return enums.CodeDraft
+	case errors.Is(err, errs.ErrPRClosed):
+		return enums.CodeClosed
+	default:
+		return enums.CodeInvalidStatus
+	}
+}
+
+func (h *PullRequestHandler) GetHistory(c *gin.Context) {
+	var req dto.PullRequestIDQuery
 	if err := c.ShouldBindQuery(&req); err != nil {
 		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: err.Error()})
 		return
 	}
 
-	prs, err := h.prSrv.GetUserReviewers(c.Request.Context(), req.UserID)
+	history, err := h.prSrv.GetHistory(c.Request.Context(), req.PullRequestID)
 	if err != nil {
 		if errors.Is(err, errs.ErrNotFound) {
 			c.JSON(http.StatusNotFound, dto.ErrorResponse{Code: enums.CodeNotFound, Message: err.Error()})
@@ -110,5 +268,24 @@ func (h *PullRequestHandler) GetReview(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Message: err.Error()})
 		return
 	}
+	c.JSON(http.StatusOK, history)
+}
+
+func (h *PullRequestHandler) ListPullRequests(c *gin.Context) {
+	var req dto.ListPullRequestsQuery
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	prs, err := h.prSrv.ListPullRequests(c.Request.Context(), req)
+	if err != nil {
+		if errors.Is(err, errs.ErrInvalidCursor) {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Code: enums.CodeInvalidCursor, Message: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Message: err.Error()})
+		return
+	}
 	c.JSON(http.StatusOK, prs)
 }
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"PRReviewer/internal/core/dto"
+	"PRReviewer/internal/core/entities"
+	"PRReviewer/internal/core/enums"
+	"PRReviewer/internal/core/errs"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// DefaultHeartbeatInterval — как часто поток шлёт комментарий-пинг, чтобы прокси не закрывали простаивающее соединение.
+const DefaultHeartbeatInterval = 15 * time.Second
+
+type ReviewStreamService interface {
+	SubscribeReviewEvents(ctx context.Context, userID string) (<-chan entities.PREvent, func(), error)
+	GetUserReviewEvents(ctx context.Context, userID string, afterID int64) ([]entities.PREvent, error)
+}
+
+// ReviewStream отдаёт Server-Sent Events с назначениями, заменами и мерджами для ревьюера.
+// id события — id записи в журнале pr; с заголовком Last-Event-ID поток сначала досылает всё, что было после него.
+// Если клиент не успевает читать или сервер останавливается, поток закрывается и клиент переподключается с Last-Event-ID.
+func (h *StreamHandler) ReviewStream(c *gin.Context) {
+	var req dto.ReviewStreamQuery
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	var lastEventID int64
+	resume := c.GetHeader("Last-Event-ID")
+	if resume != "" {
+		var err error
+		lastEventID, err = strconv.ParseInt(resume, 10, 64)
+		if err != nil || lastEventID < 0 {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: "некорректный Last-Event-ID"})
+			return
+		}
+	}
+
+	ctx := c.Request.Context()
+	events, unsubscribe, err := h.streamSrv.SubscribeReviewEvents(ctx, req.UserID)
+	if err != nil {
+		if errors.Is(err, errs.ErrNotFound) {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Code: enums.CodeNotFound, Message: err.Error()})
+			return
+		}
+		if errors.Is(err, errs.ErrStreamClosed) {
+			c.JSON(http.StatusServiceUnavailable, dto.ErrorResponse{Message: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Message: err.Error()})
+		return
+	}
+	defer unsubscribe()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	// id событий досылки: они могут прийти и через подписку, если были закоммичены после неё
+	sent := make(map[int64]bool)
+	if resume != "" {
+		for {
+			missed, err := h.streamSrv.GetUserReviewEvents(ctx, req.UserID, lastEventID)
+			if err != nil {
+				// заголовки уже отправлены: закрываем поток, клиент переподключится с тем же Last-Event-ID
+				return
+			}
+			if len(missed) == 0 {
+				break
+			}
+			for _, event := range missed {
+				if err := writeReviewEvent(c.Writer, event); err != nil {
+					return
+				}
+				sent[event.ID] = true
+				lastEventID = event.ID
+			}
+		}
+	}
+
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-h.done:
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			// события, попавшие и в досылку, и в подписку, отправляются один раз. Сравнивать с последним id нельзя:
+			// транзакции коммитятся не в порядке id, и событие с меньшим id может прийти позже.
+			if sent[event.ID] {
+				delete(sent, event.ID)
+				continue
+			}
+			if err := writeReviewEvent(c.Writer, event); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(c.Writer, ": heartbeat\n\n"); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		}
+	}
+}
+
+func writeReviewEvent(w gin.ResponseWriter, event entities.PREvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+	if err != nil {
+		return err
+	}
+	w.Flush()
+	return nil
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"PRReviewer/internal/core/dto"
+	"PRReviewer/internal/core/enums"
+	"PRReviewer/internal/core/errs"
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type StatsService interface {
+	GetReviewerStats(ctx context.Context, query dto.StatsQuery) (*dto.ReviewerStatsResponse, error)
+	GetTeamStats(ctx context.Context, query dto.StatsQuery) (*dto.TeamStatsResponse, error)
+}
+
+func (h *StatsHandler) GetReviewerStats(c *gin.Context) {
+	var req dto.StatsQuery
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	stats, err := h.statsSrv.GetReviewerStats(c.Request.Context(), req)
+	if err != nil {
+		if errors.Is(err, errs.ErrNotFound) {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Code: enums.CodeNotFound, Message: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, stats)
+}
+
+func (h *StatsHandler) GetTeamStats(c *gin.Context) {
+	var req dto.StatsQuery
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	stats, err := h.statsSrv.GetTeamStats(c.Request.Context(), req)
+	if err != nil {
+		if errors.Is(err, errs.ErrNotFound) {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Code: enums.CodeNotFound, Message: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, stats)
+}
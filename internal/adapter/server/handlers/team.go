@@ -13,6 +13,18 @@ import (
 type TeamService interface {
 	CreateTeam(ctx context.Context, team *dto.Team) (*dto.Team, error)
 	GetTeamByName(ctx context.Context, teamName string) (*dto.Team, error)
+	UpdateSettings(ctx context.Context, update dto.UpdateTeamSettingsRequest) (*dto.Team, error)
+	SetOwnership(ctx context.Context, ownership dto.TeamOwnership) (*dto.TeamOwnership, error)
+	GetOwnership(ctx context.Context, teamName string) (*dto.TeamOwnership, error)
+	DeactivateUsers(ctx context.Context, req dto.DeactivateTeamUsersRequest) (*dto.DeactivateTeamUsersResponse, error)
+	AddMembers(ctx context.Context, req dto.AddTeamMembersRequest) (*dto.Team, error)
+	RemoveMember(ctx context.Context, req dto.RemoveTeamMemberRequest) (*dto.TeamMemberChangeResponse, error)
+	MoveMember(ctx context.Context, req dto.MoveTeamMemberRequest) (*dto.TeamMemberChangeResponse, error)
+	RenameTeam(ctx context.Context, req dto.RenameTeamRequest) (*dto.Team, error)
+	ArchiveTeam(ctx context.Context, req dto.ArchiveTeamRequest) (*dto.Team, error)
+	DeleteTeam(ctx context.Context, req dto.DeleteTeamRequest) error
+	GetHistory(ctx context.Context, teamName string) (*dto.TeamHistoryResponse, error)
+	ListTeams(ctx context.Context, query dto.ListTeamsQuery) (*dto.ListTeamsResponse, error)
 }
 
 func (h *TeamHandler) CreateTeam(c *gin.Context) {
@@ -29,6 +41,18 @@ func (h *TeamHandler) CreateTeam(c *gin.Context) {
 			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Code: enums.CodeTeamExists, Message: err.Error()})
 			return
 		}
+		if errors.Is(err, errs.ErrFallbackTeamNotFound) {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Code: enums.CodeFallbackNotFound, Message: err.Error()})
+			return
+		}
+		if errors.Is(err, errs.ErrNotFound) {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Code: enums.CodeNotFound, Message: err.Error()})
+			return
+		}
+		if errors.Is(err, errs.ErrInvalidFallbackTeam) {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Code: enums.CodeInvalidSettings, Message: err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Message: err.Error()})
 		return
 	}
@@ -55,3 +79,287 @@ func (h *TeamHandler) GetTeam(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, team)
 }
+
+func (h *TeamHandler) UpdateSettings(c *gin.Context) {
+	var req dto.UpdateTeamSettingsRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	team, err := h.teamSrv.UpdateSettings(c.Request.Context(), req)
+	if err != nil {
+		if errors.Is(err, errs.ErrFallbackTeamNotFound) {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Code: enums.CodeFallbackNotFound, Message: err.Error()})
+			return
+		}
+		if errors.Is(err, errs.ErrNotFound) {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Code: enums.CodeNotFound, Message: err.Error()})
+			return
+		}
+		if errors.Is(err, errs.ErrInvalidFallbackTeam) {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Code: enums.CodeInvalidSettings, Message: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, team)
+}
+
+func (h *TeamHandler) SetOwnership(c *gin.Context) {
+	var req dto.TeamOwnership
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	ownership, err := h.teamSrv.SetOwnership(c.Request.Context(), req)
+	if err != nil {
+		if errors.Is(err, errs.ErrNotFound) {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Code: enums.CodeNotFound, Message: err.Error()})
+			return
+		}
+		if errors.Is(err, errs.ErrInvalidOwnership) {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Code: enums.CodeInvalidSettings, Message: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, ownership)
+}
+
+func (h *TeamHandler) GetOwnership(c *gin.Context) {
+	var req dto.GetTeamOwnershipRequest
+
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	ownership, err := h.teamSrv.GetOwnership(c.Request.Context(), req.TeamName)
+	if err != nil {
+		if errors.Is(err, errs.ErrNotFound) {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Code: enums.CodeNotFound, Message: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, ownership)
+}
+
+func (h *TeamHandler) DeactivateUsers(c *gin.Context) {
+	var req dto.DeactivateTeamUsersRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	response, err := h.teamSrv.DeactivateUsers(c.Request.Context(), req)
+	if err != nil {
+		if errors.Is(err, errs.ErrNotFound) {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Code: enums.CodeNotFound, Message: err.Error()})
+			return
+		}
+		if errors.Is(err, errs.ErrNotTeamMember) {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Code: enums.CodeNotTeamMember, Message: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *TeamHandler) AddMembers(c *gin.Context) {
+	var req dto.AddTeamMembersRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	team, err := h.teamSrv.AddMembers(c.Request.Context(), req)
+	if err != nil {
+		if errors.Is(err, errs.ErrNotFound) {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Code: enums.CodeNotFound, Message: err.Error()})
+			return
+		}
+		if errors.Is(err, errs.ErrTeamArchived) {
+			c.JSON(http.StatusConflict, dto.ErrorResponse{Code: enums.CodeTeamArchived, Message: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, team)
+}
+
+func (h *TeamHandler) RemoveMember(c *gin.Context) {
+	var req dto.RemoveTeamMemberRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	response, err := h.teamSrv.RemoveMember(c.Request.Context(), req)
+	if err != nil {
+		writeMemberChangeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *TeamHandler) MoveMember(c *gin.Context) {
+	var req dto.MoveTeamMemberRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	response, err := h.teamSrv.MoveMember(c.Request.Context(), req)
+	if err != nil {
+		writeMemberChangeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func writeMemberChangeError(c *gin.Context, err error) {
+	if errors.Is(err, errs.ErrNotFound) {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Code: enums.CodeNotFound, Message: err.Error()})
+		return
+	}
+	if errors.Is(err, errs.ErrNotTeamMember) {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Code: enums.CodeNotTeamMember, Message: err.Error()})
+		return
+	}
+	if errors.Is(err, errs.ErrAlreadyTeamMember) {
+		c.JSON(http.StatusConflict, dto.ErrorResponse{Code: enums.CodeAlreadyMember, Message: err.Error()})
+		return
+	}
+	if errors.Is(err, errs.ErrLastTeam) {
+		c.JSON(http.StatusConflict, dto.ErrorResponse{Code: enums.CodeLastTeam, Message: err.Error()})
+		return
+	}
+	if errors.Is(err, errs.ErrTeamArchived) {
+		c.JSON(http.StatusConflict, dto.ErrorResponse{Code: enums.CodeTeamArchived, Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Message: err.Error()})
+}
+
+func (h *TeamHandler) RenameTeam(c *gin.Context) {
+	var req dto.RenameTeamRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	team, err := h.teamSrv.RenameTeam(c.Request.Context(), req)
+	if err != nil {
+		if errors.Is(err, errs.ErrNotFound) {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Code: enums.CodeNotFound, Message: err.Error()})
+			return
+		}
+		if errors.Is(err, errs.ErrAlreadyExists) {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Code: enums.CodeTeamExists, Message: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, team)
+}
+
+func (h *TeamHandler) ArchiveTeam(c *gin.Context) {
+	var req dto.ArchiveTeamRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	team, err := h.teamSrv.ArchiveTeam(c.Request.Context(), req)
+	if err != nil {
+		if errors.Is(err, errs.ErrNotFound) {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Code: enums.CodeNotFound, Message: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, team)
+}
+
+func (h *TeamHandler) DeleteTeam(c *gin.Context) {
+	var req dto.DeleteTeamRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	err := h.teamSrv.DeleteTeam(c.Request.Context(), req)
+	if err != nil {
+		if errors.Is(err, errs.ErrNotFound) {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Code: enums.CodeNotFound, Message: err.Error()})
+			return
+		}
+		if errors.Is(err, errs.ErrTeamHasOpenPRs) {
+			c.JSON(http.StatusConflict, dto.ErrorResponse{Code: enums.CodeTeamHasOpenPRs, Message: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *TeamHandler) GetHistory(c *gin.Context) {
+	var req dto.TeamHistoryQuery
+
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	history, err := h.teamSrv.GetHistory(c.Request.Context(), req.TeamName)
+	if err != nil {
+		if errors.Is(err, errs.ErrNotFound) {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Code: enums.CodeNotFound, Message: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, history)
+}
+
+func (h *TeamHandler) ListTeams(c *gin.Context) {
+	var req dto.ListTeamsQuery
+
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	teams, err := h.teamSrv.ListTeams(c.Request.Context(), req)
+	if err != nil {
+		if errors.Is(err, errs.ErrInvalidCursor) {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Code: enums.CodeInvalidCursor, Message: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, teams)
+}
@@ -12,7 +12,12 @@ import (
 )
 
 type UsersService interface {
-	SetIsActive(ctx context.Context, userID string, isActive bool) (*entities.User, error)
+	SetIsActive(ctx context.Context, userID string, isActive bool) (*dto.SetUserActiveResponse, error)
+	UpdateUser(ctx context.Context, update dto.UpdateUserRequest) (*entities.User, error)
+	SetAdmin(ctx context.Context, req dto.SetAdminRequest) (*entities.User, error)
+	SetGitHubLogin(ctx context.Context, req dto.SetGitHubLoginRequest) (*entities.User, error)
+	SetPrimaryTeam(ctx context.Context, req dto.SetPrimaryTeamRequest) (*entities.User, error)
+	ListUsers(ctx context.Context, query dto.ListUsersQuery) (*dto.ListUsersResponse, error)
 }
 
 func (h *UsersHandler) SetIsActive(c *gin.Context) {
@@ -34,3 +39,111 @@ func (h *UsersHandler) SetIsActive(c *gin.Context) {
 
 	c.JSON(http.StatusOK, user)
 }
+
+func (h *UsersHandler) UpdateUser(c *gin.Context) {
+	var req dto.UpdateUserRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: err.Error()})
+		return
+	}
+	user, err := h.userSrv.UpdateUser(c.Request.Context(), req)
+	if err != nil {
+		if errors.Is(err, errs.ErrNotFound) {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Code: enums.CodeNotFound, Message: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+func (h *UsersHandler) SetAdmin(c *gin.Context) {
+	var req dto.SetAdminRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: err.Error()})
+		return
+	}
+	user, err := h.userSrv.SetAdmin(c.Request.Context(), req)
+	if err != nil {
+		if errors.Is(err, errs.ErrNotFound) {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Code: enums.CodeNotFound, Message: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+func (h *UsersHandler) SetGitHubLogin(c *gin.Context) {
+	var req dto.SetGitHubLoginRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: err.Error()})
+		return
+	}
+	user, err := h.userSrv.SetGitHubLogin(c.Request.Context(), req)
+	if err != nil {
+		if errors.Is(err, errs.ErrGitHubLoginTaken) {
+			c.JSON(http.StatusConflict, dto.ErrorResponse{Code: enums.CodeGitHubLoginTaken, Message: err.Error()})
+			return
+		}
+		if errors.Is(err, errs.ErrNotFound) {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Code: enums.CodeNotFound, Message: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+func (h *UsersHandler) SetPrimaryTeam(c *gin.Context) {
+	var req dto.SetPrimaryTeamRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: err.Error()})
+		return
+	}
+	user, err := h.userSrv.SetPrimaryTeam(c.Request.Context(), req)
+	if err != nil {
+		if errors.Is(err, errs.ErrNotFound) {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Code: enums.CodeNotFound, Message: err.Error()})
+			return
+		}
+		if errors.Is(err, errs.ErrNotTeamMember) {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Code: enums.CodeNotTeamMember, Message: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+func (h *UsersHandler) ListUsers(c *gin.Context) {
+	var req dto.ListUsersQuery
+
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: err.Error()})
+		return
+	}
+	users, err := h.userSrv.ListUsers(c.Request.Context(), req)
+	if err != nil {
+		if errors.Is(err, errs.ErrInvalidCursor) {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Code: enums.CodeInvalidCursor, Message: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, users)
+}
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"PRReviewer/internal/core/dto"
+	"PRReviewer/internal/core/entities"
+	"PRReviewer/internal/core/enums"
+	"PRReviewer/internal/core/errs"
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type WebhookService interface {
+	CreateSubscription(ctx context.Context, req dto.CreateWebhookSubscriptionRequest) (*entities.WebhookSubscription, error)
+	GetSubscriptions(ctx context.Context) (*dto.WebhookSubscriptionsResponse, error)
+	DeleteSubscription(ctx context.Context, subscriptionID string) error
+	GetDeadLetters(ctx context.Context, query dto.DeadLettersQuery) (*dto.DeadLettersResponse, error)
+	RetryDeadLetters(ctx context.Context, req dto.RetryDeadLettersRequest) (*dto.RetryDeadLettersResponse, error)
+}
+
+func (h *WebhookHandler) CreateSubscription(c *gin.Context) {
+	var req dto.CreateWebhookSubscriptionRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	subscription, err := h.webhookSrv.CreateSubscription(c.Request.Context(), req)
+	if err != nil {
+		if errors.Is(err, errs.ErrInvalidWebhookURL) {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Code: enums.CodeInvalidWebhookURL, Message: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, subscription)
+}
+
+func (h *WebhookHandler) GetSubscriptions(c *gin.Context) {
+	subscriptions, err := h.webhookSrv.GetSubscriptions(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, subscriptions)
+}
+
+func (h *WebhookHandler) DeleteSubscription(c *gin.Context) {
+	var req dto.DeleteWebhookSubscriptionRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	err := h.webhookSrv.DeleteSubscription(c.Request.Context(), req.SubscriptionID)
+	if err != nil {
+		if errors.Is(err, errs.ErrNotFound) {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Code: enums.CodeNotFound, Message: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *WebhookHandler) GetDeadLetters(c *gin.Context) {
+	var req dto.DeadLettersQuery
+
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	deliveries, err := h.webhookSrv.GetDeadLetters(c.Request.Context(), req)
+	if err != nil {
+		if errors.Is(err, errs.ErrInvalidCursor) {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Code: enums.CodeInvalidCursor, Message: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, deliveries)
+}
+
+func (h *WebhookHandler) RetryDeadLetters(c *gin.Context) {
+	var req dto.RetryDeadLettersRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	retried, err := h.webhookSrv.RetryDeadLetters(c.Request.Context(), req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, retried)
+}
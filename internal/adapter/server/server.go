@@ -6,6 +6,7 @@ import (
 	"context"
 	"errors"
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 	"log"
 	"net/http"
 )
@@ -14,23 +15,74 @@ type Server struct {
 	server *http.Server
 }
 
-func NewServer(cfg *config.ServerConfig, teamHandler *handlers.TeamHandler, userHandler *handlers.UsersHandler, prHandler *handlers.PullRequestHandler) *Server {
+type Metrics interface {
+	Middleware() gin.HandlerFunc
+	Handler() http.Handler
+}
+
+func NewServer(cfg *config.ServerConfig, teamHandler *handlers.TeamHandler, userHandler *handlers.UsersHandler, prHandler *handlers.PullRequestHandler, statsHandler *handlers.StatsHandler, webhookHandler *handlers.WebhookHandler, streamHandler *handlers.StreamHandler, githubHandler *handlers.GitHubHandler, authHandler *handlers.AuthHandler, metrics Metrics) *Server {
 	r := gin.New()
-	api := r.Group("")
+	r.Use(otelgin.Middleware(cfg.ServiceName), metrics.Middleware())
+	r.GET("/metrics", gin.WrapH(metrics.Handler()))
+
+	api := r.Group("", authHandler.Authenticate)
 	teams := api.Group("/team")
 	teams.POST("/add", teamHandler.CreateTeam)
 	teams.GET("/get", teamHandler.GetTeam)
+	teams.POST("/settings", authHandler.RequireAdmin, teamHandler.UpdateSettings)
+	teams.POST("/owners/set", authHandler.RequireAdmin, teamHandler.SetOwnership)
+	teams.GET("/owners/get", teamHandler.GetOwnership)
+	teams.POST("/deactivateUsers", authHandler.RequireAdmin, teamHandler.DeactivateUsers)
+	teams.POST("/members/add", authHandler.RequireAdmin, teamHandler.AddMembers)
+	teams.POST("/members/remove", authHandler.RequireAdmin, teamHandler.RemoveMember)
+	teams.POST("/members/move", authHandler.RequireAdmin, teamHandler.MoveMember)
+	teams.POST("/rename", authHandler.RequireAdmin, teamHandler.RenameTeam)
+	teams.POST("/archive", authHandler.RequireAdmin, teamHandler.ArchiveTeam)
+	teams.POST("/delete", authHandler.RequireAdmin, teamHandler.DeleteTeam)
+	teams.GET("/history", teamHandler.GetHistory)
+	teams.GET("/list", teamHandler.ListTeams)
 
 	users := api.Group("/users")
-	users.POST("/setIsActive", userHandler.SetIsActive)
+	users.POST("/setIsActive", authHandler.RequireAdmin, userHandler.SetIsActive)
+	users.POST("/update", authHandler.RequireAdmin, userHandler.UpdateUser)
+	users.POST("/setPrimaryTeam", authHandler.RequireAdmin, userHandler.SetPrimaryTeam)
 	users.GET("/getReview", prHandler.GetReview)
+	users.GET("/reviewStream", streamHandler.ReviewStream)
+	users.GET("/list", userHandler.ListUsers)
 
 	pr := api.Group("/pullRequest")
 	pr.POST("/create", prHandler.CreatePullRequest)
 	pr.POST("/merge", prHandler.MergerPullRequest)
 	pr.POST("/reassign", prHandler.ReassignPullRequest)
+	pr.POST("/review", prHandler.SubmitReview)
+	pr.POST("/ready", prHandler.MarkReadyForReview)
+	pr.POST("/close", prHandler.ClosePullRequest)
+	pr.POST("/reopen", prHandler.ReopenPullRequest)
+	pr.POST("/draft", prHandler.ConvertToDraft)
+	pr.GET("/history", prHandler.GetHistory)
+	pr.GET("/list", prHandler.ListPullRequests)
+
+	stats := api.Group("/stats")
+	stats.GET("/reviewers", statsHandler.GetReviewerStats)
+	stats.GET("/teams", statsHandler.GetTeamStats)
+
+	webhooks := api.Group("/webhooks", authHandler.RequireAdmin)
+	webhooks.POST("/subscriptions/add", webhookHandler.CreateSubscription)
+	webhooks.GET("/subscriptions/list", webhookHandler.GetSubscriptions)
+	webhooks.POST("/subscriptions/delete", webhookHandler.DeleteSubscription)
+	webhooks.GET("/deadLetters", webhookHandler.GetDeadLetters)
+	webhooks.POST("/deadLetters/retry", webhookHandler.RetryDeadLetters)
+
+	admin := api.Group("/admin", authHandler.RequireAdmin)
+	admin.POST("/users/setAdmin", userHandler.SetAdmin)
+	admin.POST("/users/setGitHubLogin", userHandler.SetGitHubLogin)
+	admin.POST("/tokens/issue", authHandler.IssueToken)
+
+	integrations := api.Group("/integrations")
+	integrations.POST("/github/webhook", githubHandler.Webhook)
 
 	server := &http.Server{Addr: ":" + cfg.Port, Handler: r}
+	server.RegisterOnShutdown(streamHandler.Close)
 
 	return &Server{server: server}
 }
@@ -4,25 +4,36 @@ import (
 	"PRReviewer/config"
 	"PRReviewer/internal/adapter/server"
 	"PRReviewer/internal/adapter/server/handlers"
+	"PRReviewer/internal/core/selector"
 	"PRReviewer/internal/core/service"
 	"PRReviewer/internal/infrastructure/data/repo"
+	"PRReviewer/internal/infrastructure/metrics"
+	"PRReviewer/internal/infrastructure/pubsub"
+	"PRReviewer/internal/infrastructure/tracing"
+	"PRReviewer/internal/infrastructure/webhook"
 	"context"
 	"database/sql"
 	_ "github.com/jackc/pgx/v5"
 	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
 	"log"
 	"log/slog"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 )
 
 type App struct {
-	cfg    *config.AppConfig
-	server *server.Server
-	db     *sql.DB
-	log    *slog.Logger
+	cfg             *config.AppConfig
+	server          *server.Server
+	dispatcher      *webhook.Dispatcher
+	broker          *pubsub.Broker
+	db              *sql.DB
+	log             *slog.Logger
+	shutdownTracing func(context.Context) error
 }
 
 func New(cfg *config.AppConfig) *App {
@@ -36,22 +47,57 @@ func New(cfg *config.AppConfig) *App {
 		Level: slog.LevelDebug,
 	}))
 
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.TracingCfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	repository := repo.New(db)
 
 	transactor := repo.NewSQLTransactor(db)
 
-	teamSrv := service.NewTeamService(repository, repository, transactor, logger)
+	selectors := selector.NewRegistry()
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		collectors.NewDBStatsCollector(db, "postgres"),
+		metrics.NewOpenReviewsCollector(repository, logger),
+	)
+	appMetrics := metrics.New(registry)
+
+	broker := pubsub.NewBroker()
+
+	prSrv := service.NewPullRequestService(repository, repository, repository, transactor, selectors, appMetrics, broker, logger)
+	prHnd := handlers.NewPullRequestHandler(prSrv)
+	streamHnd := handlers.NewStreamHandler(prSrv, handlers.DefaultHeartbeatInterval)
+
+	teamSrv := service.NewTeamService(repository, repository, prSrv, transactor, logger)
 	teamHnd := handlers.NewTeamHandler(teamSrv)
 
-	userSrv := service.NewUsersService(repository, transactor, logger)
+	userSrv := service.NewUsersService(repository, prSrv, transactor, logger)
 	userHnd := handlers.NewUsersHandler(userSrv)
 
-	prSrv := service.NewPullRequestService(repository, repository, repository, transactor, logger)
-	prHnd := handlers.NewPullRequestHandler(prSrv)
+	statsSrv := service.NewStatsService(repository, repository, logger)
+	statsHnd := handlers.NewStatsHandler(statsSrv)
+
+	dispatcherCfg := webhook.DefaultConfig()
+	dispatcherCfg.AllowPrivateTargets = cfg.WebhookCfg.AllowPrivateTargets
+	dispatcher := webhook.NewDispatcher(repository, dispatcherCfg, logger)
+
+	webhookSrv := service.NewWebhookService(repository, dispatcher, logger)
+	webhookHnd := handlers.NewWebhookHandler(webhookSrv)
 
-	httpServer := server.NewServer(cfg.ServerCfg, teamHnd, userHnd, prHnd)
+	githubSrv := service.NewGitHubService(prSrv, repository, logger)
+	githubHnd := handlers.NewGitHubHandler(githubSrv, cfg.GitHubCfg.WebhookSecret)
 
-	return &App{server: httpServer, log: logger, db: db}
+	authSrv := service.NewAuthService(repository, repository, cfg.AuthCfg.AdminToken, logger)
+	authHnd := handlers.NewAuthHandler(authSrv)
+
+	httpServer := server.NewServer(cfg.ServerCfg, teamHnd, userHnd, prHnd, statsHnd, webhookHnd, streamHnd, githubHnd, authHnd, appMetrics)
+
+	return &App{server: httpServer, dispatcher: dispatcher, broker: broker, log: logger, db: db, shutdownTracing: shutdownTracing}
 }
 
 func (a *App) Run() {
@@ -61,6 +107,13 @@ func (a *App) Run() {
 	go a.stop(cancel)
 	go a.server.Run()
 
+	var workers sync.WaitGroup
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		a.dispatcher.Run(ctx)
+	}()
+
 	<-ctx.Done()
 
 	a.log.Info("shutting down")
@@ -68,6 +121,12 @@ func (a *App) Run() {
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer shutdownCancel()
 	a.server.Stop(shutdownCtx)
+	a.broker.Close()
+	// диспетчер дописывает результаты начатых доставок, поэтому база закрывается только после него
+	workers.Wait()
+	if err := a.shutdownTracing(shutdownCtx); err != nil {
+		a.log.Error("не удалось остановить трассировку", "error", err)
+	}
 	err := a.db.Close()
 	if err != nil {
 		return
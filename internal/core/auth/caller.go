@@ -0,0 +1,29 @@
+package auth
+
+import "context"
+
+// Caller описывает аутентифицированного инициатора запроса. Администратор с пустым UserID -
+// служебный токен из конфигурации, который не привязан ни к одному пользователю.
+type Caller struct {
+	UserID  string
+	IsAdmin bool
+}
+
+type callerKey struct{}
+
+func WithCaller(ctx context.Context, caller Caller) context.Context {
+	return context.WithValue(ctx, callerKey{}, caller)
+}
+
+// CallerFrom возвращает инициатора запроса; второе значение false, если запрос не аутентифицирован.
+func CallerFrom(ctx context.Context) (Caller, bool) {
+	caller, ok := ctx.Value(callerKey{}).(Caller)
+	return caller, ok
+}
+
+// ActorID возвращает id пользователя, от имени которого выполняется запрос.
+// Для анонимных запросов и действий системы возвращается пустая строка.
+func ActorID(ctx context.Context) string {
+	caller, _ := CallerFrom(ctx)
+	return caller.UserID
+}
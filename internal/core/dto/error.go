@@ -5,4 +5,5 @@ import "PRReviewer/internal/core/enums"
 type ErrorResponse struct {
 	Code    enums.Code `json:"code"`
 	Message string     `json:"message"`
+	Details []string   `json:"details,omitempty"`
 }
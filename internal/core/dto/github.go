@@ -0,0 +1,23 @@
+package dto
+
+// GitHubPullRequestEvent — поля события pull_request из вебхука GitHub, которые нужны сервису.
+type GitHubPullRequestEvent struct {
+	Action      string            `json:"action"`
+	Number      int               `json:"number"`
+	PullRequest GitHubPullRequest `json:"pull_request"`
+	Sender      GitHubUser        `json:"sender"`
+}
+
+type GitHubPullRequest struct {
+	ID       int64       `json:"id"`
+	Number   int         `json:"number"`
+	Title    string      `json:"title"`
+	Draft    bool        `json:"draft"`
+	Merged   bool        `json:"merged"`
+	User     GitHubUser  `json:"user"`
+	MergedBy *GitHubUser `json:"merged_by"`
+}
+
+type GitHubUser struct {
+	Login string `json:"login"`
+}
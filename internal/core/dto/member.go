@@ -1,7 +1,9 @@
 package dto
 
 type TeamMember struct {
-	UserID   string `json:"user_id"`
-	Username string `json:"username"`
-	IsActive bool   `json:"is_active"`
+	UserID         string `json:"user_id"`
+	Username       string `json:"username"`
+	IsActive       bool   `json:"is_active"`
+	ReviewWeight   int    `json:"review_weight,omitempty" binding:"omitempty,min=1"`
+	MaxOpenReviews *int   `json:"max_open_reviews,omitempty" binding:"omitempty,min=0"`
 }
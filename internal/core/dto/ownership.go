@@ -0,0 +1,21 @@
+package dto
+
+type OwnerGroup struct {
+	Name    string   `json:"name" binding:"required,min=1"`
+	Members []string `json:"members" binding:"required,min=1"`
+}
+
+type OwnershipRule struct {
+	Pattern string   `json:"pattern" binding:"required,min=1"`
+	Owners  []string `json:"owners" binding:"required,min=1"`
+}
+
+type TeamOwnership struct {
+	TeamName string          `json:"team_name" binding:"required,min=1"`
+	Groups   []OwnerGroup    `json:"groups" binding:"dive"`
+	Rules    []OwnershipRule `json:"rules" binding:"dive"`
+}
+
+type GetTeamOwnershipRequest struct {
+	TeamName string `form:"team_name" binding:"required"`
+}
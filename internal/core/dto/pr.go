@@ -1,28 +1,125 @@
 package dto
 
+import (
+	"PRReviewer/internal/core/entities"
+	"PRReviewer/internal/core/enums"
+	"time"
+)
+
 type CreatePullRequest struct {
-	PullRequestID   string `json:"pull_request_id"`
-	PullRequestName string `json:"pull_request_name"`
-	AuthorID        string `json:"author_id"`
+	PullRequestID   string   `json:"pull_request_id"`
+	PullRequestName string   `json:"pull_request_name"`
+	AuthorID        string   `json:"author_id"`
+	ChangedFiles    []string `json:"changed_files,omitempty"`
+	Draft           bool     `json:"draft"`
+	TeamName        string   `json:"team_name,omitempty"`
+}
+
+type PullRequestTransition struct {
+	PullRequestID string `json:"pull_request_id" binding:"required"`
+}
+
+// SubmitReviewRequest сохраняет решение инициатора запроса. ReviewerID можно не передавать;
+// переданный должен совпадать с инициатором.
+type SubmitReviewRequest struct {
+	PullRequestID string               `json:"pull_request_id" binding:"required"`
+	ReviewerID    string               `json:"reviewer_id"`
+	Decision      enums.ReviewDecision `json:"decision" binding:"required,oneof=APPROVED CHANGES_REQUESTED COMMENTED"`
 }
 
+// MergePullRequest мерджит pr. Обойти политику мерджа через Override может только администратор;
+// инициатор определяется по аутентификации запроса, а не по телу.
 type MergePullRequest struct {
 	PullRequestID string `json:"pull_request_id"`
+	Override      bool   `json:"override"`
+	Reason        string `json:"reason"`
 }
 
 type ReassignReviewer struct {
 	PullRequestID string `json:"pull_request_id"`
 	OldUserID     string `json:"old_user_id"`
+	NewUserID     string `json:"new_user_id,omitempty"`
+}
+
+type PullRequestIDQuery struct {
+	PullRequestID string `form:"pull_request_id" binding:"required"`
+}
+
+type PullRequestHistoryResponse struct {
+	PullRequestID string             `json:"pull_request_id"`
+	Events        []entities.PREvent `json:"events"`
 }
 
 type PullRequestShort struct {
-	PullRequestID   string `json:"pull_request_id"`
-	PullRequestName string `json:"pull_request_name"`
-	AuthorID        string `json:"author_id"`
-	Status          string `json:"status"`
+	PullRequestID   string               `json:"pull_request_id"`
+	PullRequestName string               `json:"pull_request_name"`
+	AuthorID        string               `json:"author_id"`
+	Status          string               `json:"status"`
+	TeamName        string               `json:"team_name,omitempty"`
+	CreatedAt       time.Time            `json:"created_at"`
+	Decision        enums.ReviewDecision `json:"decision,omitempty"`
+}
+
+type ListPullRequestsQuery struct {
+	Status      enums.PRStatus `form:"status" binding:"omitempty,oneof=OPENED MERGED DRAFT CLOSED"`
+	AuthorID    string         `form:"author_id"`
+	ReviewerID  string         `form:"reviewer_id"`
+	TeamName    string         `form:"team_name"`
+	CreatedFrom *time.Time     `form:"created_from" time_format:"2006-01-02T15:04:05Z07:00"`
+	CreatedTo   *time.Time     `form:"created_to" time_format:"2006-01-02T15:04:05Z07:00"`
+	Limit       int            `form:"limit" binding:"omitempty,min=1,max=100"`
+	Cursor      string         `form:"cursor"`
+}
+
+type ListPullRequestsResponse struct {
+	PullRequests []PullRequestShort `json:"pull_requests"`
+	NextCursor   string             `json:"next_cursor,omitempty"`
+}
+
+type GetUserReviewsQuery struct {
+	UserID string         `form:"user_id" binding:"required"`
+	Status enums.PRStatus `form:"status" binding:"omitempty,oneof=OPENED MERGED DRAFT CLOSED"`
+	Limit  int            `form:"limit" binding:"omitempty,min=1,max=100"`
+	Cursor string         `form:"cursor"`
+}
+
+type ReviewStreamQuery struct {
+	UserID string `form:"user_id" binding:"required"`
 }
 
 type GetPullRequestResponse struct {
 	UserID       string             `json:"user_id"`
 	PullRequests []PullRequestShort `json:"pull_requests"`
+	NextCursor   string             `json:"next_cursor,omitempty"`
+}
+
+type ReassignmentResult struct {
+	PullRequestID string `json:"pull_request_id"`
+	OldUserID     string `json:"old_user_id"`
+	ReplacedBy    string `json:"replaced_by,omitempty"`
+	Reason        string `json:"reason,omitempty"`
+}
+
+type ReassignmentReport struct {
+	Reassigned []ReassignmentResult `json:"reassigned"`
+	Failed     []ReassignmentResult `json:"failed"`
+}
+
+func NewReassignmentReport() *ReassignmentReport {
+	return &ReassignmentReport{
+		Reassigned: []ReassignmentResult{},
+		Failed:     []ReassignmentResult{},
+	}
+}
+
+type ReviewerAssignment struct {
+	PullRequestID string
+	Reviewer      entities.Reviewer
+}
+
+// ReviewerReplacement замена ревьюера OldUserID на Reviewer в том же назначении pr.
+type ReviewerReplacement struct {
+	PullRequestID string
+	OldUserID     string
+	Reviewer      entities.Reviewer
 }
@@ -0,0 +1,39 @@
+package dto
+
+import "time"
+
+type StatsQuery struct {
+	TeamName string     `form:"team_name"`
+	From     *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To       *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+type ReviewerStats struct {
+	UserID         string `json:"user_id"`
+	Username       string `json:"username"`
+	Assigned       int    `json:"assigned"`
+	ReassignedAway int    `json:"reassigned_away"`
+	Approved       int    `json:"approved"`
+	OpenReviews    int    `json:"open"`
+}
+
+type ReviewerStatsResponse struct {
+	TeamName  string          `json:"team_name,omitempty"`
+	From      *time.Time      `json:"from,omitempty"`
+	To        *time.Time      `json:"to,omitempty"`
+	Reviewers []ReviewerStats `json:"reviewers"`
+}
+
+type TeamStats struct {
+	TeamName                 string   `json:"team_name"`
+	Created                  int      `json:"created"`
+	Merged                   int      `json:"merged"`
+	Open                     int      `json:"open"`
+	MedianTimeToMergeSeconds *float64 `json:"median_time_to_merge_seconds,omitempty"`
+}
+
+type TeamStatsResponse struct {
+	From  *time.Time  `json:"from,omitempty"`
+	To    *time.Time  `json:"to,omitempty"`
+	Teams []TeamStats `json:"teams"`
+}
@@ -1,10 +1,125 @@
 package dto
 
+import (
+	"PRReviewer/internal/core/entities"
+	"PRReviewer/internal/core/enums"
+	"time"
+)
+
+type TeamSettings struct {
+	ReviewerStrategy enums.ReviewerStrategy `json:"reviewer_strategy" binding:"omitempty,oneof=random round_robin least_loaded weighted"`
+	ReviewersPerPR   int                    `json:"reviewers_per_pr" binding:"omitempty,min=1,max=10"`
+	FallbackTeams    []string               `json:"fallback_teams"`
+	MergePolicy      *MergePolicy           `json:"merge_policy"`
+}
+
+type MergePolicy struct {
+	MinApprovals            int  `json:"min_approvals" binding:"min=0"`
+	BlockOnChangesRequested bool `json:"block_on_changes_requested"`
+	RequireAllApproved      bool `json:"require_all_approved"`
+}
+
 type Team struct {
-	TeamName string       `json:"team_name" binding:"required,min=1"`
-	Members  []TeamMember `json:"members" binding:"required"`
+	ID         string       `json:"-"`
+	TeamName   string       `json:"team_name" binding:"required,min=1"`
+	ArchivedAt *time.Time   `json:"archived_at,omitempty"`
+	Members    []TeamMember `json:"members" binding:"required,dive"`
+	TeamSettings
 }
 
 type GetTeamRequest struct {
 	TeamName string `form:"TeamName" binding:"required"`
 }
+
+type UpdateTeamSettingsRequest struct {
+	TeamName         string                  `json:"team_name" binding:"required,min=1"`
+	ReviewerStrategy *enums.ReviewerStrategy `json:"reviewer_strategy" binding:"omitempty,oneof=random round_robin least_loaded weighted"`
+	ReviewersPerPR   *int                    `json:"reviewers_per_pr" binding:"omitempty,min=1,max=10"`
+	FallbackTeams    []string                `json:"fallback_teams"`
+	MergePolicy      *MergePolicy            `json:"merge_policy"`
+}
+
+type DeactivateTeamUsersRequest struct {
+	TeamName string   `json:"team_name" binding:"required,min=1"`
+	UserIDs  []string `json:"user_ids" binding:"required_without=All"`
+	All      bool     `json:"all"`
+}
+
+type PRReassignmentReport struct {
+	PullRequestID string               `json:"pull_request_id"`
+	Reassigned    []ReassignmentResult `json:"reassigned"`
+	Failed        []ReassignmentResult `json:"failed"`
+}
+
+type DeactivateTeamUsersResponse struct {
+	TeamName     string                 `json:"team_name"`
+	Deactivated  []string               `json:"deactivated_user_ids"`
+	PullRequests []PRReassignmentReport `json:"pull_requests"`
+}
+
+type AddTeamMembersRequest struct {
+	TeamName string       `json:"team_name" binding:"required,min=1"`
+	Members  []TeamMember `json:"members" binding:"required,min=1,dive"`
+}
+
+type RemoveTeamMemberRequest struct {
+	TeamName string `json:"team_name" binding:"required,min=1"`
+	UserID   string `json:"user_id" binding:"required"`
+}
+
+type MoveTeamMemberRequest struct {
+	FromTeam     string `json:"from_team" binding:"required,min=1"`
+	ToTeam       string `json:"to_team" binding:"required,min=1"`
+	UserID       string `json:"user_id" binding:"required"`
+	ReviewWeight int    `json:"review_weight,omitempty" binding:"omitempty,min=1"`
+}
+
+type TeamMemberChangeResponse struct {
+	UserID       string                 `json:"user_id"`
+	FromTeam     string                 `json:"from_team"`
+	ToTeam       string                 `json:"to_team,omitempty"`
+	PullRequests []PRReassignmentReport `json:"pull_requests"`
+}
+
+type RenameTeamRequest struct {
+	TeamName string `json:"team_name" binding:"required,min=1"`
+	NewName  string `json:"new_name" binding:"required,min=1"`
+}
+
+type ArchiveTeamRequest struct {
+	TeamName string `json:"team_name" binding:"required,min=1"`
+}
+
+type DeleteTeamRequest struct {
+	TeamName string `json:"team_name" binding:"required,min=1"`
+	Force    bool   `json:"force"`
+}
+
+type TeamHistoryQuery struct {
+	TeamName string `form:"team_name" binding:"required"`
+}
+
+type TeamHistoryResponse struct {
+	TeamName string               `json:"team_name"`
+	Events   []entities.TeamEvent `json:"events"`
+}
+
+type ListTeamsQuery struct {
+	Name            string `form:"name"`
+	IncludeArchived bool   `form:"include_archived"`
+	Limit           int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Cursor          string `form:"cursor"`
+}
+
+type TeamSummary struct {
+	TeamName         string                 `json:"team_name"`
+	ReviewerStrategy enums.ReviewerStrategy `json:"reviewer_strategy"`
+	ReviewersPerPR   int                    `json:"reviewers_per_pr"`
+	MembersCount     int                    `json:"members_count"`
+	ArchivedAt       *time.Time             `json:"archived_at,omitempty"`
+}
+
+type ListTeamsResponse struct {
+	Teams      []TeamSummary `json:"teams"`
+	NextCursor string        `json:"next_cursor,omitempty"`
+}
@@ -1,10 +1,66 @@
 package dto
 
+import "PRReviewer/internal/core/entities"
+
 type SetUserActiveRequest struct {
 	UserID   string `json:"user_id"`
 	IsActive bool   `json:"is_active "`
 }
 
-type UserIDQuery struct {
-	UserID string `form:"user_id" binding:"required"`
+type SetPrimaryTeamRequest struct {
+	UserID   string `json:"user_id" binding:"required"`
+	TeamName string `json:"team_name" binding:"required,min=1"`
+}
+
+type UpdateUserRequest struct {
+	UserID              string  `json:"user_id" binding:"required"`
+	Username            *string `json:"username" binding:"omitempty,min=1"`
+	MaxOpenReviews      *int    `json:"max_open_reviews" binding:"omitempty,min=0"`
+	ResetMaxOpenReviews bool    `json:"reset_max_open_reviews"`
+}
+
+type SetAdminRequest struct {
+	UserID  string `json:"user_id" binding:"required"`
+	IsAdmin bool   `json:"is_admin"`
+}
+
+// SetGitHubLoginRequest привязывает github-логин к пользователю, пустой логин снимает привязку.
+type SetGitHubLoginRequest struct {
+	UserID      string `json:"user_id" binding:"required"`
+	GitHubLogin string `json:"github_login" binding:"max=39"`
+}
+
+type IssueTokenRequest struct {
+	UserID string `json:"user_id" binding:"required"`
+}
+
+// IssueTokenResponse содержит выданный токен. Сервис хранит только его хеш, поэтому показать токен повторно нельзя.
+type IssueTokenResponse struct {
+	UserID string `json:"user_id"`
+	Token  string `json:"token"`
+}
+
+type SetUserActiveResponse struct {
+	entities.User
+	Reassignment *ReassignmentReport `json:"reassignment,omitempty"`
+}
+
+type ListUsersQuery struct {
+	TeamName string `form:"team_name"`
+	Name     string `form:"name"`
+	IsActive *bool  `form:"is_active"`
+	Limit    int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Cursor   string `form:"cursor"`
+}
+
+type UserSummary struct {
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+	IsActive bool   `json:"is_active"`
+	TeamName string `json:"team_name,omitempty"`
+}
+
+type ListUsersResponse struct {
+	Users      []UserSummary `json:"users"`
+	NextCursor string        `json:"next_cursor,omitempty"`
 }
@@ -0,0 +1,51 @@
+package dto
+
+import (
+	"PRReviewer/internal/core/entities"
+	"PRReviewer/internal/core/enums"
+	"time"
+)
+
+type CreateWebhookSubscriptionRequest struct {
+	URL        string                   `json:"url" binding:"required,url"`
+	Secret     string                   `json:"secret" binding:"required,min=16"`
+	EventTypes []enums.WebhookEventType `json:"event_types" binding:"required,min=1,dive,oneof=pr.created reviewer.assigned reviewer.reassigned review.submitted pr.ready_for_review pr.merged pr.closed pr.reopened pr.converted_to_draft"`
+}
+
+type DeleteWebhookSubscriptionRequest struct {
+	SubscriptionID string `json:"subscription_id" binding:"required"`
+}
+
+type WebhookSubscriptionsResponse struct {
+	Subscriptions []entities.WebhookSubscription `json:"subscriptions"`
+}
+
+type DeadLettersQuery struct {
+	SubscriptionID string `form:"subscription_id"`
+	Limit          int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Cursor         string `form:"cursor"`
+}
+
+type DeadLettersResponse struct {
+	Deliveries []entities.WebhookDelivery `json:"deliveries"`
+	NextCursor string                     `json:"next_cursor,omitempty"`
+}
+
+type RetryDeadLettersRequest struct {
+	DeliveryIDs []int64 `json:"delivery_ids" binding:"required,min=1"`
+}
+
+type RetryDeadLettersResponse struct {
+	Retried int `json:"retried"`
+}
+
+// WebhookPayload тело уведомления, которое получает подписчик.
+type WebhookPayload struct {
+	Event         enums.WebhookEventType `json:"event"`
+	PullRequestID string                 `json:"pull_request_id"`
+	ActorID       string                 `json:"actor_id,omitempty"`
+	ReviewerID    string                 `json:"reviewer_id,omitempty"`
+	OldReviewerID string                 `json:"old_reviewer_id,omitempty"`
+	Decision      enums.ReviewDecision   `json:"decision,omitempty"`
+	OccurredAt    time.Time              `json:"occurred_at"`
+}
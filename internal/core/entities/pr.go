@@ -1,13 +1,29 @@
 package entities
 
-import "PRReviewer/internal/core/dto"
+import (
+	"PRReviewer/internal/core/enums"
+	"time"
+)
+
+type Reviewer struct {
+	UserID       string               `json:"user_id"`
+	Username     string               `json:"username"`
+	IsActive     bool                 `json:"is_active"`
+	FallbackTeam string               `json:"fallback_team,omitempty"`
+	Decision     enums.ReviewDecision `json:"decision"`
+	DecidedAt    *time.Time           `json:"decided_at,omitempty"`
+}
 
 type PullRequest struct {
-	ID        string           `json:"pull_request_id"`
-	Name      string           `json:"pull_request_name"`
-	AuthorID  string           `json:"author_id"`
-	Status    string           `json:"status"`
-	Reviewers []dto.TeamMember `json:"assigned_reviewers"`
+	ID        string     `json:"pull_request_id"`
+	Name      string     `json:"pull_request_name"`
+	AuthorID  string     `json:"author_id"`
+	Status    string     `json:"status"`
+	TeamName  string     `json:"team_name,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	MergedAt  *time.Time `json:"merged_at,omitempty"`
+	ClosedAt  *time.Time `json:"closed_at,omitempty"`
+	Reviewers []Reviewer `json:"assigned_reviewers"`
 }
 
 type PullRequestResponse struct {
@@ -22,3 +38,15 @@ type ReassignedPullRequest struct {
 	PR         PullRequest `json:"pr"`
 	ReplacedBY string      `json:"replaced_by"`
 }
+
+// PREvent запись журнала изменений pr. Пустой ActorID означает действие системы.
+type PREvent struct {
+	ID            int64                `json:"event_id"`
+	PullRequestID string               `json:"pull_request_id"`
+	Type          enums.PREventType    `json:"type"`
+	ActorID       string               `json:"actor_id,omitempty"`
+	ReviewerID    string               `json:"reviewer_id,omitempty"`
+	OldReviewerID string               `json:"old_reviewer_id,omitempty"`
+	Decision      enums.ReviewDecision `json:"decision,omitempty"`
+	CreatedAt     time.Time            `json:"created_at"`
+}
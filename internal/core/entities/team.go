@@ -0,0 +1,17 @@
+package entities
+
+import (
+	"PRReviewer/internal/core/enums"
+	"time"
+)
+
+// TeamEvent запись журнала изменений команды. Журнал переживает удаление команды.
+type TeamEvent struct {
+	ID          int64               `json:"event_id"`
+	TeamID      string              `json:"team_id"`
+	TeamName    string              `json:"team_name"`
+	OldTeamName string              `json:"old_team_name,omitempty"`
+	Type        enums.TeamEventType `json:"type"`
+	ActorID     string              `json:"actor_id,omitempty"`
+	CreatedAt   time.Time           `json:"created_at"`
+}
@@ -1,8 +1,18 @@
 package entities
 
+// User описывает пользователя. TeamName содержит основную команду, Teams все команды пользователя.
 type User struct {
-	ID       string `json:"user_id"`
-	Username string `json:"username"`
-	TeamName string `json:"team_name"`
-	IsActive bool   `json:"is_active"`
+	ID             string     `json:"user_id"`
+	Username       string     `json:"username"`
+	TeamName       string     `json:"team_name"`
+	Teams          []UserTeam `json:"teams"`
+	IsActive       bool       `json:"is_active"`
+	MaxOpenReviews *int       `json:"max_open_reviews,omitempty"`
+	IsAdmin        bool       `json:"is_admin"`
+	GitHubLogin    string     `json:"github_login,omitempty"`
+}
+
+type UserTeam struct {
+	TeamName  string `json:"team_name"`
+	IsPrimary bool   `json:"is_primary"`
 }
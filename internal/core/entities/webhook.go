@@ -0,0 +1,31 @@
+package entities
+
+import (
+	"PRReviewer/internal/core/enums"
+	"encoding/json"
+	"time"
+)
+
+// WebhookSubscription подписка на события. Секрет используется только для подписи и наружу не отдаётся.
+type WebhookSubscription struct {
+	ID         string                   `json:"subscription_id"`
+	URL        string                   `json:"url"`
+	Secret     string                   `json:"-"`
+	EventTypes []enums.WebhookEventType `json:"event_types"`
+	CreatedAt  time.Time                `json:"created_at"`
+}
+
+// WebhookDelivery одна отправка события одной подписке из исходящей очереди.
+type WebhookDelivery struct {
+	ID             int64                  `json:"delivery_id"`
+	SubscriptionID string                 `json:"subscription_id"`
+	URL            string                 `json:"url"`
+	Secret         string                 `json:"-"`
+	EventType      enums.WebhookEventType `json:"event_type"`
+	Payload        json.RawMessage        `json:"payload"`
+	Status         enums.DeliveryStatus   `json:"status"`
+	Attempts       int                    `json:"attempts"`
+	LastError      string                 `json:"last_error,omitempty"`
+	LastStatusCode int                    `json:"last_status_code,omitempty"`
+	CreatedAt      time.Time              `json:"created_at"`
+}
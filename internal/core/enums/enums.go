@@ -5,14 +5,98 @@ type PRStatus string
 const (
 	PRStatusOpened PRStatus = "OPENED"
 	PRStatusMerged PRStatus = "MERGED"
+	PRStatusDraft  PRStatus = "DRAFT"
+	PRStatusClosed PRStatus = "CLOSED"
+)
+
+type ReviewerStrategy string
+
+const (
+	StrategyRandom      ReviewerStrategy = "random"
+	StrategyRoundRobin  ReviewerStrategy = "round_robin"
+	StrategyLeastLoaded ReviewerStrategy = "least_loaded"
+	StrategyWeighted    ReviewerStrategy = "weighted"
+)
+
+type ReviewDecision string
+
+const (
+	DecisionPending          ReviewDecision = "PENDING"
+	DecisionApproved         ReviewDecision = "APPROVED"
+	DecisionChangesRequested ReviewDecision = "CHANGES_REQUESTED"
+	DecisionCommented        ReviewDecision = "COMMENTED"
+)
+
+type PREventType string
+
+const (
+	EventCreated          PREventType = "created"
+	EventReviewerAssigned PREventType = "reviewer_assigned"
+	EventReviewerReplaced PREventType = "reviewer_replaced"
+	EventReviewSubmitted  PREventType = "review_submitted"
+	EventReadyForReview   PREventType = "ready_for_review"
+	EventMerged           PREventType = "merged"
+	EventClosed           PREventType = "closed"
+	EventReopened         PREventType = "reopened"
+	EventConvertedToDraft PREventType = "converted_to_draft"
+)
+
+type WebhookEventType string
+
+const (
+	WebhookPRCreated          WebhookEventType = "pr.created"
+	WebhookReviewerAssigned   WebhookEventType = "reviewer.assigned"
+	WebhookReviewerReassigned WebhookEventType = "reviewer.reassigned"
+	WebhookReviewSubmitted    WebhookEventType = "review.submitted"
+	WebhookPRReadyForReview   WebhookEventType = "pr.ready_for_review"
+	WebhookPRMerged           WebhookEventType = "pr.merged"
+	WebhookPRClosed           WebhookEventType = "pr.closed"
+	WebhookPRReopened         WebhookEventType = "pr.reopened"
+	WebhookPRConvertedToDraft WebhookEventType = "pr.converted_to_draft"
+)
+
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "PENDING"
+	DeliveryDelivered DeliveryStatus = "DELIVERED"
+	DeliveryDead      DeliveryStatus = "DEAD"
+)
+
+type TeamEventType string
+
+const (
+	TeamEventCreated  TeamEventType = "created"
+	TeamEventRenamed  TeamEventType = "renamed"
+	TeamEventArchived TeamEventType = "archived"
+	TeamEventDeleted  TeamEventType = "deleted"
 )
 
 type Code string
 
 const (
-	CodeNotFound    Code = "NOT_FOUND"
-	CodeMerged      Code = "PR_MERGED"
-	CodeNoCandidate Code = "NO_CANDIDATE"
-	CodeNotAssigned Code = "NOT_ASSIGNED"
-	CodeTeamExists  Code = "TEAM_EXISTS"
+	CodeNotFound           Code = "NOT_FOUND"
+	CodeMerged             Code = "PR_MERGED"
+	CodeNoCandidate        Code = "NO_CANDIDATE"
+	CodeNotAssigned        Code = "NOT_ASSIGNED"
+	CodeTeamExists         Code = "TEAM_EXISTS"
+	CodeInvalidSettings    Code = "INVALID_SETTINGS"
+	CodeFallbackNotFound   Code = "FALLBACK_TEAM_NOT_FOUND"
+	CodeNotTeamMember      Code = "NOT_TEAM_MEMBER"
+	CodeAlreadyMember      Code = "ALREADY_MEMBER"
+	CodeLastTeam           Code = "LAST_TEAM"
+	CodeTeamArchived       Code = "TEAM_ARCHIVED"
+	CodeTeamHasOpenPRs     Code = "TEAM_HAS_OPEN_PRS"
+	CodeMergeBlocked       Code = "MERGE_POLICY_VIOLATION"
+	CodeForbidden          Code = "FORBIDDEN"
+	CodeUnauthorized       Code = "UNAUTHORIZED"
+	CodeDraft              Code = "PR_DRAFT"
+	CodeClosed             Code = "PR_CLOSED"
+	CodeInvalidStatus      Code = "INVALID_TRANSITION"
+	CodeInvalidReviewer    Code = "INVALID_REVIEWER"
+	CodeInvalidCursor      Code = "INVALID_CURSOR"
+	CodeUnknownGitHubLogin Code = "UNKNOWN_GITHUB_LOGIN"
+	CodeGitHubLoginTaken   Code = "GITHUB_LOGIN_TAKEN"
+	CodeInvalidSignature   Code = "INVALID_SIGNATURE"
+	CodeInvalidWebhookURL  Code = "INVALID_WEBHOOK_URL"
 )
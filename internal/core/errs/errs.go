@@ -2,6 +2,8 @@ package errs
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 )
 
 var ErrAlreadyExists = errors.New("сущность с такими параметрами уже существует")
@@ -9,3 +11,43 @@ var ErrNotFound = errors.New("сущность не найдена")
 var ErrNoReviewersAvailable = errors.New("нет доступных ревьюеров")
 var ErrUserNotAssigned = errors.New("пользователь не был назначен ревьюером")
 var ErrAlreadyMerged = errors.New("cannot reassign on merged PR")
+var ErrPRWithoutTeam = fmt.Errorf("%w: pr не привязан к команде", ErrNoReviewersAvailable)
+var ErrReviewersAtCapacity = fmt.Errorf("%w: все активные участники команды достигли лимита открытых ревью", ErrNoReviewersAvailable)
+var ErrInvalidFallbackTeam = errors.New("команда не может быть запасной сама для себя, название запасной команды не может быть пустым")
+var ErrFallbackTeamNotFound = fmt.Errorf("%w: запасная команда не существует или в архиве", ErrNotFound)
+var ErrInvalidOwnership = errors.New("владельцами могут быть только участники команды или объявленные группы вида @name")
+var ErrNotTeamMember = errors.New("пользователь не состоит в команде")
+var ErrAlreadyTeamMember = errors.New("пользователь уже состоит в команде")
+var ErrLastTeam = errors.New("нельзя исключить пользователя из его единственной команды, его можно только перевести в другую")
+var ErrTeamArchived = errors.New("команда в архиве")
+var ErrTeamHasOpenPRs = errors.New("у команды есть открытые pr")
+var ErrReviewOnClosedPR = errors.New("нельзя оставить ревью на pr, который не открыт")
+var ErrMergePolicyViolation = errors.New("pr не удовлетворяет политике мерджа команды")
+var ErrForbidden = errors.New("недостаточно прав для выполнения операции")
+var ErrUnauthorized = errors.New("запрос не аутентифицирован")
+var ErrInvalidTransition = errors.New("недопустимый переход статуса pr")
+var ErrPRIsDraft = fmt.Errorf("%w: pr является черновиком", ErrInvalidTransition)
+var ErrPRClosed = fmt.Errorf("%w: pr закрыт", ErrInvalidTransition)
+var ErrPRMerged = fmt.Errorf("%w: pr уже смерджен", ErrInvalidTransition)
+var ErrInvalidReviewer = errors.New("пользователь не может быть назначен ревьюером")
+var ErrReviewerInactive = fmt.Errorf("%w: пользователь неактивен", ErrInvalidReviewer)
+var ErrReviewerIsAuthor = fmt.Errorf("%w: пользователь является автором pr", ErrInvalidReviewer)
+var ErrReviewerAlreadyAssigned = fmt.Errorf("%w: пользователь уже назначен ревьюером", ErrInvalidReviewer)
+var ErrInvalidCursor = errors.New("некорректный курсор пагинации")
+var ErrStreamClosed = errors.New("поток событий закрыт: сервер останавливается")
+var ErrUnknownGitHubLogin = errors.New("github-логин не привязан ни к одному пользователю")
+var ErrGitHubLoginTaken = fmt.Errorf("%w: github-логин уже привязан к другому пользователю", ErrAlreadyExists)
+var ErrInvalidSignature = errors.New("подпись запроса не совпадает")
+var ErrInvalidWebhookURL = errors.New("адрес вебхука недопустим")
+
+type MergePolicyError struct {
+	Unmet []string
+}
+
+func (e *MergePolicyError) Error() string {
+	return ErrMergePolicyViolation.Error() + ": " + strings.Join(e.Unmet, "; ")
+}
+
+func (e *MergePolicyError) Is(target error) bool {
+	return target == ErrMergePolicyViolation
+}
@@ -0,0 +1,104 @@
+package selector
+
+import (
+	"PRReviewer/internal/core/dto"
+	"path"
+	"strings"
+)
+
+const groupPrefix = "@"
+
+func IsGroupRef(owner string) bool {
+	return strings.HasPrefix(owner, groupPrefix)
+}
+
+func GroupName(owner string) string {
+	return strings.TrimPrefix(owner, groupPrefix)
+}
+
+// MatchOwners возвращает владельцев каждого правила, которое оказалось последним совпавшим
+// хотя бы для одного из файлов, как в CODEOWNERS. Правила возвращаются в порядке их объявления.
+func MatchOwners(rules []dto.OwnershipRule, files []string) [][]string {
+	matched := make(map[int]bool)
+
+	for _, file := range files {
+		for i := len(rules) - 1; i >= 0; i-- {
+			if MatchPattern(rules[i].Pattern, file) {
+				matched[i] = true
+				break
+			}
+		}
+	}
+
+	owners := make([][]string, 0, len(matched))
+	for i, rule := range rules {
+		if matched[i] {
+			owners = append(owners, rule.Owners)
+		}
+	}
+	return owners
+}
+
+// MatchPattern поддерживает подмножество синтаксиса CODEOWNERS: '*' и '?' внутри сегмента пути,
+// '**' для любого числа сегментов, ведущий '/' для привязки к корню и завершающий '/' для каталогов.
+// Шаблон без '/' совпадает на любой глубине. Шаблон, совпавший с каталогом, покрывает всё его содержимое,
+// кроме случая, когда последний сегмент шаблона равен '*'.
+func MatchPattern(pattern, filePath string) bool {
+	pattern = strings.TrimSpace(pattern)
+	filePath = strings.Trim(strings.TrimSpace(filePath), "/")
+	if pattern == "" || filePath == "" {
+		return false
+	}
+
+	dirOnly := strings.HasSuffix(pattern, "/")
+	pattern = strings.TrimSuffix(pattern, "/")
+
+	anchored := strings.Contains(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+	if !anchored {
+		pattern = "**/" + pattern
+	}
+
+	patternSegments := strings.Split(pattern, "/")
+	pathSegments := strings.Split(filePath, "/")
+
+	if !dirOnly && matchSegments(patternSegments, pathSegments) {
+		return true
+	}
+
+	if patternSegments[len(patternSegments)-1] == "*" {
+		return false
+	}
+
+	for i := len(pathSegments) - 1; i > 0; i-- {
+		if matchSegments(patternSegments, pathSegments[:i]) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchSegments(pattern, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchSegments(pattern[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(segments) == 0 {
+		return false
+	}
+
+	ok, err := path.Match(pattern[0], segments[0])
+	if err != nil || !ok {
+		return false
+	}
+	return matchSegments(pattern[1:], segments[1:])
+}
@@ -0,0 +1,80 @@
+package selector_test
+
+import (
+	"PRReviewer/internal/core/dto"
+	"PRReviewer/internal/core/selector"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchPattern(t *testing.T) {
+	tests := []struct {
+		name     string
+		pattern  string
+		filePath string
+		want     bool
+	}{
+		{name: "extension at root", pattern: "*.go", filePath: "main.go", want: true},
+		{name: "extension at any depth", pattern: "*.go", filePath: "internal/app/app.go", want: true},
+		{name: "other extension", pattern: "*.go", filePath: "Readme.md", want: false},
+		{name: "question mark", pattern: "?.sql", filePath: "migrations/1.sql", want: true},
+		{name: "question mark matches one char", pattern: "?.sql", filePath: "migrations/12.sql", want: false},
+		{name: "anchored file", pattern: "/Makefile", filePath: "Makefile", want: true},
+		{name: "anchored file not nested", pattern: "/Makefile", filePath: "tools/Makefile", want: false},
+		{name: "anchored path", pattern: "internal/app/app.go", filePath: "internal/app/app.go", want: true},
+		{name: "path with slash is anchored", pattern: "app/app.go", filePath: "internal/app/app.go", want: false},
+		{name: "directory covers contents", pattern: "/migrations/", filePath: "migrations/7_new.up.sql", want: true},
+		{name: "directory covers nested contents", pattern: "/internal/", filePath: "internal/core/service/pr.go", want: true},
+		{name: "directory not a file", pattern: "docs/", filePath: "docs", want: false},
+		{name: "unanchored directory at any depth", pattern: "service/", filePath: "internal/core/service/pr.go", want: true},
+		{name: "directory without slash covers contents", pattern: "/internal/core", filePath: "internal/core/errs/errs.go", want: true},
+		{name: "single star stays in directory", pattern: "/internal/*", filePath: "internal/app.go", want: true},
+		{name: "single star does not descend", pattern: "/internal/*", filePath: "internal/app/app.go", want: false},
+		{name: "double star matches zero segments", pattern: "/internal/**/app.go", filePath: "internal/app.go", want: true},
+		{name: "double star matches many segments", pattern: "/internal/**/app.go", filePath: "internal/core/app/app.go", want: true},
+		{name: "leading double star", pattern: "**/testdata/**", filePath: "internal/core/testdata/case.json", want: true},
+		{name: "trailing double star", pattern: "/tests/**", filePath: "tests/integration/pr_test.go", want: true},
+		{name: "file path slashes are trimmed", pattern: "/Makefile", filePath: "/Makefile/", want: true},
+		{name: "empty pattern", pattern: "", filePath: "main.go", want: false},
+		{name: "empty file path", pattern: "*.go", filePath: "", want: false},
+		{name: "malformed pattern", pattern: "[.go", filePath: "[.go", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, selector.MatchPattern(tt.pattern, tt.filePath))
+		})
+	}
+}
+
+func TestMatchOwners(t *testing.T) {
+	rules := []dto.OwnershipRule{
+		{Pattern: "*", Owners: []string{"u1"}},
+		{Pattern: "*.go", Owners: []string{"u2"}},
+		{Pattern: "/migrations/", Owners: []string{"@db"}},
+		{Pattern: "/internal/core/", Owners: []string{"u3", "u4"}},
+	}
+
+	tests := []struct {
+		name  string
+		files []string
+		want  [][]string
+	}{
+		{name: "no files", files: nil, want: [][]string{}},
+		{name: "last matching rule wins", files: []string{"internal/core/service/pr.go"}, want: [][]string{{"u3", "u4"}}},
+		{name: "catch-all rule", files: []string{"Readme.md"}, want: [][]string{{"u1"}}},
+		{
+			name:  "rules in declaration order",
+			files: []string{"migrations/7_new.up.sql", "internal/app/app.go", "Readme.md"},
+			want:  [][]string{{"u1"}, {"u2"}, {"@db"}},
+		},
+		{name: "rule matched twice is returned once", files: []string{"main.go", "cmd/main.go"}, want: [][]string{{"u2"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, selector.MatchOwners(rules, tt.files))
+		})
+	}
+}
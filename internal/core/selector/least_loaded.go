@@ -0,0 +1,29 @@
+package selector
+
+import (
+	"math/rand"
+	"sort"
+)
+
+// LeastLoadedSelector выбирает кандидатов с наименьшим числом открытых ревью.
+// При равной нагрузке порядок случайный, чтобы не назначать всегда одних и тех же.
+type LeastLoadedSelector struct{}
+
+func NewLeastLoadedSelector() *LeastLoadedSelector {
+	return &LeastLoadedSelector{}
+}
+
+func (s *LeastLoadedSelector) Select(candidates []Candidate, limit int) []string {
+	ordered := make([]Candidate, len(candidates))
+	copy(ordered, candidates)
+
+	rand.Shuffle(len(ordered), func(i, j int) {
+		ordered[i], ordered[j] = ordered[j], ordered[i]
+	})
+
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].OpenReviews < ordered[j].OpenReviews
+	})
+
+	return candidateIDs(ordered, limit)
+}
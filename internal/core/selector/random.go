@@ -0,0 +1,20 @@
+package selector
+
+import "math/rand"
+
+type RandomSelector struct{}
+
+func NewRandomSelector() *RandomSelector {
+	return &RandomSelector{}
+}
+
+func (s *RandomSelector) Select(candidates []Candidate, limit int) []string {
+	shuffled := make([]Candidate, len(candidates))
+	copy(shuffled, candidates)
+
+	rand.Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+
+	return candidateIDs(shuffled, limit)
+}
@@ -0,0 +1,26 @@
+package selector
+
+import "sort"
+
+// RoundRobinSelector назначает кандидатов, которых дольше всех не назначали ревьюерами,
+// а при равенстве - по порядку id. Очередь строится по журналу назначений из базы,
+// поэтому она общая для всех реплик и переживает перезапуск.
+type RoundRobinSelector struct{}
+
+func NewRoundRobinSelector() *RoundRobinSelector {
+	return &RoundRobinSelector{}
+}
+
+func (s *RoundRobinSelector) Select(candidates []Candidate, limit int) []string {
+	ordered := make([]Candidate, len(candidates))
+	copy(ordered, candidates)
+
+	sort.Slice(ordered, func(i, j int) bool {
+		if ordered[i].LastAssigned != ordered[j].LastAssigned {
+			return ordered[i].LastAssigned < ordered[j].LastAssigned
+		}
+		return ordered[i].UserID < ordered[j].UserID
+	})
+
+	return candidateIDs(ordered, limit)
+}
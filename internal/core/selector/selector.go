@@ -0,0 +1,71 @@
+package selector
+
+import (
+	"PRReviewer/internal/core/enums"
+	"sync"
+)
+
+// Candidate описывает участника команды, из которого стратегия выбирает ревьюеров.
+// LastAssigned - порядковый номер последнего назначения кандидата ревьюером, ноль если назначений не было.
+type Candidate struct {
+	UserID       string
+	Weight       int
+	OpenReviews  int
+	LastAssigned int64
+}
+
+type ReviewerSelector interface {
+	Select(candidates []Candidate, limit int) []string
+}
+
+type Registry struct {
+	mu        sync.RWMutex
+	selectors map[enums.ReviewerStrategy]ReviewerSelector
+	fallback  enums.ReviewerStrategy
+}
+
+func NewRegistry() *Registry {
+	r := &Registry{
+		selectors: make(map[enums.ReviewerStrategy]ReviewerSelector),
+		fallback:  enums.StrategyRandom,
+	}
+
+	r.Register(enums.StrategyRandom, NewRandomSelector())
+	r.Register(enums.StrategyRoundRobin, NewRoundRobinSelector())
+	r.Register(enums.StrategyLeastLoaded, NewLeastLoadedSelector())
+	r.Register(enums.StrategyWeighted, NewWeightedSelector())
+
+	return r
+}
+
+func (r *Registry) Register(strategy enums.ReviewerStrategy, selector ReviewerSelector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.selectors[strategy] = selector
+}
+
+func (r *Registry) Has(strategy enums.ReviewerStrategy) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, ok := r.selectors[strategy]
+	return ok
+}
+
+// Get возвращает стратегию по имени, а для пустого или неизвестного имени - стратегию по умолчанию.
+func (r *Registry) Get(strategy enums.ReviewerStrategy) ReviewerSelector {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if selector, ok := r.selectors[strategy]; ok {
+		return selector
+	}
+	return r.selectors[r.fallback]
+}
+
+func candidateIDs(candidates []Candidate, limit int) []string {
+	count := min(limit, len(candidates))
+	ids := make([]string, count)
+	for i, candidate := range candidates[:count] {
+		ids[i] = candidate.UserID
+	}
+	return ids
+}
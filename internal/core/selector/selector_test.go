@@ -0,0 +1,228 @@
+package selector_test
+
+import (
+	"PRReviewer/internal/core/enums"
+	"PRReviewer/internal/core/selector"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func candidates(ids ...string) []selector.Candidate {
+	result := make([]selector.Candidate, len(ids))
+	for i, id := range ids {
+		result[i] = selector.Candidate{UserID: id}
+	}
+	return result
+}
+
+func TestSelectors_ShouldReturnDistinctCandidatesUpToLimit(t *testing.T) {
+	strategies := map[string]selector.ReviewerSelector{
+		"random":       selector.NewRandomSelector(),
+		"round_robin":  selector.NewRoundRobinSelector(),
+		"least_loaded": selector.NewLeastLoadedSelector(),
+		"weighted":     selector.NewWeightedSelector(),
+	}
+
+	tests := []struct {
+		name       string
+		candidates []selector.Candidate
+		limit      int
+		wantLen    int
+	}{
+		{name: "no candidates", candidates: nil, limit: 2, wantLen: 0},
+		{name: "zero limit", candidates: candidates("u1", "u2"), limit: 0, wantLen: 0},
+		{name: "fewer candidates than limit", candidates: candidates("u1", "u2"), limit: 3, wantLen: 2},
+		{name: "more candidates than limit", candidates: candidates("u1", "u2", "u3", "u4"), limit: 2, wantLen: 2},
+	}
+
+	for strategy, reviewerSelector := range strategies {
+		for _, tt := range tests {
+			t.Run(strategy+"/"+tt.name, func(t *testing.T) {
+				picked := reviewerSelector.Select(tt.candidates, tt.limit)
+
+				assert.Len(t, picked, tt.wantLen)
+				seen := make(map[string]bool)
+				for _, id := range picked {
+					assert.Contains(t, tt.candidates, selector.Candidate{UserID: id})
+					assert.False(t, seen[id], "кандидат %s выбран дважды", id)
+					seen[id] = true
+				}
+			})
+		}
+	}
+}
+
+func TestRoundRobinSelector(t *testing.T) {
+	tests := []struct {
+		name       string
+		candidates []selector.Candidate
+		limit      int
+		want       []string
+	}{
+		{
+			name:       "never assigned go by id",
+			candidates: candidates("u3", "u1", "u2"),
+			limit:      2,
+			want:       []string{"u1", "u2"},
+		},
+		{
+			name: "least recently assigned first",
+			candidates: []selector.Candidate{
+				{UserID: "u1", LastAssigned: 7},
+				{UserID: "u2", LastAssigned: 3},
+				{UserID: "u3", LastAssigned: 5},
+			},
+			limit: 2,
+			want:  []string{"u2", "u3"},
+		},
+		{
+			name: "never assigned before assigned",
+			candidates: []selector.Candidate{
+				{UserID: "u1", LastAssigned: 1},
+				{UserID: "u2", LastAssigned: 2},
+				{UserID: "u3"},
+			},
+			limit: 2,
+			want:  []string{"u3", "u1"},
+		},
+		{
+			name: "load is ignored",
+			candidates: []selector.Candidate{
+				{UserID: "u1", OpenReviews: 5},
+				{UserID: "u2", OpenReviews: 0, LastAssigned: 1},
+			},
+			limit: 1,
+			want:  []string{"u1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, selector.NewRoundRobinSelector().Select(tt.candidates, tt.limit))
+		})
+	}
+}
+
+func TestRoundRobinSelector_ShouldNotDependOnInstance(t *testing.T) {
+	team := []selector.Candidate{
+		{UserID: "u1", LastAssigned: 4},
+		{UserID: "u2", LastAssigned: 2},
+		{UserID: "u3", LastAssigned: 3},
+	}
+
+	first := selector.NewRoundRobinSelector().Select(team, 1)
+	second := selector.NewRoundRobinSelector().Select(team, 1)
+
+	assert.Equal(t, []string{"u2"}, first)
+	assert.Equal(t, first, second)
+}
+
+func TestLeastLoadedSelector(t *testing.T) {
+	tests := []struct {
+		name       string
+		candidates []selector.Candidate
+		limit      int
+		want       []string
+	}{
+		{
+			name: "lowest load first",
+			candidates: []selector.Candidate{
+				{UserID: "u1", OpenReviews: 3},
+				{UserID: "u2", OpenReviews: 0},
+				{UserID: "u3", OpenReviews: 1},
+			},
+			limit: 2,
+			want:  []string{"u2", "u3"},
+		},
+		{
+			name: "recent assignment is ignored",
+			candidates: []selector.Candidate{
+				{UserID: "u1", OpenReviews: 2},
+				{UserID: "u2", OpenReviews: 1, LastAssigned: 10},
+			},
+			limit: 1,
+			want:  []string{"u2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, selector.NewLeastLoadedSelector().Select(tt.candidates, tt.limit))
+		})
+	}
+}
+
+func TestLeastLoadedSelector_WhenLoadsEqual_ShouldPickAnyOfThem(t *testing.T) {
+	team := []selector.Candidate{
+		{UserID: "u1", OpenReviews: 1},
+		{UserID: "u2", OpenReviews: 1},
+		{UserID: "u3", OpenReviews: 4},
+	}
+
+	picked := make(map[string]bool)
+	for range 200 {
+		ids := selector.NewLeastLoadedSelector().Select(team, 1)
+		picked[ids[0]] = true
+	}
+
+	assert.Equal(t, map[string]bool{"u1": true, "u2": true}, picked)
+}
+
+func TestRandomSelector_ShouldPickEveryCandidate(t *testing.T) {
+	team := candidates("u1", "u2", "u3")
+
+	picked := make(map[string]bool)
+	for range 200 {
+		ids := selector.NewRandomSelector().Select(team, 1)
+		picked[ids[0]] = true
+	}
+
+	assert.Len(t, picked, len(team))
+}
+
+func TestWeightedSelector_ShouldPreferHeavierCandidates(t *testing.T) {
+	team := []selector.Candidate{
+		{UserID: "heavy", Weight: 9},
+		{UserID: "light", Weight: 1},
+	}
+
+	const rounds = 2000
+	heavy := 0
+	for range rounds {
+		if selector.NewWeightedSelector().Select(team, 1)[0] == "heavy" {
+			heavy++
+		}
+	}
+
+	// Ожидаемая доля 0.9, порог оставляет запас в десятки стандартных отклонений.
+	assert.Greater(t, heavy, rounds*3/4)
+	assert.Less(t, heavy, rounds)
+}
+
+func TestWeightedSelector_WhenWeightNotPositive_ShouldTreatAsOne(t *testing.T) {
+	team := []selector.Candidate{
+		{UserID: "zero", Weight: 0},
+		{UserID: "negative", Weight: -5},
+	}
+
+	picked := make(map[string]bool)
+	for range 200 {
+		ids := selector.NewWeightedSelector().Select(team, 1)
+		picked[ids[0]] = true
+	}
+
+	assert.Len(t, picked, len(team))
+}
+
+func TestRegistry_Get(t *testing.T) {
+	registry := selector.NewRegistry()
+
+	assert.IsType(t, &selector.RoundRobinSelector{}, registry.Get(enums.StrategyRoundRobin))
+	assert.IsType(t, &selector.LeastLoadedSelector{}, registry.Get(enums.StrategyLeastLoaded))
+	assert.IsType(t, &selector.WeightedSelector{}, registry.Get(enums.StrategyWeighted))
+	assert.IsType(t, &selector.RandomSelector{}, registry.Get(enums.StrategyRandom))
+	assert.IsType(t, &selector.RandomSelector{}, registry.Get(""))
+	assert.IsType(t, &selector.RandomSelector{}, registry.Get("unknown"))
+	assert.False(t, registry.Has("unknown"))
+}
@@ -0,0 +1,42 @@
+package selector
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// WeightedSelector выбирает ревьюеров случайно без повторов, с вероятностью пропорциональной весу
+// (алгоритм Efraimidis-Spirakis). Вес меньше единицы считается равным единице.
+type WeightedSelector struct{}
+
+func NewWeightedSelector() *WeightedSelector {
+	return &WeightedSelector{}
+}
+
+func (s *WeightedSelector) Select(candidates []Candidate, limit int) []string {
+	type keyed struct {
+		candidate Candidate
+		key       float64
+	}
+
+	items := make([]keyed, len(candidates))
+	for i, candidate := range candidates {
+		weight := max(candidate.Weight, 1)
+		items[i] = keyed{
+			candidate: candidate,
+			key:       math.Pow(rand.Float64(), 1/float64(weight)),
+		}
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].key > items[j].key
+	})
+
+	ordered := make([]Candidate, len(items))
+	for i, item := range items {
+		ordered[i] = item.candidate
+	}
+
+	return candidateIDs(ordered, limit)
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"PRReviewer/internal/core/auth"
+	"PRReviewer/internal/core/dto"
+	"PRReviewer/internal/core/entities"
+	"PRReviewer/internal/core/errs"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+)
+
+const tokenBytes = 32
+
+type TokenRepo interface {
+	AddToken(ctx context.Context, userID string, tokenHash string) error
+	GetTokenOwner(ctx context.Context, tokenHash string) (*entities.User, error)
+}
+
+// AuthService определяет инициатора запроса по bearer-токену. Токены пользователей выдаёт администратор,
+// в базе хранится только их sha256. adminToken из конфигурации даёт права администратора без привязки
+// к пользователю и нужен, чтобы выдать первые токены; пустой adminToken выключает его.
+type AuthService struct {
+	tokenRepo  TokenRepo
+	userRepo   UserRepo
+	adminToken string
+	log        *slog.Logger
+}
+
+func NewAuthService(tokenRepo TokenRepo, userRepo UserRepo, adminToken string, log *slog.Logger) *AuthService {
+	return &AuthService{tokenRepo: tokenRepo, userRepo: userRepo, adminToken: adminToken, log: log}
+}
+
+func (s *AuthService) Authenticate(ctx context.Context, token string) (auth.Caller, error) {
+	ctx, span := tracer.Start(ctx, "AuthService.Authenticate")
+	defer span.End()
+
+	if token == "" {
+		return auth.Caller{}, errs.ErrUnauthorized
+	}
+
+	if s.adminToken != "" && subtle.ConstantTimeCompare([]byte(token), []byte(s.adminToken)) == 1 {
+		return auth.Caller{IsAdmin: true}, nil
+	}
+
+	user, err := s.tokenRepo.GetTokenOwner(ctx, hashToken(token))
+	if err != nil {
+		if errors.Is(err, errs.ErrNotFound) {
+			s.log.Warn("неизвестный токен")
+			return auth.Caller{}, errs.ErrUnauthorized
+		}
+		s.log.Error("не удалось найти владельца токена", "error", err)
+		return auth.Caller{}, err
+	}
+
+	return auth.Caller{UserID: user.ID, IsAdmin: user.IsAdmin}, nil
+}
+
+// IssueToken выдаёт пользователю новый токен. Ранее выданные токены продолжают действовать.
+func (s *AuthService) IssueToken(ctx context.Context, req dto.IssueTokenRequest) (*dto.IssueTokenResponse, error) {
+	ctx, span := tracer.Start(ctx, "AuthService.IssueToken")
+	defer span.End()
+
+	exists, err := s.userRepo.IsUserExist(ctx, req.UserID)
+	if err != nil {
+		s.log.Error("не удалось проверить существование пользователя", "error", err)
+		return nil, err
+	}
+	if !exists {
+		s.log.Error("пользователь не существует", "error", errs.ErrNotFound, "user ID", req.UserID)
+		return nil, errs.ErrNotFound
+	}
+
+	raw := make([]byte, tokenBytes)
+	if _, err := rand.Read(raw); err != nil {
+		s.log.Error("не удалось сгенерировать токен", "error", err)
+		return nil, err
+	}
+	token := hex.EncodeToString(raw)
+
+	err = s.tokenRepo.AddToken(ctx, req.UserID, hashToken(token))
+	if err != nil {
+		s.log.Error("не удалось сохранить токен", "error", err, "user ID", req.UserID)
+		return nil, err
+	}
+
+	s.log.Info("выдан токен", "user ID", req.UserID, "actor", auth.ActorID(ctx))
+	return &dto.IssueTokenResponse{UserID: req.UserID, Token: token}, nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
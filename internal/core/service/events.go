@@ -0,0 +1,78 @@
+package service
+
+import (
+	"PRReviewer/internal/core/dto"
+	"PRReviewer/internal/core/entities"
+	"PRReviewer/internal/core/enums"
+	"PRReviewer/internal/core/errs"
+	"context"
+)
+
+// GetHistory возвращает журнал изменений pr в порядке их записи.
+func (s *PullRequestService) GetHistory(ctx context.Context, prID string) (*dto.PullRequestHistoryResponse, error) {
+	ctx, span := tracer.Start(ctx, "PullRequestService.GetHistory")
+	defer span.End()
+
+	exists, err := s.prRepo.IsPRExists(ctx, prID)
+	if err != nil {
+		s.log.Error("неудалось проверить существование pr", "error", err)
+		return nil, err
+	}
+	if !exists {
+		s.log.Error("pr не существует", "error", errs.ErrNotFound, "pull request ID", prID)
+		return nil, errs.ErrNotFound
+	}
+
+	events, err := s.prRepo.GetPREvents(ctx, prID)
+	if err != nil {
+		s.log.Error("не удалось получить историю pr", "error", err, "pull request ID", prID)
+		return nil, err
+	}
+
+	return &dto.PullRequestHistoryResponse{PullRequestID: prID, Events: events}, nil
+}
+
+// recordEvents дописывает события в журнал pr, ставит соответствующие вебхуки в исходящую очередь
+// и после коммита рассылает события ревью подписчикам потоков.
+// Вызывается в той же транзакции, что и само изменение, поэтому откат не оставляет ни событий, ни уведомлений.
+func (s *PullRequestService) recordEvents(ctx context.Context, events ...entities.PREvent) error {
+	err := s.prRepo.AddPREvents(ctx, events)
+	if err != nil {
+		s.log.Error("не удалось записать события pr", "error", err)
+		return err
+	}
+
+	err = s.prRepo.EnqueueWebhookEvents(ctx, webhookPayloads(events))
+	if err != nil {
+		s.log.Error("не удалось поставить вебхуки в очередь", "error", err)
+		return err
+	}
+
+	return s.publishReviewEvents(ctx, events)
+}
+
+func assignmentEvents(prID string, actorID string, reviewers []entities.Reviewer) []entities.PREvent {
+	events := make([]entities.PREvent, len(reviewers))
+	for i, reviewer := range reviewers {
+		events[i] = entities.PREvent{
+			PullRequestID: prID,
+			Type:          enums.EventReviewerAssigned,
+			ActorID:       actorID,
+			ReviewerID:    reviewer.UserID,
+		}
+	}
+	return events
+}
+
+// replacementEvents описывает замену ревьюера: первый новый ревьюер занимает место старого,
+// остальные добраны до настроенного числа ревьюеров.
+func replacementEvents(prID string, actorID string, oldUserID string, newReviewers []entities.Reviewer) []entities.PREvent {
+	events := []entities.PREvent{{
+		PullRequestID: prID,
+		Type:          enums.EventReviewerReplaced,
+		ActorID:       actorID,
+		ReviewerID:    newReviewers[0].UserID,
+		OldReviewerID: oldUserID,
+	}}
+	return append(events, assignmentEvents(prID, actorID, newReviewers[1:])...)
+}
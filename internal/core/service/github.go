@@ -0,0 +1,151 @@
+package service
+
+import (
+	"PRReviewer/internal/core/auth"
+	"PRReviewer/internal/core/dto"
+	"PRReviewer/internal/core/entities"
+	"PRReviewer/internal/core/errs"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+)
+
+// GitHub-действия pull_request, которые переносятся на pr сервиса.
+const (
+	GitHubActionOpened           = "opened"
+	GitHubActionClosed           = "closed"
+	GitHubActionReopened         = "reopened"
+	GitHubActionConvertedToDraft = "converted_to_draft"
+	GitHubActionReadyForReview   = "ready_for_review"
+)
+
+// gitHubMergeReason сохраняется причиной обхода политики, если pr смерджен в GitHub с невыполненными условиями.
+const gitHubMergeReason = "смерджен в github"
+
+type PullRequestActions interface {
+	CreateUpstreamPullRequest(ctx context.Context, pr dto.CreatePullRequest) (*entities.PullRequest, error)
+	MergeUpstreamPullRequest(ctx context.Context, request dto.MergePullRequest) (*entities.PullRequest, error)
+	ClosePullRequest(ctx context.Context, request dto.PullRequestTransition) (*entities.PullRequest, error)
+	ReopenPullRequest(ctx context.Context, request dto.PullRequestTransition) (*entities.PullRequest, error)
+	ConvertToDraft(ctx context.Context, request dto.PullRequestTransition) (*entities.PullRequest, error)
+	MarkReadyForReview(ctx context.Context, request dto.PullRequestTransition) (*entities.PullRequest, error)
+}
+
+// GitHubService переносит события pull_request из GitHub на pr сервиса.
+// Пользователи сопоставляются по github-логину, привязанному через /admin/users/setGitHubLogin.
+type GitHubService struct {
+	prActions PullRequestActions
+	userRepo  UserRepo
+	log       *slog.Logger
+}
+
+func NewGitHubService(prActions PullRequestActions, userRepo UserRepo, log *slog.Logger) *GitHubService {
+	return &GitHubService{prActions: prActions, userRepo: userRepo, log: log}
+}
+
+// GitHubPullRequestID возвращает id pr в сервисе для pr GitHub. Используется глобальный id GitHub,
+// а не номер, чтобы pr из разных репозиториев не совпадали.
+func GitHubPullRequestID(pr dto.GitHubPullRequest) string {
+	return "gh-" + strconv.FormatInt(pr.ID, 10)
+}
+
+// HandlePullRequestEvent применяет событие к pr. Возвращает nil без ошибки, если событие не меняет pr:
+// действие не поддерживается, pr уже создан при повторной доставке opened или уже закрыт при повторной доставке closed.
+// closed без мерджа закрывает pr, иначе reopened не на что было бы применить.
+// Открытие и мердж в GitHub уже произошли, поэтому pr без доступных ревьюеров сохраняется без них,
+// а политика команды не блокирует мердж: невыполненные условия сохраняются как обход политики.
+// Подпись вебхука уже проверена, поэтому действия выполняются от имени пользователя, привязанного к логину
+// инициатора в GitHub, а не того, кто аутентифицирован в самом http-запросе.
+func (s *GitHubService) HandlePullRequestEvent(ctx context.Context, event dto.GitHubPullRequestEvent) (*entities.PullRequest, error) {
+	ctx, span := tracer.Start(ctx, "GitHubService.HandlePullRequestEvent")
+	defer span.End()
+
+	prID := GitHubPullRequestID(event.PullRequest)
+	switch event.Action {
+	case GitHubActionOpened:
+		authorID, err := s.resolveLogin(ctx, event.PullRequest.User.Login)
+		if err != nil {
+			return nil, err
+		}
+
+		pr, err := s.prActions.CreateUpstreamPullRequest(auth.WithCaller(ctx, auth.Caller{UserID: authorID}), dto.CreatePullRequest{
+			PullRequestID:   prID,
+			PullRequestName: event.PullRequest.Title,
+			AuthorID:        authorID,
+			Draft:           event.PullRequest.Draft,
+		})
+		if errors.Is(err, errs.ErrAlreadyExists) {
+			s.log.Info("pr из github уже создан", "pull request ID", prID)
+			return nil, nil
+		}
+		return pr, err
+
+	case GitHubActionClosed:
+		if !event.PullRequest.Merged {
+			pr, err := s.prActions.ClosePullRequest(s.asSender(ctx, event.Sender.Login), dto.PullRequestTransition{PullRequestID: prID})
+			if errors.Is(err, errs.ErrPRClosed) {
+				s.log.Info("pr из github уже закрыт", "pull request ID", prID)
+				return nil, nil
+			}
+			return pr, err
+		}
+
+		var mergedBy string
+		if event.PullRequest.MergedBy != nil {
+			mergedBy = event.PullRequest.MergedBy.Login
+		}
+		return s.prActions.MergeUpstreamPullRequest(s.asSender(ctx, mergedBy), dto.MergePullRequest{
+			PullRequestID: prID,
+			Reason:        gitHubMergeReason,
+		})
+
+	case GitHubActionReopened:
+		return s.prActions.ReopenPullRequest(s.asSender(ctx, event.Sender.Login), dto.PullRequestTransition{PullRequestID: prID})
+
+	case GitHubActionConvertedToDraft:
+		return s.prActions.ConvertToDraft(s.asSender(ctx, event.Sender.Login), dto.PullRequestTransition{PullRequestID: prID})
+
+	case GitHubActionReadyForReview:
+		return s.prActions.MarkReadyForReview(s.asSender(ctx, event.Sender.Login), dto.PullRequestTransition{PullRequestID: prID})
+
+	default:
+		return nil, nil
+	}
+}
+
+// asSender делает инициатором действия пользователя с github-логином отправителя события.
+// Неизвестный логин даёт анонимного инициатора, права администратора через GitHub не передаются.
+func (s *GitHubService) asSender(ctx context.Context, login string) context.Context {
+	var userID string
+	if login != "" {
+		userID = s.optionalLogin(ctx, login)
+	}
+	return auth.WithCaller(ctx, auth.Caller{UserID: userID})
+}
+
+func (s *GitHubService) resolveLogin(ctx context.Context, login string) (string, error) {
+	userID, err := s.userRepo.GetUserIDByGitHubLogin(ctx, login)
+	if err != nil {
+		if errors.Is(err, errs.ErrNotFound) {
+			s.log.Error("github-логин не привязан", "error", errs.ErrUnknownGitHubLogin, "login", login)
+			return "", fmt.Errorf("%w: %s", errs.ErrUnknownGitHubLogin, login)
+		}
+		s.log.Error("не удалось найти пользователя по github-логину", "error", err, "login", login)
+		return "", err
+	}
+	return userID, nil
+}
+
+// optionalLogin нужен для инициатора действия: он пишется только в журнал, поэтому неизвестный логин не ошибка.
+func (s *GitHubService) optionalLogin(ctx context.Context, login string) string {
+	userID, err := s.userRepo.GetUserIDByGitHubLogin(ctx, login)
+	if err != nil {
+		if !errors.Is(err, errs.ErrNotFound) {
+			s.log.Error("не удалось найти пользователя по github-логину", "error", err, "login", login)
+		}
+		return ""
+	}
+	return userID
+}
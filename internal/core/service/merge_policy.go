@@ -0,0 +1,44 @@
+package service
+
+import (
+	"PRReviewer/internal/core/dto"
+	"PRReviewer/internal/core/entities"
+	"PRReviewer/internal/core/enums"
+	"fmt"
+)
+
+// defaultMergePolicy получают новые команды без явной политики и pr, у которых нет команды.
+var defaultMergePolicy = dto.MergePolicy{BlockOnChangesRequested: true}
+
+// unmetMergeConditions возвращает описания невыполненных условий политики мерджа.
+// Неактивные ревьюеры не учитываются в требовании одобрения всеми назначенными.
+func unmetMergeConditions(policy dto.MergePolicy, reviewers []entities.Reviewer) []string {
+	var unmet []string
+
+	approvals := 0
+	var changesRequested, notApproved []string
+	for _, reviewer := range reviewers {
+		switch reviewer.Decision {
+		case enums.DecisionApproved:
+			approvals++
+			continue
+		case enums.DecisionChangesRequested:
+			changesRequested = append(changesRequested, reviewer.UserID)
+		}
+		if reviewer.IsActive {
+			notApproved = append(notApproved, reviewer.UserID)
+		}
+	}
+
+	if approvals < policy.MinApprovals {
+		unmet = append(unmet, fmt.Sprintf("нужно одобрений: %d, получено: %d", policy.MinApprovals, approvals))
+	}
+	if policy.BlockOnChangesRequested && len(changesRequested) > 0 {
+		unmet = append(unmet, fmt.Sprintf("запрошены изменения: %v", changesRequested))
+	}
+	if policy.RequireAllApproved && len(notApproved) > 0 {
+		unmet = append(unmet, fmt.Sprintf("не одобрили назначенные ревьюеры: %v", notApproved))
+	}
+
+	return unmet
+}
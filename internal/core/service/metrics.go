@@ -0,0 +1,11 @@
+package service
+
+// MetricsRecorder принимает доменные события для метрик. teamName — команда, которая ревьюит pr.
+// Операции с собственной транзакцией сообщаются после коммита; массовое переназначение идёт
+// в транзакции вызывающей стороны и сообщается по своему завершению.
+type MetricsRecorder interface {
+	PRCreated(teamName string)
+	PRMerged(teamName string)
+	ReviewerReassigned(teamName string)
+	NoCandidate(teamName string)
+}
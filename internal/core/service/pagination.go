@@ -0,0 +1,11 @@
+package service
+
+const defaultPageLimit = 50
+
+// pageLimit подставляет размер страницы по умолчанию, если клиент его не указал.
+func pageLimit(limit int) int {
+	if limit <= 0 {
+		return defaultPageLimit
+	}
+	return limit
+}
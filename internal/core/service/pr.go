@@ -1,53 +1,222 @@
 package service
 
 import (
+	"PRReviewer/internal/core/auth"
 	"PRReviewer/internal/core/dto"
 	"PRReviewer/internal/core/entities"
+	"PRReviewer/internal/core/enums"
 	"PRReviewer/internal/core/errs"
+	"PRReviewer/internal/core/selector"
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
-	"math/rand"
+	"slices"
+	"strings"
 )
 
 type PullRequestRepo interface {
-	CreatePR(ctx context.Context, pr dto.CreatePullRequest) error
-	AddReviewers(ctx context.Context, prID string, reviewers []string) error
+	CreatePR(ctx context.Context, pr dto.CreatePullRequest, teamID string) error
+	AddReviewers(ctx context.Context, prID string, reviewers []entities.Reviewer) error
 	GetPR(ctx context.Context, prID string) (*entities.PullRequest, error)
+	LockPR(ctx context.Context, prID string) error
 	MergePullRequest(ctx context.Context, requestID string) error
-	ReassignPullRequest(ctx context.Context, prID string, oldReviewerID string, newReviewer string) error
-	GetUserPRReviews(ctx context.Context, userID string) ([]dto.PullRequestShort, error)
+	ReassignPullRequest(ctx context.Context, prID string, oldReviewerID string, newReviewer entities.Reviewer) error
+	GetUserPRReviews(ctx context.Context, query dto.GetUserReviewsQuery) ([]dto.PullRequestShort, string, error)
 	IsPRExists(ctx context.Context, prID string) (bool, error)
+	GetStatusBeforeClose(ctx context.Context, prID string) (enums.PRStatus, error)
+	CountOpenReviews(ctx context.Context, userIDs []string) (map[string]int, error)
+	GetLastAssignments(ctx context.Context, userIDs []string) (map[string]int64, error)
+	GetOpenPRsReviewedBy(ctx context.Context, userIDs []string, teamID string) ([]entities.PullRequest, error)
+	AddReviewerAssignments(ctx context.Context, assignments []dto.ReviewerAssignment) error
+	ReplaceReviewerAssignments(ctx context.Context, replacements []dto.ReviewerReplacement) error
+	SetReviewDecision(ctx context.Context, prID string, reviewerID string, decision enums.ReviewDecision) error
+	RecordMergeOverride(ctx context.Context, prID string, actorID string, reason string, unmet []string) error
+	AddChangedFiles(ctx context.Context, prID string, files []string) error
+	GetChangedFiles(ctx context.Context, prID string) ([]string, error)
+	GetChangedFilesByPRs(ctx context.Context, prIDs []string) (map[string][]string, error)
+	UpdatePRStatus(ctx context.Context, prID string, from enums.PRStatus, to enums.PRStatus) error
+	CloseTeamPRs(ctx context.Context, teamID string) ([]string, error)
+	AddPREvents(ctx context.Context, events []entities.PREvent) error
+	GetPREvents(ctx context.Context, prID string) ([]entities.PREvent, error)
+	ListPullRequests(ctx context.Context, query dto.ListPullRequestsQuery) ([]dto.PullRequestShort, string, error)
+	EnqueueWebhookEvents(ctx context.Context, payloads []dto.WebhookPayload) error
+	GetUserReviewEvents(ctx context.Context, userID string, afterID int64, limit int) ([]entities.PREvent, error)
 }
 
 type PullRequestService struct {
-	prRepo   PullRequestRepo
-	userRepo UserRepo
-	TeamRepo TeamRepo
-	tx       Transactor
-	log      *slog.Logger
+	prRepo    PullRequestRepo
+	userRepo  UserRepo
+	TeamRepo  TeamRepo
+	tx        Transactor
+	selectors *selector.Registry
+	metrics   MetricsRecorder
+	broker    ReviewEventBroker
+	log       *slog.Logger
 }
 
-func NewPullRequestService(prRepo PullRequestRepo, userRepo UserRepo, TeamRepo TeamRepo, tx Transactor, log *slog.Logger) *PullRequestService {
-	return &PullRequestService{prRepo, userRepo, TeamRepo, tx, log}
+func NewPullRequestService(prRepo PullRequestRepo, userRepo UserRepo, TeamRepo TeamRepo, tx Transactor, selectors *selector.Registry, metrics MetricsRecorder, broker ReviewEventBroker, log *slog.Logger) *PullRequestService {
+	return &PullRequestService{prRepo, userRepo, TeamRepo, tx, selectors, metrics, broker, log}
 }
 
-func (s *PullRequestService) MergePullRequest(ctx context.Context, requestID string) (*entities.PullRequest, error) {
-	err := s.prRepo.MergePullRequest(ctx, requestID)
+func (s *PullRequestService) MergePullRequest(ctx context.Context, request dto.MergePullRequest) (*entities.PullRequest, error) {
+	ctx, span := tracer.Start(ctx, "PullRequestService.MergePullRequest")
+	defer span.End()
+
+	return s.merge(ctx, request, false)
+}
+
+// MergeUpstreamPullRequest фиксирует мердж, который уже произошёл во внешней системе, например в GitHub.
+// Отменить его сервис не может, поэтому pr мерджится из любого статуса, а политика команды не блокирует мердж:
+// невыполненные условия сохраняются как обход политики с причиной из request.Reason.
+func (s *PullRequestService) MergeUpstreamPullRequest(ctx context.Context, request dto.MergePullRequest) (*entities.PullRequest, error) {
+	ctx, span := tracer.Start(ctx, "PullRequestService.MergeUpstreamPullRequest")
+	defer span.End()
+
+	return s.merge(ctx, request, true)
+}
+
+// merge блокирует pr до проверки статуса: иначе параллельный мердж или закрытие прочитают тот же статус,
+// и pr будет смерджен дважды или закрытый pr станет смердженным.
+func (s *PullRequestService) merge(ctx context.Context, request dto.MergePullRequest, upstream bool) (*entities.PullRequest, error) {
+	var pullRequest *entities.PullRequest
+	merged := false
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := s.prRepo.LockPR(ctx, request.PullRequestID)
+		if err != nil {
+			s.log.Error("не удалось заблокировать pr", "error", err, "pull request ID", request.PullRequestID)
+			return err
+		}
+
+		pr, err := s.prRepo.GetPR(ctx, request.PullRequestID)
+		if err != nil {
+			s.log.Error("неудалось получить pr", "error", err, "pull request ID", request.PullRequestID)
+			return err
+		}
+
+		if pr.Status == string(enums.PRStatusMerged) {
+			pullRequest = pr
+			return nil
+		}
+
+		if pr.Status != string(enums.PRStatusOpened) && !upstream {
+			err = statusError(pr.Status)
+			s.log.Error("pr нельзя смерджить", "error", err, "status", pr.Status)
+			return err
+		}
+
+		err = s.checkMergePolicy(ctx, pr, request, upstream)
+		if err != nil {
+			return err
+		}
+
+		err = s.prRepo.MergePullRequest(ctx, request.PullRequestID)
+		if err != nil {
+			s.log.Error("неудалось смерджить pr", "error", err)
+			return err
+		}
+
+		err = s.recordEvents(ctx, entities.PREvent{
+			PullRequestID: request.PullRequestID,
+			Type:          enums.EventMerged,
+			ActorID:       auth.ActorID(ctx),
+		})
+		if err != nil {
+			return err
+		}
+
+		pullRequest, err = s.prRepo.GetPR(ctx, request.PullRequestID)
+		if err != nil {
+			s.log.Error("неудалось получить pr", "error", err)
+			return err
+		}
+		merged = true
+		return nil
+	})
 	if err != nil {
-		s.log.Error("неудалось смерджить pr", "error", err)
+		s.log.Error("транзакция завершилась с ошибкой", "error", err)
 		return nil, err
 	}
 
-	pr, err := s.prRepo.GetPR(ctx, requestID)
+	if merged {
+		s.metrics.PRMerged(pullRequest.TeamName)
+	}
+	return pullRequest, nil
+}
+
+// checkMergePolicy проверяет pr по политике мерджа команды автора.
+// Аутентифицированный администратор может смерджить pr в обход политики, при этом невыполненные условия сохраняются.
+// Для мерджа из внешней системы условия только сохраняются: мердж уже произошёл.
+func (s *PullRequestService) checkMergePolicy(ctx context.Context, pr *entities.PullRequest, request dto.MergePullRequest, upstream bool) error {
+	policy := defaultMergePolicy
+	if pr.TeamName != "" {
+		team, err := s.TeamRepo.GetTeamByName(ctx, pr.TeamName)
+		if err != nil {
+			s.log.Error("не удалось получить команду", "error", err, "team name", pr.TeamName)
+			return err
+		}
+		if team.MergePolicy != nil {
+			policy = *team.MergePolicy
+		}
+	}
+
+	unmet := unmetMergeConditions(policy, pr.Reviewers)
+	if len(unmet) == 0 {
+		return nil
+	}
+
+	if upstream {
+		return s.recordMergeOverride(ctx, pr.ID, auth.ActorID(ctx), request.Reason, unmet)
+	}
+
+	if !request.Override {
+		err := &errs.MergePolicyError{Unmet: unmet}
+		s.log.Error("pr не удовлетворяет политике мерджа", "error", err, "pull request ID", pr.ID)
+		return err
+	}
+
+	caller, ok := auth.CallerFrom(ctx)
+	if !ok {
+		s.log.Error("обход политики мерджа без аутентификации", "error", errs.ErrUnauthorized, "pull request ID", pr.ID)
+		return errs.ErrUnauthorized
+	}
+	if !caller.IsAdmin {
+		s.log.Error("обход политики мерджа доступен только администраторам", "error", errs.ErrForbidden, "user ID", caller.UserID)
+		return errs.ErrForbidden
+	}
+
+	return s.recordMergeOverride(ctx, pr.ID, caller.UserID, request.Reason, unmet)
+}
+
+func (s *PullRequestService) recordMergeOverride(ctx context.Context, prID string, actorID string, reason string, unmet []string) error {
+	err := s.prRepo.RecordMergeOverride(ctx, prID, actorID, reason, unmet)
 	if err != nil {
-		s.log.Error("неудалось получить pr", "error", err)
-		return nil, err
+		s.log.Error("не удалось сохранить обход политики мерджа", "error", err)
+		return err
 	}
 
-	return pr, nil
+	s.log.Warn("pr смерджен в обход политики", "pull request ID", prID, "actor", actorID, "reason", reason, "unmet", unmet)
+	return nil
 }
 
 func (s *PullRequestService) CreatePullRequest(ctx context.Context, pr dto.CreatePullRequest) (*entities.PullRequest, error) {
+	ctx, span := tracer.Start(ctx, "PullRequestService.CreatePullRequest")
+	defer span.End()
+
+	return s.create(ctx, pr, false)
+}
+
+// CreateUpstreamPullRequest сохраняет pr, который уже открыт во внешней системе, например в GitHub.
+// Отклонить его сервис не может, поэтому при отсутствии доступных ревьюеров pr сохраняется без них,
+// а нехватка кандидатов попадает в метрику NoCandidate и журнал с кодом NO_CANDIDATE.
+func (s *PullRequestService) CreateUpstreamPullRequest(ctx context.Context, pr dto.CreatePullRequest) (*entities.PullRequest, error) {
+	ctx, span := tracer.Start(ctx, "PullRequestService.CreateUpstreamPullRequest")
+	defer span.End()
+
+	return s.create(ctx, pr, true)
+}
+
+func (s *PullRequestService) create(ctx context.Context, pr dto.CreatePullRequest, upstream bool) (*entities.PullRequest, error) {
 	var pullRequest entities.PullRequest
 	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
 
@@ -67,30 +236,53 @@ func (s *PullRequestService) CreatePullRequest(ctx context.Context, pr dto.Creat
 			return err
 		}
 
-		team, err := s.TeamRepo.GetTeamByName(ctx, user.TeamName)
+		teamName, err := s.reviewingTeam(user, pr.TeamName)
 		if err != nil {
-			s.log.Error("не удалось получить команду", "error", err, "team name", user.TeamName)
 			return err
 		}
 
-		reviewers, err := s.getReviewers(pr.AuthorID, team.Members, 2)
+		team, err := s.TeamRepo.GetTeamByName(ctx, teamName)
 		if err != nil {
-			s.log.Error("не удалось получить ревьюеров", "error", err)
+			s.log.Error("не удалось получить команду", "error", err, "team name", teamName)
 			return err
 		}
+		if team.ArchivedAt != nil {
+			s.log.Error("нельзя создать pr в архивной команде", "error", errs.ErrTeamArchived, "team name", teamName)
+			return errs.ErrTeamArchived
+		}
 
-		err = s.prRepo.CreatePR(ctx, pr)
+		err = s.prRepo.CreatePR(ctx, pr, team.ID)
 		if err != nil {
 			s.log.Error("не удалось создать pr", "error", err)
 			return err
 		}
 
-		err = s.prRepo.AddReviewers(ctx, pr.PullRequestID, reviewers)
+		err = s.prRepo.AddChangedFiles(ctx, pr.PullRequestID, pr.ChangedFiles)
+		if err != nil {
+			s.log.Error("не удалось сохранить изменённые файлы", "error", err)
+			return err
+		}
+
+		err = s.recordEvents(ctx, entities.PREvent{
+			PullRequestID: pr.PullRequestID,
+			Type:          enums.EventCreated,
+			ActorID:       auth.ActorID(ctx),
+		})
 		if err != nil {
-			s.log.Error("не удалось добавить ревьюеров", "error", err)
 			return err
 		}
 
+		if !pr.Draft {
+			err = s.assignReviewers(ctx, pr.PullRequestID, pr.AuthorID, team.TeamName, pr.ChangedFiles)
+			if upstream && errors.Is(err, errs.ErrNoReviewersAvailable) {
+				s.log.Warn("pr из внешней системы сохранён без ревьюеров", "code", enums.CodeNoCandidate, "error", err, "pull request ID", pr.PullRequestID)
+				err = nil
+			}
+			if err != nil {
+				return err
+			}
+		}
+
 		getPR, err := s.prRepo.GetPR(ctx, pr.PullRequestID)
 		if err != nil {
 			s.log.Error("не удалось получить pr", "error", err, "pull request ID", pr.PullRequestID)
@@ -105,88 +297,456 @@ func (s *PullRequestService) CreatePullRequest(ctx context.Context, pr dto.Creat
 		return nil, err
 	}
 
+	s.metrics.PRCreated(pullRequest.TeamName)
 	return &pullRequest, nil
 }
 
-func (s *PullRequestService) ReassignPullRequest(ctx context.Context, requestID string, oldUserID string) (*entities.PullRequest, error) {
-	var pullRequest entities.PullRequest
+// reviewingTeam определяет команду, которая ревьюит pr: выбранную автором, если он в ней состоит,
+// иначе основную команду автора.
+func (s *PullRequestService) reviewingTeam(author *entities.User, requested string) (string, error) {
+	requested = strings.TrimSpace(requested)
+	if requested == "" {
+		return author.TeamName, nil
+	}
+
+	isMember := slices.ContainsFunc(author.Teams, func(team entities.UserTeam) bool {
+		return team.TeamName == requested
+	})
+	if !isMember {
+		s.log.Error("автор не состоит в выбранной команде", "error", errs.ErrNotTeamMember, "team name", requested)
+		return "", errs.ErrNotTeamMember
+	}
+	return requested, nil
+}
+
+func (s *PullRequestService) ReassignPullRequest(ctx context.Context, request dto.ReassignReviewer) (*entities.ReassignedPullRequest, error) {
+	ctx, span := tracer.Start(ctx, "PullRequestService.ReassignPullRequest")
+	defer span.End()
+
+	var reassigned *entities.ReassignedPullRequest
 	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
-		pr, err := s.prRepo.GetPR(ctx, requestID)
+		pr, newID, err := s.reassignReviewer(ctx, request)
 		if err != nil {
-			s.log.Error("не удалось получить pr", "error", err, "pull request ID", requestID)
 			return err
 		}
 
-		exists, err := s.userRepo.IsUserExist(ctx, oldUserID)
+		reassigned = &entities.ReassignedPullRequest{PR: *pr, ReplacedBY: newID}
+		return nil
+	})
+	if err != nil {
+		s.log.Error("транзакция завершилась с ошибкой", "error", err)
+		return nil, err
+	}
+
+	s.metrics.ReviewerReassigned(reassigned.PR.TeamName)
+	return reassigned, nil
+}
+
+// ReassignReviews снимает пользователей со всех открытых pr, где они ревьюеры, и подбирает замену
+// по обычным правилам переназначения. Pr, для которых замены не нашлось, попадают в отчёт и не прерывают операцию.
+// Все pr, команды и нагрузка читаются пачкой, а изменения пишутся двумя запросами, независимо от числа pr:
+// снятый ревьюер заменяется на месте, решения остальных ревьюеров сохраняются.
+// Должен вызываться внутри транзакции вызывающей стороны; метрики переназначений учитываются только после её коммита.
+func (s *PullRequestService) ReassignReviews(ctx context.Context, userIDs []string) (*dto.ReassignmentReport, error) {
+	ctx, span := tracer.Start(ctx, "PullRequestService.ReassignReviews")
+	defer span.End()
+
+	return s.reassignReviews(ctx, "", userIDs)
+}
+
+// ReassignTeamReviews работает как ReassignReviews, но затрагивает только pr указанной команды.
+// Снимаемые пользователи не выбираются заменой ни на одном из этих pr.
+func (s *PullRequestService) ReassignTeamReviews(ctx context.Context, teamID string, userIDs []string) (*dto.ReassignmentReport, error) {
+	ctx, span := tracer.Start(ctx, "PullRequestService.ReassignTeamReviews")
+	defer span.End()
+
+	return s.reassignReviews(ctx, teamID, userIDs)
+}
+
+func (s *PullRequestService) reassignReviews(ctx context.Context, teamID string, userIDs []string) (*dto.ReassignmentReport, error) {
+	report := dto.NewReassignmentReport()
+	if len(userIDs) == 0 {
+		return report, nil
+	}
+
+	prs, err := s.prRepo.GetOpenPRsReviewedBy(ctx, userIDs, teamID)
+	if err != nil {
+		s.log.Error("не удалось получить открытые ревью пользователей", "error", err)
+		return nil, err
+	}
+
+	pool := newReviewerPool()
+	for _, id := range userIDs {
+		pool.excluded[id] = true
+	}
+	teams := make([]*dto.Team, 0)
+
+	for _, pr := range prs {
+		if pr.TeamName == "" {
+			continue
+		}
+
+		team, err := s.poolTeam(ctx, pool, pr.TeamName)
 		if err != nil {
-			s.log.Error("не удалось проверить существование пользователя", "error", err)
-			return err
+			return nil, err
+		}
+		teams = append(teams, team)
+	}
+
+	err = s.lockCandidates(ctx, pool, teams...)
+	if err != nil {
+		return nil, err
+	}
+
+	prIDs := make([]string, len(prs))
+	for i, pr := range prs {
+		prIDs[i] = pr.ID
+	}
+	changedFiles, err := s.prRepo.GetChangedFilesByPRs(ctx, prIDs)
+	if err != nil {
+		s.log.Error("не удалось получить изменённые файлы", "error", err)
+		return nil, err
+	}
+
+	targets := make(map[string]bool, len(userIDs))
+	for _, id := range userIDs {
+		targets[id] = true
+	}
+
+	replaced := make([]dto.ReviewerReplacement, 0)
+	added := make([]dto.ReviewerAssignment, 0)
+	events := make([]entities.PREvent, 0)
+	reassignedTeams := make([]string, 0)
+
+	for _, pr := range prs {
+		if pr.TeamName == "" {
+			for _, reviewer := range pr.Reviewers {
+				if targets[reviewer.UserID] {
+					s.log.Warn("pr не привязан к команде, ревьюер не заменён", "pull request ID", pr.ID, "user ID", reviewer.UserID)
+					report.Failed = append(report.Failed, dto.ReassignmentResult{PullRequestID: pr.ID, OldUserID: reviewer.UserID, Reason: errs.ErrPRWithoutTeam.Error()})
+				}
+			}
+			continue
+		}
+
+		team := pool.teams[pr.TeamName]
+		current := s.reviewersToIDs(pr.Reviewers)
+		files := changedFiles[pr.ID]
+
+		for _, reviewer := range pr.Reviewers {
+			if !targets[reviewer.UserID] {
+				continue
+			}
+
+			result := dto.ReassignmentResult{PullRequestID: pr.ID, OldUserID: reviewer.UserID}
+
+			newReviewers, err := s.replacementReviewers(ctx, pool, &pr, team, current, reviewer.UserID, files)
+			if err != nil {
+				if !errors.Is(err, errs.ErrNoReviewersAvailable) {
+					return nil, err
+				}
+				result.Reason = err.Error()
+				report.Failed = append(report.Failed, result)
+				continue
+			}
+
+			current = slices.DeleteFunc(current, func(id string) bool { return id == reviewer.UserID })
+			replaced = append(replaced, dto.ReviewerReplacement{PullRequestID: pr.ID, OldUserID: reviewer.UserID, Reviewer: newReviewers[0]})
+			current = append(current, newReviewers[0].UserID)
+			for _, newReviewer := range newReviewers[1:] {
+				current = append(current, newReviewer.UserID)
+				added = append(added, dto.ReviewerAssignment{PullRequestID: pr.ID, Reviewer: newReviewer})
+			}
+
+			events = append(events, replacementEvents(pr.ID, "", reviewer.UserID, newReviewers)...)
+			result.ReplacedBy = newReviewers[0].UserID
+			report.Reassigned = append(report.Reassigned, result)
+			reassignedTeams = append(reassignedTeams, pr.TeamName)
 		}
-		if !exists {
-			s.log.Error("пользоваетль не существует", "error", err, "user ID", oldUserID)
-			return errs.ErrNotFound
+	}
+
+	err = s.prRepo.ReplaceReviewerAssignments(ctx, replaced)
+	if err != nil {
+		s.log.Error("не удалось заменить ревьюеров", "error", err)
+		return nil, err
+	}
+
+	err = s.prRepo.AddReviewerAssignments(ctx, added)
+	if err != nil {
+		s.log.Error("не удалось назначить ревьюеров", "error", err)
+		return nil, err
+	}
+
+	err = s.recordEvents(ctx, events...)
+	if err != nil {
+		return nil, err
+	}
+
+	s.tx.AfterCommit(ctx, func() {
+		for _, teamName := range reassignedTeams {
+			s.metrics.ReviewerReassigned(teamName)
 		}
+	})
+	return report, nil
+}
+
+func (s *PullRequestService) reassignReviewer(ctx context.Context, request dto.ReassignReviewer) (*entities.PullRequest, string, error) {
+	requestID, oldUserID := request.PullRequestID, request.OldUserID
+
+	pr, err := s.prRepo.GetPR(ctx, requestID)
+	if err != nil {
+		s.log.Error("не удалось получить pr", "error", err, "pull request ID", requestID)
+		return nil, "", err
+	}
 
-		if pr.Status == "MERGED" {
-			s.log.Error("pr уже смержен", "error", errs.ErrAlreadyMerged)
-			return errs.ErrAlreadyMerged
+	exists, err := s.userRepo.IsUserExist(ctx, oldUserID)
+	if err != nil {
+		s.log.Error("не удалось проверить существование пользователя", "error", err)
+		return nil, "", err
+	}
+	if !exists {
+		s.log.Error("пользоваетль не существует", "error", err, "user ID", oldUserID)
+		return nil, "", errs.ErrNotFound
+	}
+
+	if pr.Status != string(enums.PRStatusOpened) {
+		err = statusError(pr.Status)
+		if pr.Status == string(enums.PRStatusMerged) {
+			err = errs.ErrAlreadyMerged
 		}
+		s.log.Error("нельзя переназначить ревьюера pr", "error", err, "status", pr.Status)
+		return nil, "", err
+	}
+
+	ids := s.reviewersToIDs(pr.Reviewers)
+
+	if pr.AuthorID == oldUserID || !slices.Contains(ids, oldUserID) {
+		s.log.Error("пользователь не назначен ревьюером pr", "error", errs.ErrUserNotAssigned, "user ID", oldUserID)
+		return nil, "", errs.ErrUserNotAssigned
+	}
 
-		ids := s.teamMembersToIDs(pr.Reviewers)
+	if pr.TeamName == "" {
+		s.log.Error("нельзя подобрать замену", "error", errs.ErrPRWithoutTeam, "pull request ID", requestID)
+		return nil, "", errs.ErrPRWithoutTeam
+	}
+
+	pool := newReviewerPool()
+	pool.excluded[oldUserID] = true
+
+	team, err := s.poolTeam(ctx, pool, pr.TeamName)
+	if err != nil {
+		return nil, "", err
+	}
+
+	err = s.lockCandidates(ctx, pool, team)
+	if err != nil {
+		return nil, "", err
+	}
 
-		author, err := s.userRepo.GetUserByID(ctx, pr.AuthorID)
+	var newReviewers []entities.Reviewer
+	if request.NewUserID != "" {
+		newReviewers, err = s.requestedReplacement(ctx, pool, pr, team, ids, request.NewUserID)
+	} else {
+		var files []string
+		files, err = s.prRepo.GetChangedFiles(ctx, requestID)
 		if err != nil {
-			s.log.Error("не удалось получить пользователя по id", "error", err)
-			return err
+			s.log.Error("не удалось получить изменённые файлы", "error", err, "pull request ID", requestID)
+			return nil, "", err
 		}
+		newReviewers, err = s.replacementReviewers(ctx, pool, pr, team, ids, oldUserID, files)
+	}
+	if err != nil {
+		return nil, "", err
+	}
 
-		if author.ID == oldUserID {
-			s.log.Error("id автора совпадает с id ревьюера", "error", errs.ErrUserNotAssigned)
-			return errs.ErrUserNotAssigned
+	err = s.prRepo.ReassignPullRequest(ctx, requestID, oldUserID, newReviewers[0])
+	if err != nil {
+		s.log.Error("не удалось переназначить ревьюера", "error", err)
+		return nil, "", err
+	}
+
+	err = s.prRepo.AddReviewers(ctx, requestID, newReviewers[1:])
+	if err != nil {
+		s.log.Error("не удалось добавить ревьюеров", "error", err)
+		return nil, "", err
+	}
+
+	err = s.recordEvents(ctx, replacementEvents(requestID, auth.ActorID(ctx), oldUserID, newReviewers)...)
+	if err != nil {
+		return nil, "", err
+	}
+
+	pr, err = s.prRepo.GetPR(ctx, requestID)
+	if err != nil {
+		s.log.Error("не удалось получить pr", "error", err)
+		return nil, "", err
+	}
+
+	return pr, newReviewers[0].UserID, nil
+}
+
+// replacementReviewers подбирает замену одному из текущих ревьюеров и при необходимости добирает
+// недостающих, чтобы на pr осталось столько ревьюеров, сколько настроено в команде.
+// Как и при создании pr, сначала назначаются владельцы изменённых файлов, правила которых
+// не покрыты остающимися ревьюерами. Первый из возвращённых ревьюеров занимает место заменяемого.
+func (s *PullRequestService) replacementReviewers(ctx context.Context, pool *reviewerPool, pr *entities.PullRequest, team *dto.Team, current []string, oldUserID string, changedFiles []string) ([]entities.Reviewer, error) {
+	kept := slices.DeleteFunc(slices.Clone(current), func(id string) bool { return id == oldUserID })
+	needed := max(1, team.ReviewersPerPR-len(kept))
+
+	newReviewers, err := s.getOwnerReviewers(ctx, pool, pr.AuthorID, team, changedFiles, kept)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(newReviewers) < needed {
+		rest, err := s.getReviewers(ctx, pool, pr.AuthorID, team, needed-len(newReviewers), append(slices.Clone(current), s.reviewersToIDs(newReviewers)...)...)
+		if err != nil && (len(newReviewers) == 0 || !errors.Is(err, errs.ErrNoReviewersAvailable)) {
+			s.log.Error("не удалось получить ревьюеров", "error", err, "pull request ID", pr.ID)
+			return nil, err
 		}
+		newReviewers = append(newReviewers, rest...)
+	}
+
+	if len(newReviewers) < 1 {
+		s.log.Error("не удалось получить ревьюеров", "error", errs.ErrNoReviewersAvailable)
+		return nil, errs.ErrNoReviewersAvailable
+	}
+
+	return newReviewers, nil
+}
+
+// requestedReplacement ставит на место заменяемого ревьюера выбранного вызывающей стороной участника команды pr.
+// Лимит открытых ревью для явно выбранного участника не проверяется. Недостающие до настроенного числа
+// ревьюеры добираются по обычным правилам, если для них есть кандидаты.
+func (s *PullRequestService) requestedReplacement(ctx context.Context, pool *reviewerPool, pr *entities.PullRequest, team *dto.Team, current []string, newUserID string) ([]entities.Reviewer, error) {
+	index := slices.IndexFunc(team.Members, func(member dto.TeamMember) bool {
+		return member.UserID == newUserID
+	})
+
+	var err error
+	switch {
+	case index < 0:
+		err = errs.ErrNotTeamMember
+	case newUserID == pr.AuthorID:
+		err = errs.ErrReviewerIsAuthor
+	case slices.Contains(current, newUserID):
+		err = errs.ErrReviewerAlreadyAssigned
+	case !team.Members[index].IsActive:
+		err = errs.ErrReviewerInactive
+	}
+	if err != nil {
+		s.log.Error("нельзя назначить выбранного ревьюера", "error", err, "user ID", newUserID)
+		return nil, err
+	}
 
-		team, err := s.TeamRepo.GetTeamByName(ctx, author.TeamName)
+	newReviewers := []entities.Reviewer{{UserID: newUserID, IsActive: true}}
+
+	missing := team.ReviewersPerPR - len(current)
+	if missing <= 0 {
+		return newReviewers, nil
+	}
+
+	extra, err := s.getReviewers(ctx, pool, pr.AuthorID, team, missing, append(current, newUserID)...)
+	if err != nil {
+		if errors.Is(err, errs.ErrNoReviewersAvailable) {
+			return newReviewers, nil
+		}
+		return nil, err
+	}
+
+	return append(newReviewers, extra...), nil
+}
+
+func (s *PullRequestService) memberIDs(team *dto.Team) []string {
+	ids := make([]string, len(team.Members))
+	for i, member := range team.Members {
+		ids[i] = member.UserID
+	}
+	return ids
+}
+
+// SubmitReview сохраняет решение ревьюера. Ревьюер - аутентифицированный инициатор запроса:
+// оставить решение за другого пользователя нельзя. Комментарий не отменяет уже принятое решение
+// (APPROVED или CHANGES_REQUESTED), а только фиксируется поверх PENDING.
+func (s *PullRequestService) SubmitReview(ctx context.Context, review dto.SubmitReviewRequest) (*entities.PullRequest, error) {
+	ctx, span := tracer.Start(ctx, "PullRequestService.SubmitReview")
+	defer span.End()
+
+	caller, ok := auth.CallerFrom(ctx)
+	if !ok {
+		s.log.Error("ревью без аутентификации", "error", errs.ErrUnauthorized, "pull request ID", review.PullRequestID)
+		return nil, errs.ErrUnauthorized
+	}
+	if caller.UserID == "" || (review.ReviewerID != "" && review.ReviewerID != caller.UserID) {
+		s.log.Error("ревью от имени другого пользователя", "error", errs.ErrForbidden, "user ID", caller.UserID, "reviewer ID", review.ReviewerID)
+		return nil, errs.ErrForbidden
+	}
+	review.ReviewerID = caller.UserID
+
+	var pullRequest *entities.PullRequest
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		pr, err := s.prRepo.GetPR(ctx, review.PullRequestID)
 		if err != nil {
-			s.log.Error("не удалось получить получить команду по названию", "error", err, "team name", author.TeamName)
+			s.log.Error("не удалось получить pr", "error", err, "pull request ID", review.PullRequestID)
 			return err
 		}
 
-		newReviewers, err := s.getReviewers(pr.AuthorID, team.Members, 1, ids...)
+		if pr.Status != string(enums.PRStatusOpened) {
+			s.log.Error("pr не открыт", "error", errs.ErrReviewOnClosedPR, "status", pr.Status)
+			return fmt.Errorf("%w: %w", errs.ErrReviewOnClosedPR, statusError(pr.Status))
+		}
+
+		index := slices.IndexFunc(pr.Reviewers, func(reviewer entities.Reviewer) bool {
+			return reviewer.UserID == review.ReviewerID
+		})
+		if index < 0 {
+			s.log.Error("пользователь не назначен ревьюером pr", "error", errs.ErrUserNotAssigned, "user ID", review.ReviewerID)
+			return errs.ErrUserNotAssigned
+		}
+
+		decision := review.Decision
+		current := pr.Reviewers[index].Decision
+		if decision == enums.DecisionCommented && (current == enums.DecisionApproved || current == enums.DecisionChangesRequested) {
+			decision = current
+		}
+
+		err = s.prRepo.SetReviewDecision(ctx, review.PullRequestID, review.ReviewerID, decision)
 		if err != nil {
-			s.log.Error("не удалось получить ревьюеров", "error", err)
+			s.log.Error("не удалось сохранить решение ревьюера", "error", err)
 			return err
 		}
 
-		if len(newReviewers) < 1 {
-			s.log.Error("не удалось получить ревьюеров", "error", errs.ErrNoReviewersAvailable)
-			return errs.ErrNoReviewersAvailable
-		}
-		err = s.prRepo.ReassignPullRequest(ctx, requestID, oldUserID, newReviewers[0])
+		err = s.recordEvents(ctx, entities.PREvent{
+			PullRequestID: review.PullRequestID,
+			Type:          enums.EventReviewSubmitted,
+			ActorID:       auth.ActorID(ctx),
+			ReviewerID:    review.ReviewerID,
+			Decision:      review.Decision,
+		})
 		if err != nil {
-			s.log.Error("не удалось переназначить ревьюера", "error", err)
 			return err
 		}
 
-		pr, err = s.prRepo.GetPR(ctx, requestID)
+		pullRequest, err = s.prRepo.GetPR(ctx, review.PullRequestID)
 		if err != nil {
 			s.log.Error("не удалось получить pr", "error", err)
 			return err
 		}
-
-		pullRequest = *pr
-
 		return nil
 	})
 	if err != nil {
 		s.log.Error("транзакция завершилась с ошибкой", "error", err)
 		return nil, err
 	}
-	return &pullRequest, nil
+	return pullRequest, nil
 }
 
-func (s *PullRequestService) GetUserReviewers(ctx context.Context, userID string) (*dto.GetPullRequestResponse, error) {
+// GetUserReviewers возвращает страницу ревью пользователя; Decision в каждом pr — решение этого пользователя.
+func (s *PullRequestService) GetUserReviewers(ctx context.Context, query dto.GetUserReviewsQuery) (*dto.GetPullRequestResponse, error) {
+	ctx, span := tracer.Start(ctx, "PullRequestService.GetUserReviewers")
+	defer span.End()
 
-	exists, err := s.userRepo.IsUserExist(ctx, userID)
+	exists, err := s.userRepo.IsUserExist(ctx, query.UserID)
 	if err != nil {
 		s.log.Error("не удалось проверить существует ли пользователь", "error", err)
 		return nil, err
@@ -196,54 +756,31 @@ func (s *PullRequestService) GetUserReviewers(ctx context.Context, userID string
 		return nil, errs.ErrNotFound
 	}
 
-	reviews, err := s.prRepo.GetUserPRReviews(ctx, userID)
+	query.Limit = pageLimit(query.Limit)
+	reviews, next, err := s.prRepo.GetUserPRReviews(ctx, query)
 	if err != nil {
 		s.log.Error("не удалось получить ревьюеров pr", "error", err)
 		return nil, err
 	}
 	response := &dto.GetPullRequestResponse{
-		UserID:       userID,
+		UserID:       query.UserID,
 		PullRequests: reviews,
+		NextCursor:   next,
 	}
 	return response, nil
 }
 
-func (s *PullRequestService) getReviewers(authorID string, members []dto.TeamMember, limit int, excludeMembers ...string) ([]string, error) {
-	reviewers := make([]dto.TeamMember, 0)
-	excludeMap := make(map[string]bool)
-
-	for _, excludedID := range excludeMembers {
-		excludeMap[excludedID] = true
-	}
-
-	for _, member := range members {
-		if member.UserID != authorID && member.IsActive && !excludeMap[member.UserID] {
-			reviewers = append(reviewers, member)
-		}
-	}
-
-	if len(reviewers) == 0 {
-		s.log.Error("нет доступных ревьюеров", "error", errs.ErrNoReviewersAvailable)
-		return nil, errs.ErrNoReviewersAvailable
-	}
+func (s *PullRequestService) ListPullRequests(ctx context.Context, query dto.ListPullRequestsQuery) (*dto.ListPullRequestsResponse, error) {
+	ctx, span := tracer.Start(ctx, "PullRequestService.ListPullRequests")
+	defer span.End()
 
-	rand.Shuffle(len(reviewers), func(i, j int) {
-		reviewers[i], reviewers[j] = reviewers[j], reviewers[i]
-	})
+	query.TeamName = strings.TrimSpace(query.TeamName)
+	query.Limit = pageLimit(query.Limit)
 
-	count := min(limit, len(reviewers))
-	reviewerIDs := make([]string, count)
-	for i, reviewer := range reviewers[:count] {
-		reviewerIDs[i] = reviewer.UserID
-	}
-
-	return reviewerIDs, nil
-}
-
-func (s *PullRequestService) teamMembersToIDs(members []dto.TeamMember) []string {
-	ids := make([]string, len(members))
-	for i, member := range members {
-		ids[i] = member.UserID
+	prs, next, err := s.prRepo.ListPullRequests(ctx, query)
+	if err != nil {
+		s.log.Error("не удалось получить список pr", "error", err)
+		return nil, err
 	}
-	return ids
+	return &dto.ListPullRequestsResponse{PullRequests: prs, NextCursor: next}, nil
 }
@@ -0,0 +1,195 @@
+package service
+
+import (
+	"PRReviewer/internal/core/auth"
+	"PRReviewer/internal/core/dto"
+	"PRReviewer/internal/core/entities"
+	"PRReviewer/internal/core/enums"
+	"PRReviewer/internal/core/errs"
+	"context"
+	"slices"
+)
+
+// MarkReadyForReview переводит черновик в OPENED и назначает ревьюеров.
+func (s *PullRequestService) MarkReadyForReview(ctx context.Context, request dto.PullRequestTransition) (*entities.PullRequest, error) {
+	ctx, span := tracer.Start(ctx, "PullRequestService.MarkReadyForReview")
+	defer span.End()
+
+	return s.transition(ctx, request, fixedTarget(enums.PRStatusOpened), enums.EventReadyForReview, enums.PRStatusDraft)
+}
+
+// ClosePullRequest закрывает pr без мерджа. Назначенные ревьюеры и их решения сохраняются.
+func (s *PullRequestService) ClosePullRequest(ctx context.Context, request dto.PullRequestTransition) (*entities.PullRequest, error) {
+	ctx, span := tracer.Start(ctx, "PullRequestService.ClosePullRequest")
+	defer span.End()
+
+	return s.transition(ctx, request, fixedTarget(enums.PRStatusClosed), enums.EventClosed, enums.PRStatusOpened, enums.PRStatusDraft)
+}
+
+// ReopenPullRequest возвращает закрытый pr в статус, в котором его закрыли: черновик остаётся черновиком
+// и получает ревьюеров только при переводе в OPENED.
+func (s *PullRequestService) ReopenPullRequest(ctx context.Context, request dto.PullRequestTransition) (*entities.PullRequest, error) {
+	ctx, span := tracer.Start(ctx, "PullRequestService.ReopenPullRequest")
+	defer span.End()
+
+	return s.transition(ctx, request, s.statusBeforeClose, enums.EventReopened, enums.PRStatusClosed)
+}
+
+// ConvertToDraft возвращает открытый pr в черновики. Назначенные ревьюеры сохраняются
+// и при повторном переводе в OPENED новые не добавляются.
+func (s *PullRequestService) ConvertToDraft(ctx context.Context, request dto.PullRequestTransition) (*entities.PullRequest, error) {
+	ctx, span := tracer.Start(ctx, "PullRequestService.ConvertToDraft")
+	defer span.End()
+
+	return s.transition(ctx, request, fixedTarget(enums.PRStatusDraft), enums.EventConvertedToDraft, enums.PRStatusOpened)
+}
+
+// CloseTeamPullRequests закрывает открытые pr и черновики команды перед её удалением, чтобы
+// после удаления не осталось открытых pr без команды. Вызывается внутри транзакции удаления.
+func (s *PullRequestService) CloseTeamPullRequests(ctx context.Context, teamID string) ([]string, error) {
+	ctx, span := tracer.Start(ctx, "PullRequestService.CloseTeamPullRequests")
+	defer span.End()
+
+	ids, err := s.prRepo.CloseTeamPRs(ctx, teamID)
+	if err != nil {
+		s.log.Error("не удалось закрыть pr команды", "error", err, "team ID", teamID)
+		return nil, err
+	}
+
+	events := make([]entities.PREvent, len(ids))
+	for i, id := range ids {
+		events[i] = entities.PREvent{PullRequestID: id, Type: enums.EventClosed, ActorID: auth.ActorID(ctx)}
+	}
+
+	err = s.recordEvents(ctx, events...)
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// transitionTarget выбирает статус, в который переводится pr.
+type transitionTarget func(ctx context.Context, prID string) (enums.PRStatus, error)
+
+func fixedTarget(status enums.PRStatus) transitionTarget {
+	return func(context.Context, string) (enums.PRStatus, error) {
+		return status, nil
+	}
+}
+
+func (s *PullRequestService) statusBeforeClose(ctx context.Context, prID string) (enums.PRStatus, error) {
+	status, err := s.prRepo.GetStatusBeforeClose(ctx, prID)
+	if err != nil {
+		s.log.Error("не удалось получить статус pr до закрытия", "error", err, "pull request ID", prID)
+		return "", err
+	}
+	return status, nil
+}
+
+func (s *PullRequestService) transition(ctx context.Context, request dto.PullRequestTransition, target transitionTarget, event enums.PREventType, from ...enums.PRStatus) (*entities.PullRequest, error) {
+	requestID := request.PullRequestID
+
+	var pullRequest *entities.PullRequest
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		pr, err := s.prRepo.GetPR(ctx, requestID)
+		if err != nil {
+			s.log.Error("не удалось получить pr", "error", err, "pull request ID", requestID)
+			return err
+		}
+
+		current := enums.PRStatus(pr.Status)
+		if !slices.Contains(from, current) {
+			err = statusError(pr.Status)
+			s.log.Error("недопустимый переход статуса pr", "error", err, "from", current, "event", event)
+			return err
+		}
+
+		to, err := target(ctx, requestID)
+		if err != nil {
+			return err
+		}
+
+		err = s.prRepo.UpdatePRStatus(ctx, requestID, current, to)
+		if err != nil {
+			s.log.Error("не удалось изменить статус pr", "error", err, "pull request ID", requestID)
+			return err
+		}
+
+		err = s.recordEvents(ctx, entities.PREvent{PullRequestID: requestID, Type: event, ActorID: auth.ActorID(ctx)})
+		if err != nil {
+			return err
+		}
+
+		if to == enums.PRStatusOpened && len(pr.Reviewers) == 0 {
+			files, err := s.prRepo.GetChangedFiles(ctx, requestID)
+			if err != nil {
+				s.log.Error("не удалось получить изменённые файлы", "error", err)
+				return err
+			}
+
+			err = s.assignReviewers(ctx, requestID, pr.AuthorID, pr.TeamName, files)
+			if err != nil {
+				return err
+			}
+		}
+
+		pullRequest, err = s.prRepo.GetPR(ctx, requestID)
+		if err != nil {
+			s.log.Error("не удалось получить pr", "error", err)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		s.log.Error("транзакция завершилась с ошибкой", "error", err)
+		return nil, err
+	}
+	return pullRequest, nil
+}
+
+// assignReviewers подбирает ревьюеров из команды автора, назначает их на pr и записывает назначения в журнал.
+func (s *PullRequestService) assignReviewers(ctx context.Context, prID string, authorID string, teamName string, files []string) error {
+	if teamName == "" {
+		s.log.Error("нельзя назначить ревьюеров", "error", errs.ErrPRWithoutTeam, "pull request ID", prID)
+		return errs.ErrPRWithoutTeam
+	}
+
+	pool := newReviewerPool()
+	team, err := s.poolTeam(ctx, pool, teamName)
+	if err != nil {
+		return err
+	}
+
+	err = s.lockCandidates(ctx, pool, team)
+	if err != nil {
+		return err
+	}
+
+	reviewers, err := s.selectReviewers(ctx, pool, authorID, team, files)
+	if err != nil {
+		s.log.Error("не удалось получить ревьюеров", "error", err)
+		return err
+	}
+
+	err = s.prRepo.AddReviewers(ctx, prID, reviewers)
+	if err != nil {
+		s.log.Error("не удалось добавить ревьюеров", "error", err)
+		return err
+	}
+
+	return s.recordEvents(ctx, assignmentEvents(prID, auth.ActorID(ctx), reviewers)...)
+}
+
+// statusError возвращает ошибку, объясняющую, почему операция недоступна для pr в данном статусе.
+func statusError(status string) error {
+	switch enums.PRStatus(status) {
+	case enums.PRStatusMerged:
+		return errs.ErrPRMerged
+	case enums.PRStatusDraft:
+		return errs.ErrPRIsDraft
+	case enums.PRStatusClosed:
+		return errs.ErrPRClosed
+	default:
+		return errs.ErrInvalidTransition
+	}
+}
@@ -8,7 +8,16 @@ import (
 
 type UserRepo interface {
 	AddUsers(ctx context.Context, users []dto.TeamMember) error
+	AddMissingUsers(ctx context.Context, users []dto.TeamMember) error
 	GetUserByID(ctx context.Context, userID string) (*entities.User, error)
 	SetIsActive(ctx context.Context, userID string, isActive bool) error
+	SetUsersActive(ctx context.Context, userIDs []string, isActive bool) error
+	UpdateUser(ctx context.Context, update dto.UpdateUserRequest) error
+	SetAdmin(ctx context.Context, userID string, isAdmin bool) error
+	SetGitHubLogin(ctx context.Context, userID string, login string) error
 	IsUserExist(ctx context.Context, userID string) (bool, error)
+	LockUsers(ctx context.Context, userIDs []string) error
+	SetPrimaryTeam(ctx context.Context, userID string, teamName string) error
+	ListUsers(ctx context.Context, query dto.ListUsersQuery) ([]dto.UserSummary, string, error)
+	GetUserIDByGitHubLogin(ctx context.Context, login string) (string, error)
 }
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"PRReviewer/internal/core/entities"
+	"PRReviewer/internal/core/enums"
+	"PRReviewer/internal/core/errs"
+	"context"
+)
+
+// ReviewEventBroker раздаёт события ревью подписчикам внутри процесса.
+// Канал подписки закрывается при отписке, остановке брокера или если подписчик не успевает читать.
+type ReviewEventBroker interface {
+	Publish(userID string, event entities.PREvent)
+	Subscribe(userID string) (<-chan entities.PREvent, func(), error)
+}
+
+// reviewEventsPage — сколько событий журнала читается за один запрос при досылке пропущенного.
+const reviewEventsPage = 100
+
+// SubscribeReviewEvents подписывает на назначения, замены и мерджи, касающиеся пользователя как ревьюера.
+// Пропущенные события досылаются через GetUserReviewEvents; подписываться нужно до досылки, чтобы не потерять события между ними.
+func (s *PullRequestService) SubscribeReviewEvents(ctx context.Context, userID string) (<-chan entities.PREvent, func(), error) {
+	ctx, span := tracer.Start(ctx, "PullRequestService.SubscribeReviewEvents")
+	defer span.End()
+
+	exists, err := s.userRepo.IsUserExist(ctx, userID)
+	if err != nil {
+		s.log.Error("не удалось проверить существует ли пользователь", "error", err)
+		return nil, nil, err
+	}
+	if !exists {
+		s.log.Error("пользователь не существует", "error", errs.ErrNotFound, "user ID", userID)
+		return nil, nil, errs.ErrNotFound
+	}
+
+	events, unsubscribe, err := s.broker.Subscribe(userID)
+	if err != nil {
+		s.log.Error("не удалось подписаться на события ревью", "error", err, "user ID", userID)
+		return nil, nil, err
+	}
+	return events, unsubscribe, nil
+}
+
+// GetUserReviewEvents возвращает очередную страницу событий ревью пользователя из журнала pr с id больше afterID.
+// Пустой результат означает, что журнал дочитан.
+func (s *PullRequestService) GetUserReviewEvents(ctx context.Context, userID string, afterID int64) ([]entities.PREvent, error) {
+	ctx, span := tracer.Start(ctx, "PullRequestService.GetUserReviewEvents")
+	defer span.End()
+
+	events, err := s.prRepo.GetUserReviewEvents(ctx, userID, afterID, reviewEventsPage)
+	if err != nil {
+		s.log.Error("не удалось получить события ревью пользователя", "error", err, "user ID", userID)
+		return nil, err
+	}
+	return events, nil
+}
+
+// publishReviewEvents откладывает рассылку событий ревью до коммита. Получатели — те же,
+// что отбирает GetUserReviewEvents, чтобы живой поток и досылка из журнала не расходились.
+func (s *PullRequestService) publishReviewEvents(ctx context.Context, events []entities.PREvent) error {
+	type delivery struct {
+		userID string
+		event  entities.PREvent
+	}
+
+	var deliveries []delivery
+	for _, event := range events {
+		switch event.Type {
+		case enums.EventReviewerAssigned:
+			deliveries = append(deliveries, delivery{event.ReviewerID, event})
+		case enums.EventReviewerReplaced:
+			deliveries = append(deliveries, delivery{event.ReviewerID, event})
+			if event.OldReviewerID != "" {
+				deliveries = append(deliveries, delivery{event.OldReviewerID, event})
+			}
+		case enums.EventMerged:
+			pr, err := s.prRepo.GetPR(ctx, event.PullRequestID)
+			if err != nil {
+				s.log.Error("не удалось получить pr", "error", err, "pull request ID", event.PullRequestID)
+				return err
+			}
+			for _, reviewer := range pr.Reviewers {
+				deliveries = append(deliveries, delivery{reviewer.UserID, event})
+			}
+		}
+	}
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	s.tx.AfterCommit(ctx, func() {
+		for _, d := range deliveries {
+			s.broker.Publish(d.userID, d.event)
+		}
+	})
+	return nil
+}
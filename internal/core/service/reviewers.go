@@ -0,0 +1,357 @@
+package service
+
+import (
+	"PRReviewer/internal/core/dto"
+	"PRReviewer/internal/core/entities"
+	"PRReviewer/internal/core/errs"
+	"PRReviewer/internal/core/selector"
+	"context"
+	"errors"
+	"slices"
+)
+
+// reviewerPool кеширует команды, правила владения кодом, нагрузку и очередь назначений кандидатов в рамках одной операции,
+// чтобы подбор ревьюеров для многих pr не делал запросов на каждый pr.
+// Нагрузка и порядковый номер последнего назначения обновляются при каждом выборе,
+// поэтому последующие выборы их учитывают. Исключённые пользователи не выбираются ни в одной команде пула.
+type reviewerPool struct {
+	teams          map[string]*dto.Team
+	ownership      map[string]*dto.TeamOwnership
+	loads          map[string]int
+	lastAssigned   map[string]int64
+	lastAssignment int64
+	excluded       map[string]bool
+}
+
+func newReviewerPool() *reviewerPool {
+	return &reviewerPool{
+		teams:        make(map[string]*dto.Team),
+		ownership:    make(map[string]*dto.TeamOwnership),
+		loads:        make(map[string]int),
+		lastAssigned: make(map[string]int64),
+		excluded:     make(map[string]bool),
+	}
+}
+
+func (s *PullRequestService) poolTeam(ctx context.Context, pool *reviewerPool, teamName string) (*dto.Team, error) {
+	if team, ok := pool.teams[teamName]; ok {
+		return team, nil
+	}
+
+	team, err := s.TeamRepo.GetTeamByName(ctx, teamName)
+	if err != nil {
+		s.log.Error("не удалось получить команду по названию", "error", err, "team name", teamName)
+		return nil, err
+	}
+
+	pool.teams[teamName] = team
+	return team, nil
+}
+
+// lockCandidates загружает в пул команды и их запасные команды и одним запросом блокирует всех их участников.
+// Блокировка всего объединения сразу в порядке id даёт один порядок захвата строк для всех операций:
+// если блокировать команду и запасные команды по очереди, два создания pr в командах, запасных друг для друга,
+// захватят одних и тех же пользователей в обратном порядке и попадут во взаимную блокировку.
+func (s *PullRequestService) lockCandidates(ctx context.Context, pool *reviewerPool, teams ...*dto.Team) error {
+	candidateIDs := make([]string, 0)
+	for _, team := range teams {
+		candidateIDs = append(candidateIDs, s.memberIDs(team)...)
+
+		for _, fallbackName := range team.FallbackTeams {
+			fallback, err := s.poolTeam(ctx, pool, fallbackName)
+			if err != nil {
+				return err
+			}
+			if fallback.ArchivedAt != nil {
+				continue
+			}
+			candidateIDs = append(candidateIDs, s.memberIDs(fallback)...)
+		}
+	}
+
+	slices.Sort(candidateIDs)
+	return s.loadCandidates(ctx, pool, slices.Compact(candidateIDs))
+}
+
+// poolOwnership возвращает правила владения кодом команды, читая их из базы один раз за операцию.
+func (s *PullRequestService) poolOwnership(ctx context.Context, pool *reviewerPool, team *dto.Team) (*dto.TeamOwnership, error) {
+	if ownership, ok := pool.ownership[team.ID]; ok {
+		return ownership, nil
+	}
+
+	ownership, err := s.poolOwnership(ctx, pool, team)
+	if err != nil {
+		return nil, err
+	}
+
+	pool.ownership[team.ID] = ownership
+	return ownership, nil
+}
+
+// loadCandidates блокирует строки кандидатов, которых ещё нет в пуле, и читает их нагрузку
+// и последние назначения из журнала событий.
+// Блокировка до подсчёта нужна, чтобы параллельная транзакция дождалась коммита и увидела
+// уже назначенные ревью: так два создания pr не выберут одного и того же "свободного" ревьюера.
+func (s *PullRequestService) loadCandidates(ctx context.Context, pool *reviewerPool, userIDs []string) error {
+	missing := make([]string, 0, len(userIDs))
+	for _, id := range userIDs {
+		if _, ok := pool.loads[id]; !ok {
+			missing = append(missing, id)
+		}
+	}
+
+	if len(missing) == 0 {
+		return nil
+	}
+
+	err := s.userRepo.LockUsers(ctx, missing)
+	if err != nil {
+		s.log.Error("не удалось заблокировать кандидатов в ревьюеры", "error", err)
+		return err
+	}
+
+	loads, err := s.prRepo.CountOpenReviews(ctx, missing)
+	if err != nil {
+		s.log.Error("не удалось посчитать открытые ревью", "error", err)
+		return err
+	}
+
+	assignments, err := s.prRepo.GetLastAssignments(ctx, missing)
+	if err != nil {
+		s.log.Error("не удалось получить последние назначения ревьюеров", "error", err)
+		return err
+	}
+
+	for _, id := range missing {
+		pool.loads[id] = loads[id]
+		pool.lastAssigned[id] = assignments[id]
+		pool.lastAssignment = max(pool.lastAssignment, assignments[id])
+	}
+	return nil
+}
+
+// selectReviewers сначала назначает владельцев изменённых файлов, а оставшиеся места
+// заполняет обычной стратегией команды.
+func (s *PullRequestService) selectReviewers(ctx context.Context, pool *reviewerPool, authorID string, team *dto.Team, changedFiles []string) ([]entities.Reviewer, error) {
+	reviewers, err := s.getOwnerReviewers(ctx, pool, authorID, team, changedFiles, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	remaining := team.ReviewersPerPR - len(reviewers)
+	if remaining <= 0 {
+		return reviewers, nil
+	}
+
+	rest, err := s.getReviewers(ctx, pool, authorID, team, remaining, s.reviewersToIDs(reviewers)...)
+	if err != nil {
+		if len(reviewers) > 0 && errors.Is(err, errs.ErrNoReviewersAvailable) {
+			return reviewers, nil
+		}
+		return nil, err
+	}
+
+	return append(reviewers, rest...), nil
+}
+
+// getOwnerReviewers назначает по одному ревьюеру на каждое совпавшее правило владения кодом,
+// если правило ещё не покрыто уже выбранным ревьюером или одним из assigned - ревьюеров,
+// которые остаются на pr. Участники assigned повторно не выбираются.
+func (s *PullRequestService) getOwnerReviewers(ctx context.Context, pool *reviewerPool, authorID string, team *dto.Team, changedFiles []string, assigned []string) ([]entities.Reviewer, error) {
+	reviewers := make([]entities.Reviewer, 0)
+	if len(changedFiles) == 0 {
+		return reviewers, nil
+	}
+
+	ownership, err := s.TeamRepo.GetTeamOwnership(ctx, team.ID)
+	if err != nil {
+		s.log.Error("не удалось получить правила владения кодом", "error", err, "team name", team.TeamName)
+		return nil, err
+	}
+
+	matched := selector.MatchOwners(ownership.Rules, changedFiles)
+	if len(matched) == 0 {
+		return reviewers, nil
+	}
+
+	groups := make(map[string][]string, len(ownership.Groups))
+	for _, group := range ownership.Groups {
+		groups[group.Name] = group.Members
+	}
+
+	excludeMap := map[string]bool{authorID: true}
+	covering := make([]string, 0, len(assigned))
+	for _, id := range assigned {
+		excludeMap[id] = true
+		covering = append(covering, id)
+	}
+
+	for _, owners := range matched {
+		ownerIDs := make(map[string]bool)
+		for _, owner := range owners {
+			if selector.IsGroupRef(owner) {
+				for _, userID := range groups[selector.GroupName(owner)] {
+					ownerIDs[userID] = true
+				}
+				continue
+			}
+			ownerIDs[owner] = true
+		}
+
+		covered := slices.ContainsFunc(covering, func(id string) bool {
+			return ownerIDs[id]
+		})
+		if covered {
+			continue
+		}
+
+		ownerTeam := &dto.Team{
+			ID:           team.ID,
+			TeamName:     team.TeamName,
+			Members:      make([]dto.TeamMember, 0, len(ownerIDs)),
+			TeamSettings: team.TeamSettings,
+		}
+		for _, member := range team.Members {
+			if ownerIDs[member.UserID] {
+				ownerTeam.Members = append(ownerTeam.Members, member)
+			}
+		}
+
+		picked, _, err := s.pickFromTeam(ctx, pool, ownerTeam, 1, excludeMap)
+		if err != nil {
+			return nil, err
+		}
+		if len(picked) == 0 {
+			s.log.Warn("нет доступного владельца кода для правила", "team name", team.TeamName, "owners", owners)
+			continue
+		}
+
+		reviewers = append(reviewers, entities.Reviewer{UserID: picked[0], IsActive: true})
+		excludeMap[picked[0]] = true
+		covering = append(covering, picked[0])
+	}
+
+	return reviewers, nil
+}
+
+// getReviewers подбирает ревьюеров в команде автора, а недостающие места заполняет
+// участниками запасных команд в заданном порядке. Запасные команды из архива пропускаются.
+func (s *PullRequestService) getReviewers(ctx context.Context, pool *reviewerPool, authorID string, team *dto.Team, limit int, excludeMembers ...string) ([]entities.Reviewer, error) {
+	excludeMap := make(map[string]bool)
+	for _, excludedID := range excludeMembers {
+		excludeMap[excludedID] = true
+	}
+	excludeMap[authorID] = true
+
+	reviewers := make([]entities.Reviewer, 0, limit)
+
+	picked, saturated, err := s.pickFromTeam(ctx, pool, team, limit, excludeMap)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range picked {
+		reviewers = append(reviewers, entities.Reviewer{UserID: id, IsActive: true})
+		excludeMap[id] = true
+	}
+
+	for _, fallbackName := range team.FallbackTeams {
+		if len(reviewers) >= limit {
+			break
+		}
+
+		fallback, err := s.poolTeam(ctx, pool, fallbackName)
+		if err != nil {
+			return nil, err
+		}
+		if fallback.ArchivedAt != nil {
+			continue
+		}
+
+		picked, fallbackSaturated, err := s.pickFromTeam(ctx, pool, fallback, limit-len(reviewers), excludeMap)
+		if err != nil {
+			return nil, err
+		}
+		saturated = saturated || fallbackSaturated
+
+		for _, id := range picked {
+			reviewers = append(reviewers, entities.Reviewer{UserID: id, IsActive: true, FallbackTeam: fallback.TeamName})
+			excludeMap[id] = true
+		}
+	}
+
+	if len(reviewers) == 0 {
+		s.metrics.NoCandidate(team.TeamName)
+		if saturated {
+			s.log.Error("все кандидаты достигли лимита открытых ревью", "error", errs.ErrReviewersAtCapacity, "team name", team.TeamName)
+			return nil, errs.ErrReviewersAtCapacity
+		}
+		s.log.Error("нет доступных ревьюеров", "error", errs.ErrNoReviewersAvailable)
+		return nil, errs.ErrNoReviewersAvailable
+	}
+
+	return reviewers, nil
+}
+
+// pickFromTeam выбирает до limit ревьюеров из одной команды по её стратегии.
+// Второе значение сообщает, что активные кандидаты были, но все достигли лимита открытых ревью.
+func (s *PullRequestService) pickFromTeam(ctx context.Context, pool *reviewerPool, team *dto.Team, limit int, excludeMap map[string]bool) ([]string, bool, error) {
+	candidates := make([]selector.Candidate, 0)
+	limits := make(map[string]*int, len(team.Members))
+
+	for _, member := range team.Members {
+		if member.IsActive && !excludeMap[member.UserID] && !pool.excluded[member.UserID] {
+			candidates = append(candidates, selector.Candidate{
+				UserID: member.UserID,
+				Weight: member.ReviewWeight,
+			})
+			limits[member.UserID] = member.MaxOpenReviews
+		}
+	}
+
+	if len(candidates) == 0 {
+		return nil, false, nil
+	}
+
+	ids := make([]string, len(candidates))
+	for i, candidate := range candidates {
+		ids[i] = candidate.UserID
+	}
+
+	err := s.loadCandidates(ctx, pool, ids)
+	if err != nil {
+		return nil, false, err
+	}
+
+	available := make([]selector.Candidate, 0, len(candidates))
+	for _, candidate := range candidates {
+		candidate.OpenReviews = pool.loads[candidate.UserID]
+		candidate.LastAssigned = pool.lastAssigned[candidate.UserID]
+		if limit := limits[candidate.UserID]; limit != nil && candidate.OpenReviews >= *limit {
+			continue
+		}
+		available = append(available, candidate)
+	}
+
+	if len(available) == 0 {
+		return nil, true, nil
+	}
+
+	reviewerSelector := s.selectors.Get(team.ReviewerStrategy)
+	picked := reviewerSelector.Select(available, limit)
+
+	for _, id := range picked {
+		pool.loads[id]++
+		pool.lastAssignment++
+		pool.lastAssigned[id] = pool.lastAssignment
+	}
+
+	return picked, false, nil
+}
+
+func (s *PullRequestService) reviewersToIDs(reviewers []entities.Reviewer) []string {
+	ids := make([]string, len(reviewers))
+	for i, reviewer := range reviewers {
+		ids[i] = reviewer.UserID
+	}
+	return ids
+}
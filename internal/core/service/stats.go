@@ -0,0 +1,70 @@
+package service
+
+import (
+	"PRReviewer/internal/core/dto"
+	"PRReviewer/internal/core/errs"
+	"context"
+	"log/slog"
+	"strings"
+)
+
+type StatsRepo interface {
+	GetReviewerStats(ctx context.Context, query dto.StatsQuery) ([]dto.ReviewerStats, error)
+	GetTeamStats(ctx context.Context, query dto.StatsQuery) ([]dto.TeamStats, error)
+}
+
+type StatsService struct {
+	statsRepo StatsRepo
+	teamRepo  TeamRepo
+	log       *slog.Logger
+}
+
+func NewStatsService(statsRepo StatsRepo, teamRepo TeamRepo, log *slog.Logger) *StatsService {
+	return &StatsService{statsRepo: statsRepo, teamRepo: teamRepo, log: log}
+}
+
+func (s *StatsService) GetReviewerStats(ctx context.Context, query dto.StatsQuery) (*dto.ReviewerStatsResponse, error) {
+	query, err := s.normalizeQuery(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	reviewers, err := s.statsRepo.GetReviewerStats(ctx, query)
+	if err != nil {
+		s.log.Error("не удалось посчитать статистику ревьюеров", "error", err, "team name", query.TeamName)
+		return nil, err
+	}
+	return &dto.ReviewerStatsResponse{TeamName: query.TeamName, From: query.From, To: query.To, Reviewers: reviewers}, nil
+}
+
+func (s *StatsService) GetTeamStats(ctx context.Context, query dto.StatsQuery) (*dto.TeamStatsResponse, error) {
+	query, err := s.normalizeQuery(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	teams, err := s.statsRepo.GetTeamStats(ctx, query)
+	if err != nil {
+		s.log.Error("не удалось посчитать статистику команд", "error", err, "team name", query.TeamName)
+		return nil, err
+	}
+	return &dto.TeamStatsResponse{From: query.From, To: query.To, Teams: teams}, nil
+}
+
+// normalizeQuery проверяет, что запрошенная команда существует, чтобы опечатка не выглядела как пустая статистика.
+func (s *StatsService) normalizeQuery(ctx context.Context, query dto.StatsQuery) (dto.StatsQuery, error) {
+	query.TeamName = strings.TrimSpace(query.TeamName)
+	if query.TeamName == "" {
+		return query, nil
+	}
+
+	exists, err := s.teamRepo.IsTeamExistsByName(ctx, query.TeamName)
+	if err != nil {
+		s.log.Error("не удалось проверить существует ли комманда", "error", err)
+		return query, err
+	}
+	if !exists {
+		return query, errs.ErrNotFound
+	}
+	return query, nil
+}
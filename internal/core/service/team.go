@@ -2,31 +2,60 @@ package service
 
 import (
 	"PRReviewer/internal/core/dto"
+	"PRReviewer/internal/core/entities"
+	"PRReviewer/internal/core/enums"
 	"PRReviewer/internal/core/errs"
+	"PRReviewer/internal/core/selector"
 	"context"
 	"log/slog"
+	"slices"
 	"strings"
 )
 
 type TeamRepo interface {
 	IsTeamExistsByName(ctx context.Context, teamName string) (bool, error)
-	CreateTeam(ctx context.Context, teamName string) (string, error)
+	CreateTeam(ctx context.Context, teamName string, settings dto.TeamSettings) (string, error)
 	AddMembersToTeam(ctx context.Context, teamID string, users []dto.TeamMember) error
 	GetTeamByName(ctx context.Context, teamName string) (*dto.Team, error)
+	UpdateTeamSettings(ctx context.Context, update dto.UpdateTeamSettingsRequest) error
+	SetTeamFallbacks(ctx context.Context, teamID string, fallbackTeams []string) error
+	SetTeamOwnership(ctx context.Context, teamID string, ownership dto.TeamOwnership) error
+	GetTeamOwnership(ctx context.Context, teamID string) (*dto.TeamOwnership, error)
+	RemoveMemberFromTeam(ctx context.Context, teamID string, userID string) error
+	RenameTeam(ctx context.Context, teamID string, newName string) error
+	ArchiveTeam(ctx context.Context, teamID string) error
+	DeleteTeam(ctx context.Context, teamID string) error
+	CountOpenTeamPRs(ctx context.Context, teamID string) (int, error)
+	AddTeamEvent(ctx context.Context, event entities.TeamEvent) error
+	GetTeamEvents(ctx context.Context, teamName string) ([]entities.TeamEvent, error)
+	ListTeams(ctx context.Context, query dto.ListTeamsQuery) ([]dto.TeamSummary, string, error)
+}
+
+const defaultReviewersPerPR = 2
+
+// TeamPullRequests - операции над pr, которые нужны командам: переназначение ревью при изменении
+// состава и закрытие pr при удалении команды.
+type TeamPullRequests interface {
+	ReviewReassigner
+	CloseTeamPullRequests(ctx context.Context, teamID string) ([]string, error)
 }
 
 type TeamService struct {
-	teamRepo TeamRepo
-	UserRepo UserRepo
-	tx       Transactor
-	log      *slog.Logger
+	teamRepo   TeamRepo
+	UserRepo   UserRepo
+	reassigner TeamPullRequests
+	tx         Transactor
+	log        *slog.Logger
 }
 
-func NewTeamService(teamRepo TeamRepo, userRepo UserRepo, tx Transactor, log *slog.Logger) *TeamService {
-	return &TeamService{teamRepo: teamRepo, UserRepo: userRepo, tx: tx, log: log}
+func NewTeamService(teamRepo TeamRepo, userRepo UserRepo, reassigner TeamPullRequests, tx Transactor, log *slog.Logger) *TeamService {
+	return &TeamService{teamRepo: teamRepo, UserRepo: userRepo, reassigner: reassigner, tx: tx, log: log}
 }
 
 func (s *TeamService) CreateTeam(ctx context.Context, team *dto.Team) (*dto.Team, error) {
+	ctx, span := tracer.Start(ctx, "TeamService.CreateTeam")
+	defer span.End()
+
 	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
 		exists, err := s.teamRepo.IsTeamExistsByName(ctx, team.TeamName)
 		if err != nil {
@@ -39,12 +68,35 @@ func (s *TeamService) CreateTeam(ctx context.Context, team *dto.Team) (*dto.Team
 			return errs.ErrAlreadyExists
 		}
 
-		id, err := s.teamRepo.CreateTeam(ctx, team.TeamName)
+		if team.ReviewerStrategy == "" {
+			team.ReviewerStrategy = enums.StrategyRandom
+		}
+		if team.ReviewersPerPR == 0 {
+			team.ReviewersPerPR = defaultReviewersPerPR
+		}
+		if team.MergePolicy == nil {
+			policy := defaultMergePolicy
+			team.MergePolicy = &policy
+		}
+
+		id, err := s.teamRepo.CreateTeam(ctx, team.TeamName, team.TeamSettings)
 		if err != nil {
 			s.log.Error("не удалось создать команду", "error", err)
 			return err
 		}
 
+		team.FallbackTeams, err = s.normalizeFallbacks(team.TeamName, team.FallbackTeams)
+		if err != nil {
+			s.log.Error("некорректный список запасных команд", "error", err)
+			return err
+		}
+
+		err = s.teamRepo.SetTeamFallbacks(ctx, id, team.FallbackTeams)
+		if err != nil {
+			s.log.Error("не удалось сохранить запасные команды", "error", err)
+			return err
+		}
+
 		err = s.UserRepo.AddUsers(ctx, team.Members)
 		if err != nil {
 			s.log.Error("не удалось добавить пользователя", "error", err)
@@ -57,7 +109,7 @@ func (s *TeamService) CreateTeam(ctx context.Context, team *dto.Team) (*dto.Team
 			return err
 		}
 
-		return nil
+		return s.recordTeamEvent(ctx, entities.TeamEvent{TeamID: id, TeamName: team.TeamName, Type: enums.TeamEventCreated})
 	})
 	if err != nil {
 		s.log.Error("транзакция завершилась с ошибкой", "error", err)
@@ -67,6 +119,9 @@ func (s *TeamService) CreateTeam(ctx context.Context, team *dto.Team) (*dto.Team
 }
 
 func (s *TeamService) GetTeamByName(ctx context.Context, teamName string) (*dto.Team, error) {
+	ctx, span := tracer.Start(ctx, "TeamService.GetTeamByName")
+	defer span.End()
+
 	team, err := s.teamRepo.GetTeamByName(ctx, strings.TrimSpace(teamName))
 	if err != nil {
 		s.log.Error("не удалось получить команду по названию", "error", err)
@@ -74,3 +129,279 @@ func (s *TeamService) GetTeamByName(ctx context.Context, teamName string) (*dto.
 	}
 	return team, nil
 }
+
+func (s *TeamService) ListTeams(ctx context.Context, query dto.ListTeamsQuery) (*dto.ListTeamsResponse, error) {
+	ctx, span := tracer.Start(ctx, "TeamService.ListTeams")
+	defer span.End()
+
+	query.Name = strings.TrimSpace(query.Name)
+	query.Limit = pageLimit(query.Limit)
+
+	teams, next, err := s.teamRepo.ListTeams(ctx, query)
+	if err != nil {
+		s.log.Error("не удалось получить список команд", "error", err)
+		return nil, err
+	}
+	return &dto.ListTeamsResponse{Teams: teams, NextCursor: next}, nil
+}
+
+func (s *TeamService) UpdateSettings(ctx context.Context, update dto.UpdateTeamSettingsRequest) (*dto.Team, error) {
+	ctx, span := tracer.Start(ctx, "TeamService.UpdateSettings")
+	defer span.End()
+
+	update.TeamName = strings.TrimSpace(update.TeamName)
+
+	var team *dto.Team
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := s.teamRepo.UpdateTeamSettings(ctx, update)
+		if err != nil {
+			s.log.Error("не удалось обновить настройки команды", "error", err, "team name", update.TeamName)
+			return err
+		}
+
+		team, err = s.teamRepo.GetTeamByName(ctx, update.TeamName)
+		if err != nil {
+			s.log.Error("не удалось получить команду по названию", "error", err, "team name", update.TeamName)
+			return err
+		}
+
+		if update.FallbackTeams == nil {
+			return nil
+		}
+
+		fallbacks, err := s.normalizeFallbacks(team.TeamName, update.FallbackTeams)
+		if err != nil {
+			s.log.Error("некорректный список запасных команд", "error", err)
+			return err
+		}
+
+		err = s.teamRepo.SetTeamFallbacks(ctx, team.ID, fallbacks)
+		if err != nil {
+			s.log.Error("не удалось сохранить запасные команды", "error", err, "team name", update.TeamName)
+			return err
+		}
+
+		team, err = s.teamRepo.GetTeamByName(ctx, update.TeamName)
+		if err != nil {
+			s.log.Error("не удалось получить команду по названию", "error", err, "team name", update.TeamName)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		s.log.Error("транзакция завершилась с ошибкой", "error", err)
+		return nil, err
+	}
+	return team, nil
+}
+
+func (s *TeamService) normalizeFallbacks(teamName string, fallbacks []string) ([]string, error) {
+	normalized := make([]string, 0, len(fallbacks))
+	seen := make(map[string]bool, len(fallbacks))
+
+	for _, fallback := range fallbacks {
+		fallback = strings.TrimSpace(fallback)
+		if fallback == "" || fallback == teamName {
+			return nil, errs.ErrInvalidFallbackTeam
+		}
+		if seen[fallback] {
+			continue
+		}
+		seen[fallback] = true
+		normalized = append(normalized, fallback)
+	}
+
+	return normalized, nil
+}
+
+func (s *TeamService) SetOwnership(ctx context.Context, ownership dto.TeamOwnership) (*dto.TeamOwnership, error) {
+	ctx, span := tracer.Start(ctx, "TeamService.SetOwnership")
+	defer span.End()
+
+	ownership.TeamName = strings.TrimSpace(ownership.TeamName)
+
+	var result *dto.TeamOwnership
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		team, err := s.teamRepo.GetTeamByName(ctx, ownership.TeamName)
+		if err != nil {
+			s.log.Error("не удалось получить команду по названию", "error", err, "team name", ownership.TeamName)
+			return err
+		}
+
+		err = s.validateOwnership(team, ownership)
+		if err != nil {
+			s.log.Error("некорректные правила владения кодом", "error", err, "team name", ownership.TeamName)
+			return err
+		}
+
+		err = s.teamRepo.SetTeamOwnership(ctx, team.ID, ownership)
+		if err != nil {
+			s.log.Error("не удалось сохранить правила владения кодом", "error", err)
+			return err
+		}
+
+		result, err = s.teamRepo.GetTeamOwnership(ctx, team.ID)
+		if err != nil {
+			s.log.Error("не удалось получить правила владения кодом", "error", err)
+			return err
+		}
+		result.TeamName = team.TeamName
+		return nil
+	})
+	if err != nil {
+		s.log.Error("транзакция завершилась с ошибкой", "error", err)
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *TeamService) GetOwnership(ctx context.Context, teamName string) (*dto.TeamOwnership, error) {
+	ctx, span := tracer.Start(ctx, "TeamService.GetOwnership")
+	defer span.End()
+
+	team, err := s.teamRepo.GetTeamByName(ctx, strings.TrimSpace(teamName))
+	if err != nil {
+		s.log.Error("не удалось получить команду по названию", "error", err)
+		return nil, err
+	}
+
+	ownership, err := s.teamRepo.GetTeamOwnership(ctx, team.ID)
+	if err != nil {
+		s.log.Error("не удалось получить правила владения кодом", "error", err)
+		return nil, err
+	}
+	ownership.TeamName = team.TeamName
+	return ownership, nil
+}
+
+// validateOwnership проверяет, что группы состоят из участников команды,
+// а владельцы в правилах - это участники команды или объявленные группы вида @name.
+func (s *TeamService) validateOwnership(team *dto.Team, ownership dto.TeamOwnership) error {
+	members := make(map[string]bool, len(team.Members))
+	for _, member := range team.Members {
+		members[member.UserID] = true
+	}
+
+	groups := make(map[string]bool, len(ownership.Groups))
+	for _, group := range ownership.Groups {
+		if groups[group.Name] || selector.IsGroupRef(group.Name) {
+			return errs.ErrInvalidOwnership
+		}
+		groups[group.Name] = true
+
+		for _, userID := range group.Members {
+			if !members[userID] {
+				return errs.ErrInvalidOwnership
+			}
+		}
+	}
+
+	for _, rule := range ownership.Rules {
+		if strings.TrimSpace(rule.Pattern) == "" {
+			return errs.ErrInvalidOwnership
+		}
+		for _, owner := range rule.Owners {
+			if selector.IsGroupRef(owner) && groups[selector.GroupName(owner)] {
+				continue
+			}
+			if !members[owner] {
+				return errs.ErrInvalidOwnership
+			}
+		}
+	}
+
+	return nil
+}
+
+// DeactivateUsers атомарно деактивирует участников команды и переназначает их открытые ревью.
+func (s *TeamService) DeactivateUsers(ctx context.Context, req dto.DeactivateTeamUsersRequest) (*dto.DeactivateTeamUsersResponse, error) {
+	ctx, span := tracer.Start(ctx, "TeamService.DeactivateUsers")
+	defer span.End()
+
+	req.TeamName = strings.TrimSpace(req.TeamName)
+
+	var response *dto.DeactivateTeamUsersResponse
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		team, err := s.teamRepo.GetTeamByName(ctx, req.TeamName)
+		if err != nil {
+			s.log.Error("не удалось получить команду по названию", "error", err, "team name", req.TeamName)
+			return err
+		}
+
+		members := make(map[string]bool, len(team.Members))
+		for _, member := range team.Members {
+			members[member.UserID] = true
+		}
+
+		userIDs := make([]string, 0, len(team.Members))
+		if req.All {
+			for _, member := range team.Members {
+				userIDs = append(userIDs, member.UserID)
+			}
+		} else {
+			for _, userID := range req.UserIDs {
+				if !members[userID] {
+					s.log.Error("пользователь не состоит в команде", "error", errs.ErrNotTeamMember, "user ID", userID)
+					return errs.ErrNotTeamMember
+				}
+				if !slices.Contains(userIDs, userID) {
+					userIDs = append(userIDs, userID)
+				}
+			}
+		}
+
+		err = s.UserRepo.SetUsersActive(ctx, userIDs, false)
+		if err != nil {
+			s.log.Error("не удалось деактивировать пользователей", "error", err)
+			return err
+		}
+
+		report, err := s.reassigner.ReassignReviews(ctx, userIDs)
+		if err != nil {
+			s.log.Error("не удалось переназначить ревью", "error", err)
+			return err
+		}
+
+		response = &dto.DeactivateTeamUsersResponse{
+			TeamName:     team.TeamName,
+			Deactivated:  userIDs,
+			PullRequests: groupByPullRequest(report),
+		}
+		return nil
+	})
+	if err != nil {
+		s.log.Error("транзакция завершилась с ошибкой", "error", err)
+		return nil, err
+	}
+	return response, nil
+}
+
+func groupByPullRequest(report *dto.ReassignmentReport) []dto.PRReassignmentReport {
+	reports := make([]dto.PRReassignmentReport, 0)
+	index := make(map[string]int)
+
+	get := func(prID string) *dto.PRReassignmentReport {
+		i, ok := index[prID]
+		if !ok {
+			i = len(reports)
+			index[prID] = i
+			reports = append(reports, dto.PRReassignmentReport{
+				PullRequestID: prID,
+				Reassigned:    []dto.ReassignmentResult{},
+				Failed:        []dto.ReassignmentResult{},
+			})
+		}
+		return &reports[i]
+	}
+
+	for _, result := range report.Reassigned {
+		pr := get(result.PullRequestID)
+		pr.Reassigned = append(pr.Reassigned, result)
+	}
+	for _, result := range report.Failed {
+		pr := get(result.PullRequestID)
+		pr.Failed = append(pr.Failed, result)
+	}
+
+	return reports
+}
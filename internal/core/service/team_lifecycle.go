@@ -0,0 +1,208 @@
+package service
+
+import (
+	"PRReviewer/internal/core/auth"
+	"PRReviewer/internal/core/dto"
+	"PRReviewer/internal/core/entities"
+	"PRReviewer/internal/core/enums"
+	"PRReviewer/internal/core/errs"
+	"context"
+	"strings"
+)
+
+// RenameTeam меняет название команды. Идентификатор команды, а вместе с ним pr, участники
+// и настройки остаются прежними.
+func (s *TeamService) RenameTeam(ctx context.Context, req dto.RenameTeamRequest) (*dto.Team, error) {
+	ctx, span := tracer.Start(ctx, "TeamService.RenameTeam")
+	defer span.End()
+
+	req.TeamName = strings.TrimSpace(req.TeamName)
+	req.NewName = strings.TrimSpace(req.NewName)
+
+	var team *dto.Team
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		current, err := s.teamRepo.GetTeamByName(ctx, req.TeamName)
+		if err != nil {
+			s.log.Error("не удалось получить команду по названию", "error", err, "team name", req.TeamName)
+			return err
+		}
+
+		exists, err := s.teamRepo.IsTeamExistsByName(ctx, req.NewName)
+		if err != nil {
+			s.log.Error("не удалось проверить существует ли комманда", "error", err)
+			return err
+		}
+		if exists {
+			s.log.Error("команда с таким названием уже существует", "error", errs.ErrAlreadyExists, "team name", req.NewName)
+			return errs.ErrAlreadyExists
+		}
+
+		err = s.teamRepo.RenameTeam(ctx, current.ID, req.NewName)
+		if err != nil {
+			s.log.Error("не удалось переименовать команду", "error", err, "team name", req.TeamName)
+			return err
+		}
+
+		err = s.recordTeamEvent(ctx, entities.TeamEvent{
+			TeamID:      current.ID,
+			TeamName:    req.NewName,
+			OldTeamName: current.TeamName,
+			Type:        enums.TeamEventRenamed,
+		})
+		if err != nil {
+			return err
+		}
+
+		team, err = s.teamRepo.GetTeamByName(ctx, req.NewName)
+		if err != nil {
+			s.log.Error("не удалось получить команду по названию", "error", err, "team name", req.NewName)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		s.log.Error("транзакция завершилась с ошибкой", "error", err)
+		return nil, err
+	}
+	return team, nil
+}
+
+// ArchiveTeam выводит команду из работы: в ней нельзя создавать pr и добавлять участников,
+// но сама команда, её pr и история сохраняются.
+func (s *TeamService) ArchiveTeam(ctx context.Context, req dto.ArchiveTeamRequest) (*dto.Team, error) {
+	ctx, span := tracer.Start(ctx, "TeamService.ArchiveTeam")
+	defer span.End()
+
+	req.TeamName = strings.TrimSpace(req.TeamName)
+
+	var team *dto.Team
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		current, err := s.teamRepo.GetTeamByName(ctx, req.TeamName)
+		if err != nil {
+			s.log.Error("не удалось получить команду по названию", "error", err, "team name", req.TeamName)
+			return err
+		}
+
+		if current.ArchivedAt != nil {
+			team = current
+			return nil
+		}
+
+		err = s.teamRepo.ArchiveTeam(ctx, current.ID)
+		if err != nil {
+			s.log.Error("не удалось архивировать команду", "error", err, "team name", req.TeamName)
+			return err
+		}
+
+		err = s.recordTeamEvent(ctx, entities.TeamEvent{
+			TeamID:   current.ID,
+			TeamName: current.TeamName,
+			Type:     enums.TeamEventArchived,
+		})
+		if err != nil {
+			return err
+		}
+
+		team, err = s.teamRepo.GetTeamByName(ctx, req.TeamName)
+		if err != nil {
+			s.log.Error("не удалось получить команду по названию", "error", err, "team name", req.TeamName)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		s.log.Error("транзакция завершилась с ошибкой", "error", err)
+		return nil, err
+	}
+	return team, nil
+}
+
+// DeleteTeam удаляет команду. Пока у команды есть открытые pr, удаление возможно только с force.
+// Открытые pr и черновики команды закрываются до удаления: без команды им не из кого подбирать ревьюеров.
+func (s *TeamService) DeleteTeam(ctx context.Context, req dto.DeleteTeamRequest) error {
+	ctx, span := tracer.Start(ctx, "TeamService.DeleteTeam")
+	defer span.End()
+
+	req.TeamName = strings.TrimSpace(req.TeamName)
+
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		team, err := s.teamRepo.GetTeamByName(ctx, req.TeamName)
+		if err != nil {
+			s.log.Error("не удалось получить команду по названию", "error", err, "team name", req.TeamName)
+			return err
+		}
+
+		openPRs, err := s.teamRepo.CountOpenTeamPRs(ctx, team.ID)
+		if err != nil {
+			s.log.Error("не удалось посчитать открытые pr команды", "error", err, "team name", req.TeamName)
+			return err
+		}
+		if openPRs > 0 && !req.Force {
+			s.log.Error("у команды есть открытые pr", "error", errs.ErrTeamHasOpenPRs, "team name", req.TeamName, "open", openPRs)
+			return errs.ErrTeamHasOpenPRs
+		}
+
+		closed, err := s.reassigner.CloseTeamPullRequests(ctx, team.ID)
+		if err != nil {
+			s.log.Error("не удалось закрыть pr команды", "error", err, "team name", req.TeamName)
+			return err
+		}
+		if len(closed) > 0 {
+			s.log.Info("pr удаляемой команды закрыты", "team name", req.TeamName, "pull requests", closed)
+		}
+
+		err = s.teamRepo.DeleteTeam(ctx, team.ID)
+		if err != nil {
+			s.log.Error("не удалось удалить команду", "error", err, "team name", req.TeamName)
+			return err
+		}
+
+		return s.recordTeamEvent(ctx, entities.TeamEvent{
+			TeamID:   team.ID,
+			TeamName: team.TeamName,
+			Type:     enums.TeamEventDeleted,
+		})
+	})
+	if err != nil {
+		s.log.Error("транзакция завершилась с ошибкой", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (s *TeamService) GetHistory(ctx context.Context, teamName string) (*dto.TeamHistoryResponse, error) {
+	ctx, span := tracer.Start(ctx, "TeamService.GetHistory")
+	defer span.End()
+
+	teamName = strings.TrimSpace(teamName)
+
+	events, err := s.teamRepo.GetTeamEvents(ctx, teamName)
+	if err != nil {
+		s.log.Error("не удалось получить историю команды", "error", err, "team name", teamName)
+		return nil, err
+	}
+
+	if len(events) == 0 {
+		exists, err := s.teamRepo.IsTeamExistsByName(ctx, teamName)
+		if err != nil {
+			s.log.Error("не удалось проверить существует ли комманда", "error", err)
+			return nil, err
+		}
+		if !exists {
+			return nil, errs.ErrNotFound
+		}
+	}
+
+	return &dto.TeamHistoryResponse{TeamName: teamName, Events: events}, nil
+}
+
+// recordTeamEvent дописывает событие в журнал команды от имени инициатора запроса.
+func (s *TeamService) recordTeamEvent(ctx context.Context, event entities.TeamEvent) error {
+	event.ActorID = auth.ActorID(ctx)
+	err := s.teamRepo.AddTeamEvent(ctx, event)
+	if err != nil {
+		s.log.Error("не удалось записать событие команды", "error", err, "team name", event.TeamName)
+		return err
+	}
+	return nil
+}
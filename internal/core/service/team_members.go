@@ -0,0 +1,199 @@
+package service
+
+import (
+	"PRReviewer/internal/core/dto"
+	"PRReviewer/internal/core/errs"
+	"context"
+	"slices"
+	"strings"
+)
+
+// AddMembers добавляет пользователей в существующую команду. Уже состоящие в команде участники пропускаются,
+// а данные существующих пользователей не меняются: создаются только новые пользователи.
+func (s *TeamService) AddMembers(ctx context.Context, req dto.AddTeamMembersRequest) (*dto.Team, error) {
+	ctx, span := tracer.Start(ctx, "TeamService.AddMembers")
+	defer span.End()
+
+	req.TeamName = strings.TrimSpace(req.TeamName)
+
+	var team *dto.Team
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		current, err := s.teamRepo.GetTeamByName(ctx, req.TeamName)
+		if err != nil {
+			s.log.Error("не удалось получить команду по названию", "error", err, "team name", req.TeamName)
+			return err
+		}
+		if current.ArchivedAt != nil {
+			s.log.Error("нельзя добавить участников в архивную команду", "error", errs.ErrTeamArchived, "team name", req.TeamName)
+			return errs.ErrTeamArchived
+		}
+
+		err = s.UserRepo.AddMissingUsers(ctx, req.Members)
+		if err != nil {
+			s.log.Error("не удалось добавить пользователя", "error", err)
+			return err
+		}
+
+		err = s.teamRepo.AddMembersToTeam(ctx, current.ID, req.Members)
+		if err != nil {
+			s.log.Error("не удалось добавить пользователя в команду", "error", err)
+			return err
+		}
+
+		team, err = s.teamRepo.GetTeamByName(ctx, req.TeamName)
+		if err != nil {
+			s.log.Error("не удалось получить команду по названию", "error", err, "team name", req.TeamName)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		s.log.Error("транзакция завершилась с ошибкой", "error", err)
+		return nil, err
+	}
+	return team, nil
+}
+
+// RemoveMember исключает пользователя из команды и переназначает его открытые ревью на pr этой команды.
+// Пользователя нельзя оставить без команды: из единственной команды его можно только перевести.
+func (s *TeamService) RemoveMember(ctx context.Context, req dto.RemoveTeamMemberRequest) (*dto.TeamMemberChangeResponse, error) {
+	ctx, span := tracer.Start(ctx, "TeamService.RemoveMember")
+	defer span.End()
+
+	req.TeamName = strings.TrimSpace(req.TeamName)
+
+	var response *dto.TeamMemberChangeResponse
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		team, err := s.teamRepo.GetTeamByName(ctx, req.TeamName)
+		if err != nil {
+			s.log.Error("не удалось получить команду по названию", "error", err, "team name", req.TeamName)
+			return err
+		}
+
+		user, err := s.UserRepo.GetUserByID(ctx, req.UserID)
+		if err != nil {
+			s.log.Error("не удалось получить пользователя", "error", err, "user ID", req.UserID)
+			return err
+		}
+		if len(user.Teams) == 1 && user.Teams[0].TeamName == team.TeamName {
+			s.log.Error("нельзя исключить пользователя из единственной команды", "error", errs.ErrLastTeam, "user ID", req.UserID)
+			return errs.ErrLastTeam
+		}
+
+		report, err := s.leaveTeam(ctx, team, req.UserID)
+		if err != nil {
+			return err
+		}
+
+		response = &dto.TeamMemberChangeResponse{
+			UserID:       req.UserID,
+			FromTeam:     team.TeamName,
+			PullRequests: groupByPullRequest(report),
+		}
+		return nil
+	})
+	if err != nil {
+		s.log.Error("транзакция завершилась с ошибкой", "error", err)
+		return nil, err
+	}
+	return response, nil
+}
+
+// MoveMember переводит пользователя в другую команду. Открытые ревью на pr прежней команды переназначаются,
+// ревью на pr других команд остаются за пользователем. Если прежняя команда была основной, основной становится новая.
+func (s *TeamService) MoveMember(ctx context.Context, req dto.MoveTeamMemberRequest) (*dto.TeamMemberChangeResponse, error) {
+	ctx, span := tracer.Start(ctx, "TeamService.MoveMember")
+	defer span.End()
+
+	req.FromTeam = strings.TrimSpace(req.FromTeam)
+	req.ToTeam = strings.TrimSpace(req.ToTeam)
+
+	var response *dto.TeamMemberChangeResponse
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		from, err := s.teamRepo.GetTeamByName(ctx, req.FromTeam)
+		if err != nil {
+			s.log.Error("не удалось получить команду по названию", "error", err, "team name", req.FromTeam)
+			return err
+		}
+
+		to, err := s.teamRepo.GetTeamByName(ctx, req.ToTeam)
+		if err != nil {
+			s.log.Error("не удалось получить команду по названию", "error", err, "team name", req.ToTeam)
+			return err
+		}
+
+		if to.ArchivedAt != nil {
+			s.log.Error("нельзя перевести участника в архивную команду", "error", errs.ErrTeamArchived, "team name", to.TeamName)
+			return errs.ErrTeamArchived
+		}
+
+		if isTeamMember(to, req.UserID) {
+			s.log.Error("пользователь уже состоит в команде", "error", errs.ErrAlreadyTeamMember, "team name", to.TeamName)
+			return errs.ErrAlreadyTeamMember
+		}
+
+		user, err := s.UserRepo.GetUserByID(ctx, req.UserID)
+		if err != nil {
+			s.log.Error("не удалось получить пользователя", "error", err, "user ID", req.UserID)
+			return err
+		}
+
+		report, err := s.leaveTeam(ctx, from, req.UserID)
+		if err != nil {
+			return err
+		}
+
+		err = s.teamRepo.AddMembersToTeam(ctx, to.ID, []dto.TeamMember{{UserID: req.UserID, ReviewWeight: req.ReviewWeight}})
+		if err != nil {
+			s.log.Error("не удалось добавить пользователя в команду", "error", err)
+			return err
+		}
+
+		if user.TeamName == from.TeamName {
+			err = s.UserRepo.SetPrimaryTeam(ctx, req.UserID, to.TeamName)
+			if err != nil {
+				s.log.Error("не удалось сменить основную команду", "error", err, "user ID", req.UserID)
+				return err
+			}
+		}
+
+		response = &dto.TeamMemberChangeResponse{
+			UserID:       req.UserID,
+			FromTeam:     from.TeamName,
+			ToTeam:       to.TeamName,
+			PullRequests: groupByPullRequest(report),
+		}
+		return nil
+	})
+	if err != nil {
+		s.log.Error("транзакция завершилась с ошибкой", "error", err)
+		return nil, err
+	}
+	return response, nil
+}
+
+func (s *TeamService) leaveTeam(ctx context.Context, team *dto.Team, userID string) (*dto.ReassignmentReport, error) {
+	if !isTeamMember(team, userID) {
+		s.log.Error("пользователь не состоит в команде", "error", errs.ErrNotTeamMember, "user ID", userID)
+		return nil, errs.ErrNotTeamMember
+	}
+
+	err := s.teamRepo.RemoveMemberFromTeam(ctx, team.ID, userID)
+	if err != nil {
+		s.log.Error("не удалось исключить пользователя из команды", "error", err, "user ID", userID)
+		return nil, err
+	}
+
+	report, err := s.reassigner.ReassignTeamReviews(ctx, team.ID, []string{userID})
+	if err != nil {
+		s.log.Error("не удалось переназначить ревью", "error", err)
+		return nil, err
+	}
+	return report, nil
+}
+
+func isTeamMember(team *dto.Team, userID string) bool {
+	return slices.ContainsFunc(team.Members, func(member dto.TeamMember) bool {
+		return member.UserID == userID
+	})
+}
@@ -0,0 +1,6 @@
+package service
+
+import "go.opentelemetry.io/otel"
+
+// tracer берётся из глобального провайдера, поэтому без настроенного экспортёра span'ы ничего не стоят.
+var tracer = otel.Tracer("PRReviewer/internal/core/service")
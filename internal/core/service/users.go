@@ -1,30 +1,80 @@
 package service
 
 import (
+	"PRReviewer/internal/core/auth"
+	"PRReviewer/internal/core/dto"
 	"PRReviewer/internal/core/entities"
+	"PRReviewer/internal/core/errs"
 	"context"
+	"errors"
 	"log/slog"
+	"strings"
 )
 
+type ReviewReassigner interface {
+	ReassignReviews(ctx context.Context, userIDs []string) (*dto.ReassignmentReport, error)
+	ReassignTeamReviews(ctx context.Context, teamID string, userIDs []string) (*dto.ReassignmentReport, error)
+}
+
 type UsersService struct {
-	userRepo UserRepo
-	tx       Transactor
-	log      *slog.Logger
+	userRepo   UserRepo
+	reassigner ReviewReassigner
+	tx         Transactor
+	log        *slog.Logger
 }
 
-func NewUsersService(userRepo UserRepo, tx Transactor, log *slog.Logger) *UsersService {
-	return &UsersService{userRepo: userRepo, tx: tx, log: log}
+func NewUsersService(userRepo UserRepo, reassigner ReviewReassigner, tx Transactor, log *slog.Logger) *UsersService {
+	return &UsersService{userRepo: userRepo, reassigner: reassigner, tx: tx, log: log}
 }
 
-func (s *UsersService) SetIsActive(ctx context.Context, userID string, isActive bool) (*entities.User, error) {
-	var user *entities.User
+// SetIsActive при деактивации в той же транзакции переназначает открытые ревью пользователя.
+func (s *UsersService) SetIsActive(ctx context.Context, userID string, isActive bool) (*dto.SetUserActiveResponse, error) {
+	ctx, span := tracer.Start(ctx, "UsersService.SetIsActive")
+	defer span.End()
+
+	var response dto.SetUserActiveResponse
 	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
 		err := s.userRepo.SetIsActive(ctx, userID, isActive)
 		if err != nil {
 			s.log.Error("не удалось обновить статус пользователя", "error", err)
 			return err
 		}
-		user, err = s.userRepo.GetUserByID(ctx, userID)
+
+		if !isActive {
+			response.Reassignment, err = s.reassigner.ReassignReviews(ctx, []string{userID})
+			if err != nil {
+				s.log.Error("не удалось переназначить ревью пользователя", "error", err, "user ID", userID)
+				return err
+			}
+		}
+
+		user, err := s.userRepo.GetUserByID(ctx, userID)
+		if err != nil {
+			s.log.Error("не удалось получить пользователя", "error", err)
+			return err
+		}
+		response.User = *user
+		return nil
+	})
+	if err != nil {
+		s.log.Error("транзакция завершилась с ошибкой", "error", err)
+		return nil, err
+	}
+	return &response, nil
+}
+
+func (s *UsersService) UpdateUser(ctx context.Context, update dto.UpdateUserRequest) (*entities.User, error) {
+	ctx, span := tracer.Start(ctx, "UsersService.UpdateUser")
+	defer span.End()
+
+	var user *entities.User
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := s.userRepo.UpdateUser(ctx, update)
+		if err != nil {
+			s.log.Error("не удалось обновить пользователя", "error", err, "user ID", update.UserID)
+			return err
+		}
+		user, err = s.userRepo.GetUserByID(ctx, update.UserID)
 		if err != nil {
 			s.log.Error("не удалось получить пользователя", "error", err)
 			return err
@@ -37,3 +87,113 @@ func (s *UsersService) SetIsActive(ctx context.Context, userID string, isActive
 	}
 	return user, nil
 }
+
+// SetAdmin выдаёт или снимает права администратора. Доступ к операции проверяется на уровне маршрута.
+func (s *UsersService) SetAdmin(ctx context.Context, req dto.SetAdminRequest) (*entities.User, error) {
+	ctx, span := tracer.Start(ctx, "UsersService.SetAdmin")
+	defer span.End()
+
+	var user *entities.User
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := s.userRepo.SetAdmin(ctx, req.UserID, req.IsAdmin)
+		if err != nil {
+			s.log.Error("не удалось изменить права администратора", "error", err, "user ID", req.UserID)
+			return err
+		}
+		user, err = s.userRepo.GetUserByID(ctx, req.UserID)
+		if err != nil {
+			s.log.Error("не удалось получить пользователя", "error", err)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		s.log.Error("транзакция завершилась с ошибкой", "error", err)
+		return nil, err
+	}
+
+	s.log.Warn("изменены права администратора", "user ID", req.UserID, "is admin", req.IsAdmin, "actor", auth.ActorID(ctx))
+	return user, nil
+}
+
+// SetGitHubLogin привязывает github-логин к пользователю. По логину вебхуки GitHub определяют автора
+// и инициатора действий, поэтому один логин не может принадлежать двум пользователям.
+func (s *UsersService) SetGitHubLogin(ctx context.Context, req dto.SetGitHubLoginRequest) (*entities.User, error) {
+	ctx, span := tracer.Start(ctx, "UsersService.SetGitHubLogin")
+	defer span.End()
+
+	login := strings.TrimSpace(req.GitHubLogin)
+
+	var user *entities.User
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if login != "" {
+			owner, err := s.userRepo.GetUserIDByGitHubLogin(ctx, login)
+			if err != nil && !errors.Is(err, errs.ErrNotFound) {
+				s.log.Error("не удалось найти пользователя по github-логину", "error", err)
+				return err
+			}
+			if err == nil && owner != req.UserID {
+				s.log.Error("github-логин уже занят", "error", errs.ErrGitHubLoginTaken, "user ID", owner)
+				return errs.ErrGitHubLoginTaken
+			}
+		}
+
+		err := s.userRepo.SetGitHubLogin(ctx, req.UserID, login)
+		if err != nil {
+			s.log.Error("не удалось привязать github-логин", "error", err, "user ID", req.UserID)
+			return err
+		}
+		user, err = s.userRepo.GetUserByID(ctx, req.UserID)
+		if err != nil {
+			s.log.Error("не удалось получить пользователя", "error", err)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		s.log.Error("транзакция завершилась с ошибкой", "error", err)
+		return nil, err
+	}
+	return user, nil
+}
+
+func (s *UsersService) SetPrimaryTeam(ctx context.Context, req dto.SetPrimaryTeamRequest) (*entities.User, error) {
+	ctx, span := tracer.Start(ctx, "UsersService.SetPrimaryTeam")
+	defer span.End()
+
+	var user *entities.User
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := s.userRepo.SetPrimaryTeam(ctx, req.UserID, strings.TrimSpace(req.TeamName))
+		if err != nil {
+			s.log.Error("не удалось сменить основную команду", "error", err, "user ID", req.UserID)
+			return err
+		}
+		user, err = s.userRepo.GetUserByID(ctx, req.UserID)
+		if err != nil {
+			s.log.Error("не удалось получить пользователя", "error", err)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		s.log.Error("транзакция завершилась с ошибкой", "error", err)
+		return nil, err
+	}
+	return user, nil
+}
+
+func (s *UsersService) ListUsers(ctx context.Context, query dto.ListUsersQuery) (*dto.ListUsersResponse, error) {
+	ctx, span := tracer.Start(ctx, "UsersService.ListUsers")
+	defer span.End()
+
+	query.TeamName = strings.TrimSpace(query.TeamName)
+	query.Name = strings.TrimSpace(query.Name)
+	query.Limit = pageLimit(query.Limit)
+
+	users, next, err := s.userRepo.ListUsers(ctx, query)
+	if err != nil {
+		s.log.Error("не удалось получить список пользователей", "error", err)
+		return nil, err
+	}
+	return &dto.ListUsersResponse{Users: users, NextCursor: next}, nil
+}
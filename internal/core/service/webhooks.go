@@ -0,0 +1,134 @@
+package service
+
+import (
+	"PRReviewer/internal/core/dto"
+	"PRReviewer/internal/core/entities"
+	"PRReviewer/internal/core/enums"
+	"context"
+	"log/slog"
+	"time"
+)
+
+type WebhookRepo interface {
+	CreateWebhookSubscription(ctx context.Context, req dto.CreateWebhookSubscriptionRequest) (*entities.WebhookSubscription, error)
+	GetWebhookSubscriptions(ctx context.Context) ([]entities.WebhookSubscription, error)
+	DeleteWebhookSubscription(ctx context.Context, subscriptionID string) error
+	GetDeadLetters(ctx context.Context, query dto.DeadLettersQuery) ([]entities.WebhookDelivery, string, error)
+	RetryDeadLetters(ctx context.Context, deliveryIDs []int64) (int, error)
+}
+
+// webhookEvents сопоставляет события журнала pr с событиями, на которые можно подписаться.
+var webhookEvents = map[enums.PREventType]enums.WebhookEventType{
+	enums.EventCreated:          enums.WebhookPRCreated,
+	enums.EventReviewerAssigned: enums.WebhookReviewerAssigned,
+	enums.EventReviewerReplaced: enums.WebhookReviewerReassigned,
+	enums.EventReviewSubmitted:  enums.WebhookReviewSubmitted,
+	enums.EventReadyForReview:   enums.WebhookPRReadyForReview,
+	enums.EventMerged:           enums.WebhookPRMerged,
+	enums.EventClosed:           enums.WebhookPRClosed,
+	enums.EventReopened:         enums.WebhookPRReopened,
+	enums.EventConvertedToDraft: enums.WebhookPRConvertedToDraft,
+}
+
+func webhookPayloads(events []entities.PREvent) []dto.WebhookPayload {
+	now := time.Now().UTC()
+	payloads := make([]dto.WebhookPayload, 0, len(events))
+	for _, event := range events {
+		eventType, ok := webhookEvents[event.Type]
+		if !ok {
+			continue
+		}
+		payloads = append(payloads, dto.WebhookPayload{
+			Event:         eventType,
+			PullRequestID: event.PullRequestID,
+			ActorID:       event.ActorID,
+			ReviewerID:    event.ReviewerID,
+			OldReviewerID: event.OldReviewerID,
+			Decision:      event.Decision,
+			OccurredAt:    now,
+		})
+	}
+	return payloads
+}
+
+// WebhookTargetValidator проверяет, можно ли отправлять вебхуки на адрес подписки.
+type WebhookTargetValidator interface {
+	ValidateTarget(rawURL string) error
+}
+
+type WebhookService struct {
+	webhookRepo WebhookRepo
+	targets     WebhookTargetValidator
+	log         *slog.Logger
+}
+
+func NewWebhookService(webhookRepo WebhookRepo, targets WebhookTargetValidator, log *slog.Logger) *WebhookService {
+	return &WebhookService{webhookRepo: webhookRepo, targets: targets, log: log}
+}
+
+func (s *WebhookService) CreateSubscription(ctx context.Context, req dto.CreateWebhookSubscriptionRequest) (*entities.WebhookSubscription, error) {
+	ctx, span := tracer.Start(ctx, "WebhookService.CreateSubscription")
+	defer span.End()
+
+	if err := s.targets.ValidateTarget(req.URL); err != nil {
+		s.log.Error("недопустимый адрес вебхука", "error", err, "url", req.URL)
+		return nil, err
+	}
+
+	subscription, err := s.webhookRepo.CreateWebhookSubscription(ctx, req)
+	if err != nil {
+		s.log.Error("не удалось создать подписку на вебхуки", "error", err, "url", req.URL)
+		return nil, err
+	}
+	return subscription, nil
+}
+
+func (s *WebhookService) GetSubscriptions(ctx context.Context) (*dto.WebhookSubscriptionsResponse, error) {
+	ctx, span := tracer.Start(ctx, "WebhookService.GetSubscriptions")
+	defer span.End()
+
+	subscriptions, err := s.webhookRepo.GetWebhookSubscriptions(ctx)
+	if err != nil {
+		s.log.Error("не удалось получить подписки на вебхуки", "error", err)
+		return nil, err
+	}
+	return &dto.WebhookSubscriptionsResponse{Subscriptions: subscriptions}, nil
+}
+
+func (s *WebhookService) DeleteSubscription(ctx context.Context, subscriptionID string) error {
+	ctx, span := tracer.Start(ctx, "WebhookService.DeleteSubscription")
+	defer span.End()
+
+	err := s.webhookRepo.DeleteWebhookSubscription(ctx, subscriptionID)
+	if err != nil {
+		s.log.Error("не удалось удалить подписку на вебхуки", "error", err, "subscription ID", subscriptionID)
+		return err
+	}
+	return nil
+}
+
+func (s *WebhookService) GetDeadLetters(ctx context.Context, query dto.DeadLettersQuery) (*dto.DeadLettersResponse, error) {
+	ctx, span := tracer.Start(ctx, "WebhookService.GetDeadLetters")
+	defer span.End()
+
+	query.Limit = pageLimit(query.Limit)
+
+	deliveries, next, err := s.webhookRepo.GetDeadLetters(ctx, query)
+	if err != nil {
+		s.log.Error("не удалось получить недоставленные вебхуки", "error", err)
+		return nil, err
+	}
+	return &dto.DeadLettersResponse{Deliveries: deliveries, NextCursor: next}, nil
+}
+
+func (s *WebhookService) RetryDeadLetters(ctx context.Context, req dto.RetryDeadLettersRequest) (*dto.RetryDeadLettersResponse, error) {
+	ctx, span := tracer.Start(ctx, "WebhookService.RetryDeadLetters")
+	defer span.End()
+
+	retried, err := s.webhookRepo.RetryDeadLetters(ctx, req.DeliveryIDs)
+	if err != nil {
+		s.log.Error("не удалось вернуть вебхуки в очередь", "error", err)
+		return nil, err
+	}
+	return &dto.RetryDeadLettersResponse{Retried: retried}, nil
+}
@@ -0,0 +1,141 @@
+package repo
+
+import (
+	"PRReviewer/internal/core/entities"
+	"PRReviewer/internal/core/enums"
+	"cmp"
+	"context"
+	"database/sql"
+	"slices"
+)
+
+// AddPREvents сохраняет события в порядке передачи и проставляет им ID и CreatedAt.
+func (r *SQLRepo) AddPREvents(ctx context.Context, events []entities.PREvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	prIDs := make([]string, len(events))
+	types := make([]string, len(events))
+	actors := make([]string, len(events))
+	reviewers := make([]string, len(events))
+	oldReviewers := make([]string, len(events))
+	decisions := make([]string, len(events))
+	for i, event := range events {
+		prIDs[i] = event.PullRequestID
+		types[i] = string(event.Type)
+		actors[i] = event.ActorID
+		reviewers[i] = event.ReviewerID
+		oldReviewers[i] = event.OldReviewerID
+		decisions[i] = string(event.Decision)
+	}
+
+	query := `
+        INSERT INTO pr_events (pr_id, event_type, actor_id, reviewer_id, old_reviewer_id, decision)
+        SELECT pr_id, event_type, NULLIF(actor_id, ''), NULLIF(reviewer_id, ''), NULLIF(old_reviewer_id, ''), NULLIF(decision, '')
+        FROM unnest($1::text[], $2::text[], $3::text[], $4::text[], $5::text[], $6::text[])
+            WITH ORDINALITY AS e(pr_id, event_type, actor_id, reviewer_id, old_reviewer_id, decision, ord)
+        ORDER BY e.ord
+        RETURNING id, created_at
+    `
+
+	executor := getExecutor(ctx, r.db, "SQLRepo.AddPREvents")
+	rows, err := executor.QueryContext(ctx, query, prIDs, types, actors, reviewers, oldReviewers, decisions)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	// id выдаются из последовательности в порядке вставки, поэтому по возрастанию id совпадают с порядком событий.
+	inserted := make([]entities.PREvent, 0, len(events))
+	for rows.Next() {
+		var event entities.PREvent
+		if err := rows.Scan(&event.ID, &event.CreatedAt); err != nil {
+			return err
+		}
+		inserted = append(inserted, event)
+	}
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	slices.SortFunc(inserted, func(a, b entities.PREvent) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+	for i := range events {
+		events[i].ID = inserted[i].ID
+		events[i].CreatedAt = inserted[i].CreatedAt
+	}
+	return nil
+}
+
+func (r *SQLRepo) GetPREvents(ctx context.Context, prID string) ([]entities.PREvent, error) {
+	query := `
+        SELECT id, pr_id, event_type, actor_id, reviewer_id, old_reviewer_id, decision, created_at
+        FROM pr_events
+        WHERE pr_id = $1
+        ORDER BY id
+    `
+
+	executor := getExecutor(ctx, r.db, "SQLRepo.GetPREvents")
+	rows, err := executor.QueryContext(ctx, query, prID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanPREvents(rows)
+}
+
+func scanPREvents(rows *sql.Rows) ([]entities.PREvent, error) {
+	events := make([]entities.PREvent, 0)
+	for rows.Next() {
+		var event entities.PREvent
+		var actorID, reviewerID, oldReviewerID, decision sql.NullString
+
+		err := rows.Scan(&event.ID, &event.PullRequestID, &event.Type, &actorID, &reviewerID, &oldReviewerID, &decision, &event.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		event.ActorID = actorID.String
+		event.ReviewerID = reviewerID.String
+		event.OldReviewerID = oldReviewerID.String
+		event.Decision = enums.ReviewDecision(decision.String)
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// GetUserReviewEvents возвращает до limit событий с id больше afterID, которые касаются пользователя как ревьюера:
+// назначения на него, замены с его участием и мерджи pr, где он ревьюер.
+func (r *SQLRepo) GetUserReviewEvents(ctx context.Context, userID string, afterID int64, limit int) ([]entities.PREvent, error) {
+	query := `
+        SELECT e.id, e.pr_id, e.event_type, e.actor_id, e.reviewer_id, e.old_reviewer_id, e.decision, e.created_at
+        FROM pr_events e
+        WHERE e.id > $2
+          AND (
+              (e.event_type = $3 AND e.reviewer_id = $1)
+              OR (e.event_type = $4 AND $1 IN (e.reviewer_id, e.old_reviewer_id))
+              OR (e.event_type = $5 AND EXISTS (
+                  SELECT 1 FROM pull_request_reviewers prr WHERE prr.pr_id = e.pr_id AND prr.reviewer_id = $1
+              ))
+          )
+        ORDER BY e.id
+        LIMIT $6
+    `
+
+	executor := getExecutor(ctx, r.db, "SQLRepo.GetUserReviewEvents")
+	rows, err := executor.QueryContext(ctx, query, userID, afterID,
+		enums.EventReviewerAssigned, enums.EventReviewerReplaced, enums.EventMerged, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanPREvents(rows)
+}
@@ -3,17 +3,77 @@ package repo
 import (
 	"context"
 	"database/sql"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"strings"
 )
 
+var tracer = otel.Tracer("PRReviewer/internal/infrastructure/data/repo")
+
 type Executor interface {
 	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
 	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
 	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
 }
 
-func getExecutor(ctx context.Context, fallback Executor) Executor {
+// getExecutor возвращает транзакцию из ctx или fallback. operation — имя span'а запросов, обычно "SQLRepo.<метод>".
+func getExecutor(ctx context.Context, fallback Executor, operation string) Executor {
 	if tx, ok := GetTxFromCtx(ctx); ok {
-		return tx
+		return tracedExecutor{Executor: tx, operation: operation}
+	}
+	return tracedExecutor{Executor: fallback, operation: operation}
+}
+
+// tracedExecutor оборачивает каждый запрос в span с именем операции репозитория, которая его выполняет.
+// Span покрывает выполнение запроса, но не чтение строк результата.
+type tracedExecutor struct {
+	Executor
+	operation string
+}
+
+func (e tracedExecutor) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	ctx, span := startQuerySpan(ctx, e.operation, query)
+	defer span.End()
+
+	result, err := e.Executor.ExecContext(ctx, query, args...)
+	recordSpanError(span, err)
+	return result, err
+}
+
+func (e tracedExecutor) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	ctx, span := startQuerySpan(ctx, e.operation, query)
+	defer span.End()
+
+	rows, err := e.Executor.QueryContext(ctx, query, args...)
+	recordSpanError(span, err)
+	return rows, err
+}
+
+func (e tracedExecutor) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	ctx, span := startQuerySpan(ctx, e.operation, query)
+	defer span.End()
+
+	row := e.Executor.QueryRowContext(ctx, query, args...)
+	recordSpanError(span, row.Err())
+	return row
+}
+
+func startQuerySpan(ctx context.Context, name string, query string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system.name", "postgresql"),
+			attribute.String("db.query.text", strings.Join(strings.Fields(query), " ")),
+		),
+	)
+}
+
+func recordSpanError(span trace.Span, err error) {
+	if err == nil {
+		return
 	}
-	return fallback
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
 }
@@ -0,0 +1,61 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// failingExecutor отвечает ошибкой на любой запрос.
+type failingExecutor struct {
+	err error
+}
+
+func (e failingExecutor) ExecContext(context.Context, string, ...interface{}) (sql.Result, error) {
+	return nil, e.err
+}
+
+func (e failingExecutor) QueryContext(context.Context, string, ...interface{}) (*sql.Rows, error) {
+	return nil, e.err
+}
+
+func (e failingExecutor) QueryRowContext(context.Context, string, ...interface{}) *sql.Row {
+	return nil
+}
+
+func TestTracedExecutor_RecordsQuerySpans(t *testing.T) {
+	spans := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans)))
+
+	queryErr := errors.New("value too long for type character varying(36)")
+	executor := getExecutor(context.Background(), failingExecutor{err: queryErr}, "SQLRepo.CreatePR")
+
+	_, err := executor.ExecContext(context.Background(), "INSERT INTO pull_requests\n\t(id) VALUES ($1)", "pr")
+	require.ErrorIs(t, err, queryErr)
+
+	ended := spans.Ended()
+	require.Len(t, ended, 1)
+	span := ended[0]
+	assert.Equal(t, "SQLRepo.CreatePR", span.Name())
+	assert.Equal(t, trace.SpanKindClient, span.SpanKind())
+	assert.Equal(t, codes.Error, span.Status().Code)
+	assert.Equal(t, queryErr.Error(), span.Status().Description)
+	require.Len(t, span.Events(), 1)
+	assert.Equal(t, "exception", span.Events()[0].Name)
+
+	attributes := map[string]string{}
+	for _, attr := range span.Attributes() {
+		attributes[string(attr.Key)] = attr.Value.AsString()
+	}
+	assert.Equal(t, "postgresql", attributes["db.system.name"])
+	assert.Equal(t, "INSERT INTO pull_requests (id) VALUES ($1)", attributes["db.query.text"])
+}
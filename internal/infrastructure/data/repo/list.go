@@ -0,0 +1,222 @@
+package repo
+
+import (
+	"PRReviewer/internal/core/dto"
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+type teamCursor struct {
+	TeamName string `json:"team_name"`
+}
+
+type userCursor struct {
+	UserID string `json:"user_id"`
+}
+
+type pullRequestCursor struct {
+	CreatedAt time.Time `json:"created_at"`
+	ID        string    `json:"pull_request_id"`
+}
+
+// ListTeams возвращает страницу команд, отсортированных по имени, и курсор следующей страницы.
+// Архивные команды попадают в выборку только при IncludeArchived.
+func (r *SQLRepo) ListTeams(ctx context.Context, query dto.ListTeamsQuery) ([]dto.TeamSummary, string, error) {
+	scope := cursorScope("teams", query.Name, query.IncludeArchived)
+	var after teamCursor
+	if err := decodeCursor(query.Cursor, scope, &after); err != nil {
+		return nil, "", err
+	}
+
+	var f filter
+	if !query.IncludeArchived {
+		f.add("t.archived_at IS NULL")
+	}
+	if query.Name != "" {
+		f.add("t.team_name ILIKE $%d", containsPattern(query.Name))
+	}
+	if after.TeamName != "" {
+		f.add("t.team_name > $%d", after.TeamName)
+	}
+	limit := f.arg(query.Limit + 1)
+
+	sqlQuery := fmt.Sprintf(`
+        SELECT t.team_name, t.reviewer_strategy, t.reviewers_per_pr, t.archived_at,
+               (SELECT count(*) FROM team_members tm WHERE tm.team_id = t.id)
+        FROM teams t
+        %s
+        ORDER BY t.team_name
+        LIMIT $%d
+    `, f.where(), limit)
+
+	executor := getExecutor(ctx, r.db, "SQLRepo.ListTeams")
+	rows, err := executor.QueryContext(ctx, sqlQuery, f.args...)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	teams := make([]dto.TeamSummary, 0)
+	for rows.Next() {
+		var team dto.TeamSummary
+		var archivedAt sql.NullTime
+		if err := rows.Scan(&team.TeamName, &team.ReviewerStrategy, &team.ReviewersPerPR, &archivedAt, &team.MembersCount); err != nil {
+			return nil, "", err
+		}
+		if archivedAt.Valid {
+			team.ArchivedAt = &archivedAt.Time
+		}
+		teams = append(teams, team)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, "", err
+	}
+
+	if len(teams) <= query.Limit {
+		return teams, "", nil
+	}
+	teams = teams[:query.Limit]
+	next, err := encodeCursor(scope, teamCursor{TeamName: teams[len(teams)-1].TeamName})
+	if err != nil {
+		return nil, "", err
+	}
+	return teams, next, nil
+}
+
+// ListUsers возвращает страницу пользователей, отсортированных по id, и курсор следующей страницы.
+func (r *SQLRepo) ListUsers(ctx context.Context, query dto.ListUsersQuery) ([]dto.UserSummary, string, error) {
+	scope := cursorScope("users", query.TeamName, query.Name, query.IsActive)
+	var after userCursor
+	if err := decodeCursor(query.Cursor, scope, &after); err != nil {
+		return nil, "", err
+	}
+
+	var f filter
+	if query.TeamName != "" {
+		f.add("u.id IN (SELECT tm.user_id FROM team_members tm JOIN teams t ON t.id = tm.team_id WHERE t.team_name = $%d)", query.TeamName)
+	}
+	if query.Name != "" {
+		f.add("u.username ILIKE $%d", containsPattern(query.Name))
+	}
+	if query.IsActive != nil {
+		f.add("u.is_active = $%d", *query.IsActive)
+	}
+	if after.UserID != "" {
+		f.add("u.id > $%d", after.UserID)
+	}
+	limit := f.arg(query.Limit + 1)
+
+	sqlQuery := fmt.Sprintf(`
+        SELECT u.id, COALESCE(u.username, ''), u.is_active, COALESCE(pt.team_name, '')
+        FROM users u
+        LEFT JOIN team_members ptm ON ptm.user_id = u.id AND ptm.is_primary
+        LEFT JOIN teams pt ON pt.id = ptm.team_id
+        %s
+        ORDER BY u.id
+        LIMIT $%d
+    `, f.where(), limit)
+
+	executor := getExecutor(ctx, r.db, "SQLRepo.ListUsers")
+	rows, err := executor.QueryContext(ctx, sqlQuery, f.args...)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	users := make([]dto.UserSummary, 0)
+	for rows.Next() {
+		var user dto.UserSummary
+		if err := rows.Scan(&user.UserID, &user.Username, &user.IsActive, &user.TeamName); err != nil {
+			return nil, "", err
+		}
+		users = append(users, user)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, "", err
+	}
+
+	if len(users) <= query.Limit {
+		return users, "", nil
+	}
+	users = users[:query.Limit]
+	next, err := encodeCursor(scope, userCursor{UserID: users[len(users)-1].UserID})
+	if err != nil {
+		return nil, "", err
+	}
+	return users, next, nil
+}
+
+// ListPullRequests возвращает страницу pr от новых к старым и курсор следующей страницы.
+// Для одинакового времени создания порядок задаёт id, поэтому страницы не пересекаются.
+func (r *SQLRepo) ListPullRequests(ctx context.Context, query dto.ListPullRequestsQuery) ([]dto.PullRequestShort, string, error) {
+	scope := cursorScope("pull_requests", query.Status, query.AuthorID, query.ReviewerID, query.TeamName, query.CreatedFrom, query.CreatedTo)
+	var after pullRequestCursor
+	if err := decodeCursor(query.Cursor, scope, &after); err != nil {
+		return nil, "", err
+	}
+
+	var f filter
+	if query.Status != "" {
+		f.add("p.status = $%d", query.Status)
+	}
+	if query.AuthorID != "" {
+		f.add("p.author_id = $%d", query.AuthorID)
+	}
+	if query.ReviewerID != "" {
+		f.add("p.id IN (SELECT pr_id FROM pull_request_reviewers WHERE reviewer_id = $%d)", query.ReviewerID)
+	}
+	if query.TeamName != "" {
+		f.add("p.team_id = (SELECT id FROM teams WHERE team_name = $%d)", query.TeamName)
+	}
+	if query.CreatedFrom != nil {
+		f.add("p.created_at >= $%d", *query.CreatedFrom)
+	}
+	if query.CreatedTo != nil {
+		f.add("p.created_at < $%d", *query.CreatedTo)
+	}
+	if after.ID != "" {
+		f.add("(p.created_at, p.id) < ($%d, $%d)", after.CreatedAt, after.ID)
+	}
+	limit := f.arg(query.Limit + 1)
+
+	sqlQuery := fmt.Sprintf(`
+        SELECT p.id, p.pr_name, p.author_id, p.status, COALESCE(pt.team_name, ''), p.created_at
+        FROM pull_requests p
+        LEFT JOIN teams pt ON pt.id = p.team_id
+        %s
+        ORDER BY p.created_at DESC, p.id DESC
+        LIMIT $%d
+    `, f.where(), limit)
+
+	executor := getExecutor(ctx, r.db, "SQLRepo.ListPullRequests")
+	rows, err := executor.QueryContext(ctx, sqlQuery, f.args...)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	prs := make([]dto.PullRequestShort, 0)
+	for rows.Next() {
+		var pr dto.PullRequestShort
+		if err := rows.Scan(&pr.PullRequestID, &pr.PullRequestName, &pr.AuthorID, &pr.Status, &pr.TeamName, &pr.CreatedAt); err != nil {
+			return nil, "", err
+		}
+		prs = append(prs, pr)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, "", err
+	}
+
+	if len(prs) <= query.Limit {
+		return prs, "", nil
+	}
+	prs = prs[:query.Limit]
+	last := prs[len(prs)-1]
+	next, err := encodeCursor(scope, pullRequestCursor{CreatedAt: last.CreatedAt, ID: last.PullRequestID})
+	if err != nil {
+		return nil, "", err
+	}
+	return prs, next, nil
+}
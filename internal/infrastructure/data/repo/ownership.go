@@ -0,0 +1,153 @@
+package repo
+
+import (
+	"PRReviewer/internal/core/dto"
+	"context"
+	"fmt"
+	"strings"
+)
+
+func (r *SQLRepo) SetTeamOwnership(ctx context.Context, teamID string, ownership dto.TeamOwnership) error {
+	executor := getExecutor(ctx, r.db, "SQLRepo.SetTeamOwnership")
+
+	_, err := executor.ExecContext(ctx, `DELETE FROM team_owner_groups WHERE team_id = $1`, teamID)
+	if err != nil {
+		return err
+	}
+
+	_, err = executor.ExecContext(ctx, `DELETE FROM team_ownership_rules WHERE team_id = $1`, teamID)
+	if err != nil {
+		return err
+	}
+
+	var valueStrings []string
+	var valueArgs []interface{}
+
+	for _, group := range ownership.Groups {
+		for _, userID := range group.Members {
+			pos := len(valueArgs)
+			valueStrings = append(valueStrings, fmt.Sprintf("($%d, $%d, $%d)", pos+1, pos+2, pos+3))
+			valueArgs = append(valueArgs, teamID, group.Name, userID)
+		}
+	}
+
+	if len(valueStrings) > 0 {
+		query := fmt.Sprintf(
+			"INSERT INTO team_owner_groups (team_id, group_name, user_id) VALUES %s ON CONFLICT DO NOTHING",
+			strings.Join(valueStrings, ", "),
+		)
+		_, err = executor.ExecContext(ctx, query, valueArgs...)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(ownership.Rules) == 0 {
+		return nil
+	}
+
+	valueStrings, valueArgs = nil, nil
+	var ownerStrings []string
+	var ownerArgs []interface{}
+
+	for i, rule := range ownership.Rules {
+		pos := len(valueArgs)
+		valueStrings = append(valueStrings, fmt.Sprintf("($%d, $%d, $%d)", pos+1, pos+2, pos+3))
+		valueArgs = append(valueArgs, teamID, i, rule.Pattern)
+
+		for _, owner := range rule.Owners {
+			pos := len(ownerArgs)
+			ownerStrings = append(ownerStrings, fmt.Sprintf("($%d, $%d, $%d)", pos+1, pos+2, pos+3))
+			ownerArgs = append(ownerArgs, teamID, i, owner)
+		}
+	}
+
+	query := fmt.Sprintf(
+		"INSERT INTO team_ownership_rules (team_id, position, pattern) VALUES %s",
+		strings.Join(valueStrings, ", "),
+	)
+	_, err = executor.ExecContext(ctx, query, valueArgs...)
+	if err != nil {
+		return err
+	}
+
+	query = fmt.Sprintf(
+		"INSERT INTO team_ownership_rule_owners (team_id, position, owner) VALUES %s ON CONFLICT DO NOTHING",
+		strings.Join(ownerStrings, ", "),
+	)
+	_, err = executor.ExecContext(ctx, query, ownerArgs...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *SQLRepo) GetTeamOwnership(ctx context.Context, teamID string) (*dto.TeamOwnership, error) {
+	executor := getExecutor(ctx, r.db, "SQLRepo.GetTeamOwnership")
+
+	ownership := &dto.TeamOwnership{
+		Groups: []dto.OwnerGroup{},
+		Rules:  []dto.OwnershipRule{},
+	}
+
+	groupQuery := `SELECT group_name, user_id FROM team_owner_groups WHERE team_id = $1 ORDER BY group_name, user_id`
+	rows, err := executor.QueryContext(ctx, groupQuery, teamID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var groupName, userID string
+		if err := rows.Scan(&groupName, &userID); err != nil {
+			return nil, err
+		}
+
+		last := len(ownership.Groups) - 1
+		if last < 0 || ownership.Groups[last].Name != groupName {
+			ownership.Groups = append(ownership.Groups, dto.OwnerGroup{Name: groupName})
+			last++
+		}
+		ownership.Groups[last].Members = append(ownership.Groups[last].Members, userID)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	ruleQuery := `
+				SELECT r.position, r.pattern, o.owner
+				FROM team_ownership_rules r
+				JOIN team_ownership_rule_owners o ON o.team_id = r.team_id AND o.position = r.position
+				WHERE r.team_id = $1
+				ORDER BY r.position, o.owner
+			`
+	ruleRows, err := executor.QueryContext(ctx, ruleQuery, teamID)
+	if err != nil {
+		return nil, err
+	}
+	defer ruleRows.Close()
+
+	lastPosition := -1
+	for ruleRows.Next() {
+		var position int
+		var pattern, owner string
+		if err := ruleRows.Scan(&position, &pattern, &owner); err != nil {
+			return nil, err
+		}
+
+		if position != lastPosition {
+			ownership.Rules = append(ownership.Rules, dto.OwnershipRule{Pattern: pattern})
+			lastPosition = position
+		}
+		last := len(ownership.Rules) - 1
+		ownership.Rules[last].Owners = append(ownership.Rules[last].Owners, owner)
+	}
+
+	if err = ruleRows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ownership, nil
+}
@@ -0,0 +1,99 @@
+package repo
+
+import (
+	"PRReviewer/internal/core/errs"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// filter собирает условия WHERE с позиционными параметрами.
+type filter struct {
+	conditions []string
+	args       []interface{}
+}
+
+// add добавляет условие; каждый %d в condition заменяется номером параметра для соответствующего значения.
+func (f *filter) add(condition string, values ...interface{}) {
+	positions := make([]interface{}, 0, len(values))
+	for _, value := range values {
+		positions = append(positions, f.arg(value))
+	}
+	f.conditions = append(f.conditions, fmt.Sprintf(condition, positions...))
+}
+
+// arg добавляет значение без условия и возвращает номер его параметра.
+func (f *filter) arg(value interface{}) int {
+	f.args = append(f.args, value)
+	return len(f.args)
+}
+
+func (f *filter) where() string {
+	if len(f.conditions) == 0 {
+		return ""
+	}
+	return "WHERE " + strings.Join(f.conditions, " AND ")
+}
+
+// containsPattern экранирует спецсимволы LIKE и возвращает шаблон поиска подстроки.
+func containsPattern(substring string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return "%" + replacer.Replace(substring) + "%"
+}
+
+// cursorEnvelope — содержимое курсора. Scope привязывает курсор к списку и фильтрам, с которыми он выдан,
+// чтобы курсор одного запроса нельзя было применить к другому и получить страницу с пропусками.
+type cursorEnvelope struct {
+	Scope string          `json:"s"`
+	Key   json.RawMessage `json:"k"`
+}
+
+// cursorScope возвращает отпечаток списка kind и значений его фильтров. Лимит страницы в него не входит.
+func cursorScope(kind string, filters ...interface{}) string {
+	data, err := json.Marshal(filters)
+	if err != nil {
+		data = []byte(fmt.Sprint(filters...))
+	}
+	sum := sha256.Sum256(append([]byte(kind+":"), data...))
+	return hex.EncodeToString(sum[:8])
+}
+
+// encodeCursor упаковывает ключ сортировки последней строки страницы и scope в непрозрачную строку.
+func encodeCursor(scope string, key interface{}) (string, error) {
+	data, err := json.Marshal(key)
+	if err != nil {
+		return "", err
+	}
+	data, err = json.Marshal(cursorEnvelope{Scope: scope, Key: data})
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// decodeCursor распаковывает курсор в key. Пустой курсор означает первую страницу, key не меняется.
+// Курсор, выданный для другого списка или других фильтров, считается некорректным.
+func decodeCursor(cursor string, scope string, key interface{}) error {
+	if cursor == "" {
+		return nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return errs.ErrInvalidCursor
+	}
+
+	var envelope cursorEnvelope
+	if err := json.Unmarshal(data, &envelope); err != nil || envelope.Key == nil {
+		return errs.ErrInvalidCursor
+	}
+	if envelope.Scope != scope {
+		return fmt.Errorf("%w: курсор выдан для другого запроса", errs.ErrInvalidCursor)
+	}
+	if err := json.Unmarshal(envelope.Key, key); err != nil {
+		return errs.ErrInvalidCursor
+	}
+	return nil
+}
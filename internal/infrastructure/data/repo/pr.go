@@ -6,25 +6,179 @@ import (
 	"PRReviewer/internal/core/enums"
 	"PRReviewer/internal/core/errs"
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 )
 
-func (r *SQLRepo) CreatePR(ctx context.Context, pr dto.CreatePullRequest) error {
-	query := `INSERT INTO pull_requests (id, pr_name, author_id, status) VALUES ($1, $2, $3, $4)`
+func (r *SQLRepo) CreatePR(ctx context.Context, pr dto.CreatePullRequest, teamID string) error {
+	query := `INSERT INTO pull_requests (id, pr_name, author_id, status, team_id) VALUES ($1, $2, $3, $4, NULLIF($5, ''))`
 
-	executor := getExecutor(ctx, r.db)
+	executor := getExecutor(ctx, r.db, "SQLRepo.CreatePR")
 
-	_, err := executor.ExecContext(ctx, query, pr.PullRequestID, pr.PullRequestName, pr.AuthorID, enums.PRStatusOpened)
+	status := enums.PRStatusOpened
+	if pr.Draft {
+		status = enums.PRStatusDraft
+	}
+
+	_, err := executor.ExecContext(ctx, query, pr.PullRequestID, pr.PullRequestName, pr.AuthorID, status, teamID)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
+func (r *SQLRepo) AddChangedFiles(ctx context.Context, prID string, files []string) error {
+	if len(files) == 0 {
+		return nil
+	}
+
+	query := `INSERT INTO pull_request_files (pr_id, path) SELECT $1, unnest($2::text[]) ON CONFLICT DO NOTHING`
+
+	executor := getExecutor(ctx, r.db, "SQLRepo.AddChangedFiles")
+	_, err := executor.ExecContext(ctx, query, prID, files)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *SQLRepo) GetChangedFiles(ctx context.Context, prID string) ([]string, error) {
+	query := `SELECT path FROM pull_request_files WHERE pr_id = $1 ORDER BY path`
+
+	executor := getExecutor(ctx, r.db, "SQLRepo.GetChangedFiles")
+	rows, err := executor.QueryContext(ctx, query, prID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	files := make([]string, 0)
+	for rows.Next() {
+		var path string
+		if err := rows.Scan(&path); err != nil {
+			return nil, err
+		}
+		files = append(files, path)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// GetChangedFilesByPRs возвращает изменённые файлы нескольких pr одним запросом.
+// Pr без изменённых файлов в результат не попадают.
+func (r *SQLRepo) GetChangedFilesByPRs(ctx context.Context, prIDs []string) (map[string][]string, error) {
+	files := make(map[string][]string, len(prIDs))
+	if len(prIDs) == 0 {
+		return files, nil
+	}
+
+	query := `SELECT pr_id, path FROM pull_request_files WHERE pr_id = ANY($1) ORDER BY pr_id, path`
+
+	executor := getExecutor(ctx, r.db, "SQLRepo.GetChangedFilesByPRs")
+	rows, err := executor.QueryContext(ctx, query, prIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var prID, path string
+		if err := rows.Scan(&prID, &path); err != nil {
+			return nil, err
+		}
+		files[prID] = append(files[prID], path)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// UpdatePRStatus переводит pr из статуса from в статус to. Если статус pr успел измениться,
+// возвращает errs.ErrInvalidTransition.
+func (r *SQLRepo) UpdatePRStatus(ctx context.Context, prID string, from enums.PRStatus, to enums.PRStatus) error {
+	query := `
+        UPDATE pull_requests
+        SET status = $3, closed_at = CASE WHEN $4 THEN now() END,
+            status_before_close = CASE WHEN $4 THEN status END
+        WHERE id = $1 AND status = $2
+    `
+
+	executor := getExecutor(ctx, r.db, "SQLRepo.UpdatePRStatus")
+	result, err := executor.ExecContext(ctx, query, prID, from, to, to == enums.PRStatusClosed)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return errs.ErrInvalidTransition
+	}
+	return nil
+}
+
+// CloseTeamPRs закрывает все открытые pr и черновики команды и возвращает их идентификаторы.
+func (r *SQLRepo) CloseTeamPRs(ctx context.Context, teamID string) ([]string, error) {
+	query := `
+        UPDATE pull_requests
+        SET status = $2, closed_at = now(), status_before_close = status
+        WHERE team_id = $1 AND status IN ($3, $4)
+        RETURNING id
+    `
+
+	executor := getExecutor(ctx, r.db, "SQLRepo.CloseTeamPRs")
+	rows, err := executor.QueryContext(ctx, query, teamID, enums.PRStatusClosed, enums.PRStatusOpened, enums.PRStatusDraft)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := make([]string, 0)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// GetStatusBeforeClose возвращает статус, в котором pr был закрыт. Для pr, закрытых до появления этой записи, — OPENED.
+func (r *SQLRepo) GetStatusBeforeClose(ctx context.Context, prID string) (enums.PRStatus, error) {
+	query := `SELECT COALESCE(status_before_close, $2) FROM pull_requests WHERE id = $1`
+
+	executor := getExecutor(ctx, r.db, "SQLRepo.GetStatusBeforeClose")
+	var status enums.PRStatus
+	err := executor.QueryRowContext(ctx, query, prID, enums.PRStatusOpened).Scan(&status)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", errs.ErrNotFound
+		}
+		return "", err
+	}
+	return status, nil
+}
+
 func (r *SQLRepo) IsPRExists(ctx context.Context, prID string) (bool, error) {
 	query := `SELECT EXISTS (SELECT 1 FROM pull_requests WHERE id=$1)`
-	executor := getExecutor(ctx, r.db)
+	executor := getExecutor(ctx, r.db, "SQLRepo.IsPRExists")
 	var exists bool
 	err := executor.QueryRowContext(ctx, query, prID).Scan(&exists)
 	if err != nil {
@@ -33,25 +187,34 @@ func (r *SQLRepo) IsPRExists(ctx context.Context, prID string) (bool, error) {
 	return exists, nil
 }
 
-func (r *SQLRepo) AddReviewers(ctx context.Context, prID string, reviewers []string) error {
-	if len(reviewers) == 0 {
+func (r *SQLRepo) AddReviewers(ctx context.Context, prID string, reviewers []entities.Reviewer) error {
+	assignments := make([]dto.ReviewerAssignment, len(reviewers))
+	for i, reviewer := range reviewers {
+		assignments[i] = dto.ReviewerAssignment{PullRequestID: prID, Reviewer: reviewer}
+	}
+	return r.AddReviewerAssignments(ctx, assignments)
+}
+
+func (r *SQLRepo) AddReviewerAssignments(ctx context.Context, assignments []dto.ReviewerAssignment) error {
+	if len(assignments) == 0 {
 		return nil
 	}
 
 	var valueStrings []string
 	var valueArgs []interface{}
 
-	for i, reviewer := range reviewers {
-		valueStrings = append(valueStrings, fmt.Sprintf("($%d, $%d)", i*2+1, i*2+2))
-		valueArgs = append(valueArgs, prID, reviewer)
+	for i, assignment := range assignments {
+		pos := i * 3
+		valueStrings = append(valueStrings, fmt.Sprintf("($%d, $%d, (SELECT id FROM teams WHERE team_name = $%d))", pos+1, pos+2, pos+3))
+		valueArgs = append(valueArgs, assignment.PullRequestID, assignment.Reviewer.UserID, assignment.Reviewer.FallbackTeam)
 	}
 
 	query := fmt.Sprintf(
-		"INSERT INTO pull_request_reviewers (pr_id, reviewer_id) VALUES %s ON CONFLICT DO NOTHING",
+		"INSERT INTO pull_request_reviewers (pr_id, reviewer_id, fallback_team_id) VALUES %s ON CONFLICT DO NOTHING",
 		strings.Join(valueStrings, ","),
 	)
 
-	executor := getExecutor(ctx, r.db)
+	executor := getExecutor(ctx, r.db, "SQLRepo.AddReviewerAssignments")
 	_, err := executor.ExecContext(ctx, query, valueArgs...)
 	if err != nil {
 		return err
@@ -60,68 +223,182 @@ func (r *SQLRepo) AddReviewers(ctx context.Context, prID string, reviewers []str
 	return nil
 }
 
+// ReplaceReviewerAssignments заменяет ревьюеров на месте одним запросом. Остальные назначения pr
+// и их решения не затрагиваются.
+func (r *SQLRepo) ReplaceReviewerAssignments(ctx context.Context, replacements []dto.ReviewerReplacement) error {
+	if len(replacements) == 0 {
+		return nil
+	}
+
+	prIDs := make([]string, len(replacements))
+	oldIDs := make([]string, len(replacements))
+	newIDs := make([]string, len(replacements))
+	fallbackTeams := make([]string, len(replacements))
+	for i, replacement := range replacements {
+		prIDs[i] = replacement.PullRequestID
+		oldIDs[i] = replacement.OldUserID
+		newIDs[i] = replacement.Reviewer.UserID
+		fallbackTeams[i] = replacement.Reviewer.FallbackTeam
+	}
+
+	query := `
+        UPDATE pull_request_reviewers prr
+        SET reviewer_id = r.new_id, fallback_team_id = ft.id,
+            decision = $5, decided_at = NULL
+        FROM unnest($1::text[], $2::text[], $3::text[], $4::text[]) AS r(pr_id, old_id, new_id, fallback_team)
+        LEFT JOIN teams ft ON ft.team_name = r.fallback_team
+        WHERE prr.pr_id = r.pr_id AND prr.reviewer_id = r.old_id
+    `
+
+	executor := getExecutor(ctx, r.db, "SQLRepo.ReplaceReviewerAssignments")
+	_, err := executor.ExecContext(ctx, query, prIDs, oldIDs, newIDs, fallbackTeams, enums.DecisionPending)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (r *SQLRepo) GetPR(ctx context.Context, prID string) (*entities.PullRequest, error) {
 	query := `
-        SELECT p.id, p.pr_name, p.author_id, p.status, prr.reviewer_id, u.is_active
+        SELECT p.id, p.pr_name, p.author_id, p.status, pt.team_name, p.created_at, p.merged_at, p.closed_at, prr.reviewer_id, u.username, u.is_active, ft.team_name, prr.decision, prr.decided_at
         FROM pull_requests p
+        LEFT JOIN teams pt ON pt.id = p.team_id
         LEFT JOIN pull_request_reviewers prr ON p.id = prr.pr_id
         LEFT JOIN users u ON u.id = prr.reviewer_id
+        LEFT JOIN teams ft ON ft.id = prr.fallback_team_id
         WHERE p.id = $1
     `
 
-	executor := getExecutor(ctx, r.db)
+	executor := getExecutor(ctx, r.db, "SQLRepo.GetPR")
 	rows, err := executor.QueryContext(ctx, query, prID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var pr *entities.PullRequest
-	reviewers := make([]dto.TeamMember, 0)
+	prs, err := scanPullRequests(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(prs) == 0 {
+		return nil, errs.ErrNotFound
+	}
+
+	return &prs[0], nil
+}
+
+// GetOpenPRsReviewedBy возвращает открытые pr, где ревьюит хотя бы один из пользователей.
+// Непустой teamID ограничивает выборку pr этой команды.
+func (r *SQLRepo) GetOpenPRsReviewedBy(ctx context.Context, userIDs []string, teamID string) ([]entities.PullRequest, error) {
+	if len(userIDs) == 0 {
+		return []entities.PullRequest{}, nil
+	}
+
+	query := `
+        SELECT p.id, p.pr_name, p.author_id, p.status, pt.team_name, p.created_at, p.merged_at, p.closed_at, prr.reviewer_id, u.username, u.is_active, ft.team_name, prr.decision, prr.decided_at
+        FROM pull_requests p
+        LEFT JOIN teams pt ON pt.id = p.team_id
+        JOIN pull_request_reviewers prr ON p.id = prr.pr_id
+        JOIN users u ON u.id = prr.reviewer_id
+        LEFT JOIN teams ft ON ft.id = prr.fallback_team_id
+        WHERE p.status = $2
+          AND p.id IN (SELECT pr_id FROM pull_request_reviewers WHERE reviewer_id = ANY($1))
+          AND ($3 = '' OR p.team_id = $3)
+        ORDER BY p.id, prr.reviewer_id
+    `
+
+	executor := getExecutor(ctx, r.db, "SQLRepo.GetOpenPRsReviewedBy")
+	rows, err := executor.QueryContext(ctx, query, userIDs, enums.PRStatusOpened, teamID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanPullRequests(rows)
+}
+
+// scanPullRequests собирает pr из строк вида (pr, ревьюер), отсортированных по id pr.
+func scanPullRequests(rows *sql.Rows) ([]entities.PullRequest, error) {
+	prs := make([]entities.PullRequest, 0)
 
 	for rows.Next() {
-		var prID, prName, authorID, status, userID string
-		var isActive bool
+		var prID, prName, authorID, status string
+		var teamName, userID, username, fallbackTeam, decision sql.NullString
+		var isActive sql.NullBool
+		var createdAt time.Time
+		var mergedAt, closedAt, decidedAt sql.NullTime
 
-		err := rows.Scan(&prID, &prName, &authorID, &status, &userID, &isActive)
+		err := rows.Scan(&prID, &prName, &authorID, &status, &teamName, &createdAt, &mergedAt, &closedAt, &userID, &username, &isActive, &fallbackTeam, &decision, &decidedAt)
 		if err != nil {
 			return nil, fmt.Errorf("scan row: %w", err)
 		}
 
-		if pr == nil {
-			pr = &entities.PullRequest{
+		if len(prs) == 0 || prs[len(prs)-1].ID != prID {
+			prs = append(prs, entities.PullRequest{
 				ID:        prID,
 				Name:      prName,
 				AuthorID:  authorID,
 				Status:    status,
-				Reviewers: reviewers,
+				TeamName:  teamName.String,
+				CreatedAt: createdAt,
+				Reviewers: make([]entities.Reviewer, 0),
+			})
+			if mergedAt.Valid {
+				prs[len(prs)-1].MergedAt = &mergedAt.Time
+			}
+			if closedAt.Valid {
+				prs[len(prs)-1].ClosedAt = &closedAt.Time
 			}
 		}
 
-		user := dto.TeamMember{
-			IsActive: isActive,
-			UserID:   userID,
+		if !userID.Valid {
+			continue
 		}
 
-		reviewers = append(reviewers, user)
+		reviewer := entities.Reviewer{
+			UserID:       userID.String,
+			Username:     username.String,
+			IsActive:     isActive.Bool,
+			FallbackTeam: fallbackTeam.String,
+			Decision:     enums.ReviewDecision(decision.String),
+		}
+		if decidedAt.Valid {
+			reviewer.DecidedAt = &decidedAt.Time
+		}
+
+		pr := &prs[len(prs)-1]
+		pr.Reviewers = append(pr.Reviewers, reviewer)
 	}
 
-	if err = rows.Err(); err != nil {
+	if err := rows.Err(); err != nil {
 		return nil, err
 	}
 
-	if pr == nil {
-		return nil, errs.ErrNotFound
-	}
+	return prs, nil
+}
+
+// LockPR блокирует строку pr до конца транзакции, чтобы параллельные изменения статуса выполнялись по очереди.
+func (r *SQLRepo) LockPR(ctx context.Context, prID string) error {
+	query := `SELECT id FROM pull_requests WHERE id = $1 FOR UPDATE`
 
-	pr.Reviewers = reviewers
-	return pr, nil
+	executor := getExecutor(ctx, r.db, "SQLRepo.LockPR")
+	var id string
+	err := executor.QueryRowContext(ctx, query, prID).Scan(&id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errs.ErrNotFound
+		}
+		return err
+	}
+	return nil
 }
 
 func (r *SQLRepo) MergePullRequest(ctx context.Context, requestID string) error {
-	query := `UPDATE pull_requests SET status = $2 WHERE id = $1`
+	query := `UPDATE pull_requests SET status = $2, merged_at = COALESCE(merged_at, now()) WHERE id = $1`
 
-	executor := getExecutor(ctx, r.db)
+	executor := getExecutor(ctx, r.db, "SQLRepo.MergePullRequest")
 	result, err := executor.ExecContext(ctx, query, requestID, enums.PRStatusMerged)
 	if err != nil {
 		return err
@@ -139,36 +416,65 @@ func (r *SQLRepo) MergePullRequest(ctx context.Context, requestID string) error
 	return nil
 }
 
-func (r *SQLRepo) ReassignPullRequest(ctx context.Context, prID string, oldReviewerID string, newReviewer string) error {
-	query := `UPDATE pull_request_reviewers SET reviewer_id = $1 WHERE pr_id = $2 AND reviewer_id = $3`
-	executor := getExecutor(ctx, r.db)
-	_, err := executor.ExecContext(ctx, query, newReviewer, prID, oldReviewerID)
+func (r *SQLRepo) ReassignPullRequest(ctx context.Context, prID string, oldReviewerID string, newReviewer entities.Reviewer) error {
+	query := `
+        UPDATE pull_request_reviewers
+        SET reviewer_id = $1, fallback_team_id = (SELECT id FROM teams WHERE team_name = $4),
+            decision = $5, decided_at = NULL
+        WHERE pr_id = $2 AND reviewer_id = $3
+    `
+	executor := getExecutor(ctx, r.db, "SQLRepo.ReassignPullRequest")
+	_, err := executor.ExecContext(ctx, query, newReviewer.UserID, prID, oldReviewerID, newReviewer.FallbackTeam, enums.DecisionPending)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (r *SQLRepo) GetUserPRReviews(ctx context.Context, userID string) ([]dto.PullRequestShort, error) {
-	query := `
+// GetUserPRReviews возвращает страницу pr, где пользователь назначен ревьюером, от новых к старым,
+// вместе с его решением и курсором следующей страницы.
+func (r *SQLRepo) GetUserPRReviews(ctx context.Context, query dto.GetUserReviewsQuery) ([]dto.PullRequestShort, string, error) {
+	scope := cursorScope("user_reviews", query.UserID, query.Status)
+	var after pullRequestCursor
+	if err := decodeCursor(query.Cursor, scope, &after); err != nil {
+		return nil, "", err
+	}
+
+	var f filter
+	f.add("prr.reviewer_id = $%d", query.UserID)
+	if query.Status != "" {
+		f.add("pr.status = $%d", query.Status)
+	}
+	if after.ID != "" {
+		f.add("(pr.created_at, pr.id) < ($%d, $%d)", after.CreatedAt, after.ID)
+	}
+	limit := f.arg(query.Limit + 1)
+
+	sqlQuery := fmt.Sprintf(`
         SELECT 
             pr.id,
             pr.pr_name,
             pr.author_id,
-            pr.status
+            pr.status,
+            COALESCE(pt.team_name, ''),
+            pr.created_at,
+            prr.decision
         FROM pull_requests pr
         INNER JOIN pull_request_reviewers prr ON pr.id = prr.pr_id
-        WHERE prr.reviewer_id = $1
-    `
-
-	executor := getExecutor(ctx, r.db)
-	rows, err := executor.QueryContext(ctx, query, userID)
+        LEFT JOIN teams pt ON pt.id = pr.team_id
+        %s
+        ORDER BY pr.created_at DESC, pr.id DESC
+        LIMIT $%d
+    `, f.where(), limit)
+
+	executor := getExecutor(ctx, r.db, "SQLRepo.GetUserPRReviews")
+	rows, err := executor.QueryContext(ctx, sqlQuery, f.args...)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 	defer rows.Close()
 
-	var prs []dto.PullRequestShort
+	prs := make([]dto.PullRequestShort, 0)
 	for rows.Next() {
 		var pr dto.PullRequestShort
 		err := rows.Scan(
@@ -176,16 +482,147 @@ func (r *SQLRepo) GetUserPRReviews(ctx context.Context, userID string) ([]dto.Pu
 			&pr.PullRequestName,
 			&pr.AuthorID,
 			&pr.Status,
+			&pr.TeamName,
+			&pr.CreatedAt,
+			&pr.Decision,
 		)
 		if err != nil {
-			return nil, err
+			return nil, "", err
 		}
 		prs = append(prs, pr)
 	}
 
+	if err = rows.Err(); err != nil {
+		return nil, "", err
+	}
+
+	if len(prs) <= query.Limit {
+		return prs, "", nil
+	}
+	prs = prs[:query.Limit]
+	last := prs[len(prs)-1]
+	next, err := encodeCursor(scope, pullRequestCursor{CreatedAt: last.CreatedAt, ID: last.PullRequestID})
+	if err != nil {
+		return nil, "", err
+	}
+	return prs, next, nil
+}
+
+func (r *SQLRepo) CountOpenReviews(ctx context.Context, userIDs []string) (map[string]int, error) {
+	counts := make(map[string]int, len(userIDs))
+	if len(userIDs) == 0 {
+		return counts, nil
+	}
+
+	query := `
+        SELECT prr.reviewer_id, COUNT(*)
+        FROM pull_request_reviewers prr
+        INNER JOIN pull_requests pr ON pr.id = prr.pr_id
+        WHERE prr.reviewer_id = ANY($1) AND pr.status = $2
+        GROUP BY prr.reviewer_id
+    `
+
+	executor := getExecutor(ctx, r.db, "SQLRepo.CountOpenReviews")
+	rows, err := executor.QueryContext(ctx, query, userIDs, enums.PRStatusOpened)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var userID string
+		var count int
+		if err := rows.Scan(&userID, &count); err != nil {
+			return nil, err
+		}
+		counts[userID] = count
+	}
+
 	if err = rows.Err(); err != nil {
 		return nil, err
 	}
 
-	return prs, nil
+	return counts, nil
+}
+
+// GetLastAssignments возвращает для каждого пользователя id последнего события его назначения ревьюером.
+// Пользователи, которых ещё ни разу не назначали, в результат не попадают.
+func (r *SQLRepo) GetLastAssignments(ctx context.Context, userIDs []string) (map[string]int64, error) {
+	assignments := make(map[string]int64, len(userIDs))
+	if len(userIDs) == 0 {
+		return assignments, nil
+	}
+
+	query := `
+        SELECT reviewer_id, MAX(id)
+        FROM pr_events
+        WHERE reviewer_id = ANY($1) AND event_type IN ($2, $3)
+        GROUP BY reviewer_id
+    `
+
+	executor := getExecutor(ctx, r.db, "SQLRepo.GetLastAssignments")
+	rows, err := executor.QueryContext(ctx, query, userIDs, enums.EventReviewerAssigned, enums.EventReviewerReplaced)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var userID string
+		var eventID int64
+		if err := rows.Scan(&userID, &eventID); err != nil {
+			return nil, err
+		}
+		assignments[userID] = eventID
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return assignments, nil
+}
+
+func (r *SQLRepo) SetReviewDecision(ctx context.Context, prID string, reviewerID string, decision enums.ReviewDecision) error {
+	query := `
+        UPDATE pull_request_reviewers
+        SET decision = CASE
+                WHEN $3::text = 'COMMENTED' AND decision IN ('APPROVED', 'CHANGES_REQUESTED') THEN decision
+                ELSE $3
+            END,
+            decided_at = CASE
+                WHEN $3 IN ('APPROVED', 'CHANGES_REQUESTED') AND decision IS DISTINCT FROM $3 THEN now()
+                ELSE decided_at
+            END
+        WHERE pr_id = $1 AND reviewer_id = $2
+    `
+
+	executor := getExecutor(ctx, r.db, "SQLRepo.SetReviewDecision")
+	result, err := executor.ExecContext(ctx, query, prID, reviewerID, decision)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return errs.ErrUserNotAssigned
+	}
+
+	return nil
+}
+
+func (r *SQLRepo) RecordMergeOverride(ctx context.Context, prID string, actorID string, reason string, unmet []string) error {
+	query := `INSERT INTO merge_overrides (pr_id, actor_id, reason, unmet_conditions) VALUES ($1, NULLIF($2, ''), $3, $4)`
+
+	executor := getExecutor(ctx, r.db, "SQLRepo.RecordMergeOverride")
+	_, err := executor.ExecContext(ctx, query, prID, actorID, reason, strings.Join(unmet, "\n"))
+	if err != nil {
+		return err
+	}
+
+	return nil
 }
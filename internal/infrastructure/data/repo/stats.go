@@ -0,0 +1,168 @@
+package repo
+
+import (
+	"PRReviewer/internal/core/dto"
+	"PRReviewer/internal/core/enums"
+	"context"
+	"database/sql"
+)
+
+// GetReviewerStats считает нагрузку ревьюеров в окне [From, To).
+// Assigned и ReassignedAway берутся из журнала pr по времени события: назначения считаются вместе с заменами,
+// в которых ревьюер пришёл на место другого, поэтому снятые ревьюеры не пропадают из статистики.
+// Approved и OpenReviews считаются по текущим назначениям на pr, созданных в окне.
+// С TeamName учитываются только pr этой команды и выводятся все её участники, включая тех, у кого нет ревью;
+// без него — все пользователи, у которых есть хотя бы одно назначение.
+func (r *SQLRepo) GetReviewerStats(ctx context.Context, query dto.StatsQuery) ([]dto.ReviewerStats, error) {
+	sqlQuery := `
+        WITH team_prs AS (
+            SELECT p.id, p.status, p.created_at
+            FROM pull_requests p
+            WHERE $1 = '' OR p.team_id = (SELECT id FROM teams WHERE team_name = $1)
+        ),
+        window_events AS (
+            SELECT e.event_type, e.reviewer_id, e.old_reviewer_id
+            FROM pr_events e
+            JOIN team_prs tp ON tp.id = e.pr_id
+            WHERE e.created_at >= COALESCE($2::timestamptz, '-infinity')
+              AND e.created_at < COALESCE($3::timestamptz, 'infinity')
+        ),
+        assignments AS (
+            SELECT reviewer_id, count(*) AS assigned
+            FROM window_events
+            WHERE event_type IN ($6, $7) AND reviewer_id IS NOT NULL
+            GROUP BY reviewer_id
+        ),
+        replacements AS (
+            SELECT old_reviewer_id AS reviewer_id, count(*) AS reassigned_away
+            FROM window_events
+            WHERE event_type = $6 AND old_reviewer_id IS NOT NULL
+            GROUP BY old_reviewer_id
+        ),
+        decisions AS (
+            SELECT prr.reviewer_id,
+                   count(*) FILTER (WHERE prr.decision = $4) AS approved,
+                   count(*) FILTER (WHERE tp.status = $5) AS open
+            FROM pull_request_reviewers prr
+            JOIN team_prs tp ON tp.id = prr.pr_id
+            WHERE tp.created_at >= COALESCE($2::timestamptz, '-infinity')
+              AND tp.created_at < COALESCE($3::timestamptz, 'infinity')
+            GROUP BY prr.reviewer_id
+        )
+        SELECT u.id, COALESCE(u.username, ''),
+               COALESCE(a.assigned, 0), COALESCE(x.reassigned_away, 0), COALESCE(d.approved, 0), COALESCE(d.open, 0)
+        FROM users u
+        LEFT JOIN assignments a ON a.reviewer_id = u.id
+        LEFT JOIN replacements x ON x.reviewer_id = u.id
+        LEFT JOIN decisions d ON d.reviewer_id = u.id
+        WHERE CASE
+            WHEN $1 = '' THEN a.reviewer_id IS NOT NULL OR x.reviewer_id IS NOT NULL OR d.reviewer_id IS NOT NULL
+            ELSE u.id IN (SELECT tm.user_id FROM team_members tm JOIN teams t ON t.id = tm.team_id WHERE t.team_name = $1)
+        END
+        ORDER BY u.id
+    `
+
+	executor := getExecutor(ctx, r.db, "SQLRepo.GetReviewerStats")
+	rows, err := executor.QueryContext(ctx, sqlQuery, query.TeamName, query.From, query.To,
+		enums.DecisionApproved, enums.PRStatusOpened, enums.EventReviewerReplaced, enums.EventReviewerAssigned)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := make([]dto.ReviewerStats, 0)
+	for rows.Next() {
+		var s dto.ReviewerStats
+		if err := rows.Scan(&s.UserID, &s.Username, &s.Assigned, &s.ReassignedAway, &s.Approved, &s.OpenReviews); err != nil {
+			return nil, err
+		}
+		stats = append(stats, s)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// GetTeamStats считает по командам число pr, созданных и смердженных в окне [From, To),
+// текущее число открытых pr и медианное время от создания до мерджа в секундах.
+// Архивные команды выводятся, только если запрошены по имени.
+func (r *SQLRepo) GetTeamStats(ctx context.Context, query dto.StatsQuery) ([]dto.TeamStats, error) {
+	sqlQuery := `
+        SELECT t.team_name,
+               count(p.id) FILTER (WHERE p.created_at >= COALESCE($2::timestamptz, '-infinity')
+                                     AND p.created_at < COALESCE($3::timestamptz, 'infinity')),
+               count(p.id) FILTER (WHERE p.merged_at >= COALESCE($2::timestamptz, '-infinity')
+                                     AND p.merged_at < COALESCE($3::timestamptz, 'infinity')),
+               count(p.id) FILTER (WHERE p.status = $4),
+               percentile_cont(0.5) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM p.merged_at - p.created_at)::float8)
+                   FILTER (WHERE p.merged_at >= COALESCE($2::timestamptz, '-infinity')
+                             AND p.merged_at < COALESCE($3::timestamptz, 'infinity'))
+        FROM teams t
+        LEFT JOIN pull_requests p ON p.team_id = t.id
+        WHERE CASE WHEN $1 = '' THEN t.archived_at IS NULL ELSE t.team_name = $1 END
+        GROUP BY t.id, t.team_name
+        ORDER BY t.team_name
+    `
+
+	executor := getExecutor(ctx, r.db, "SQLRepo.GetTeamStats")
+	rows, err := executor.QueryContext(ctx, sqlQuery, query.TeamName, query.From, query.To, enums.PRStatusOpened)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := make([]dto.TeamStats, 0)
+	for rows.Next() {
+		var s dto.TeamStats
+		var median sql.NullFloat64
+		if err := rows.Scan(&s.TeamName, &s.Created, &s.Merged, &s.Open, &median); err != nil {
+			return nil, err
+		}
+		if median.Valid {
+			s.MedianTimeToMergeSeconds = &median.Float64
+		}
+		stats = append(stats, s)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// CountOpenReviewsByTeam возвращает число назначений на открытых pr по команде pr.
+// Pr без команды учитываются под пустым именем.
+func (r *SQLRepo) CountOpenReviewsByTeam(ctx context.Context) (map[string]int, error) {
+	query := `
+        SELECT COALESCE(t.team_name, ''), count(*)
+        FROM pull_request_reviewers prr
+        JOIN pull_requests p ON p.id = prr.pr_id
+        LEFT JOIN teams t ON t.id = p.team_id
+        WHERE p.status = $1
+        GROUP BY t.team_name
+    `
+
+	executor := getExecutor(ctx, r.db, "SQLRepo.CountOpenReviewsByTeam")
+	rows, err := executor.QueryContext(ctx, query, enums.PRStatusOpened)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var teamName string
+		var count int
+		if err := rows.Scan(&teamName, &count); err != nil {
+			return nil, err
+		}
+		counts[teamName] += count
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
@@ -2,6 +2,7 @@ package repo
 
 import (
 	"PRReviewer/internal/core/dto"
+	"PRReviewer/internal/core/enums"
 	"PRReviewer/internal/core/errs"
 	"context"
 	"database/sql"
@@ -11,12 +12,18 @@ import (
 	"strings"
 )
 
-func (r *SQLRepo) CreateTeam(ctx context.Context, teamName string) (string, error) {
+// CreateTeam ожидает заполненную политику мерджа: значения по умолчанию подставляет сервис.
+func (r *SQLRepo) CreateTeam(ctx context.Context, teamName string, settings dto.TeamSettings) (string, error) {
 	teamID := uuid.New().String()
-	query := `INSERT INTO teams (id, team_name) values ($1, $2)`
+	query := `INSERT INTO teams (id, team_name, reviewer_strategy, reviewers_per_pr,
+                   merge_min_approvals, merge_block_on_changes_requested, merge_require_all_approved)
+              values ($1, $2, $3, $4, $5, $6, $7)`
 
-	executor := getExecutor(ctx, r.db)
-	_, err := executor.ExecContext(ctx, query, teamID, teamName)
+	policy := settings.MergePolicy
+
+	executor := getExecutor(ctx, r.db, "SQLRepo.CreateTeam")
+	_, err := executor.ExecContext(ctx, query, teamID, teamName, settings.ReviewerStrategy, settings.ReviewersPerPR,
+		policy.MinApprovals, policy.BlockOnChangesRequested, policy.RequireAllApproved)
 	if err != nil {
 		return "", err
 	}
@@ -28,7 +35,7 @@ func (r *SQLRepo) IsTeamExistsByName(ctx context.Context, teamName string) (bool
 	query := `SELECT EXISTS(SELECT 1 FROM teams WHERE team_name = $1)`
 	var exists bool
 
-	executor := getExecutor(ctx, r.db)
+	executor := getExecutor(ctx, r.db, "SQLRepo.IsTeamExistsByName")
 	err := executor.QueryRowContext(ctx, query, teamName).Scan(&exists)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -48,17 +55,21 @@ func (r *SQLRepo) AddMembersToTeam(ctx context.Context, teamID string, users []d
 	var valueArgs []interface{}
 
 	for i, user := range users {
-		pos := i * 2
-		valueStrings = append(valueStrings, fmt.Sprintf("($%d, $%d)", pos+1, pos+2))
-		valueArgs = append(valueArgs, teamID, user.UserID)
+		pos := i * 3
+		valueStrings = append(valueStrings, fmt.Sprintf("($%d, $%d, $%d::int)", pos+1, pos+2, pos+3))
+		valueArgs = append(valueArgs, teamID, user.UserID, max(user.ReviewWeight, 1))
 	}
 
-	query := fmt.Sprintf(
-		"INSERT INTO team_members (team_id, user_id) VALUES %s ON CONFLICT (team_id, user_id) DO NOTHING",
+	query := fmt.Sprintf(`
+		INSERT INTO team_members (team_id, user_id, review_weight, is_primary)
+		SELECT v.team_id, v.user_id, v.review_weight,
+		       NOT EXISTS (SELECT 1 FROM team_members tm WHERE tm.user_id = v.user_id AND tm.is_primary)
+		FROM (VALUES %s) AS v(team_id, user_id, review_weight)
+		ON CONFLICT (team_id, user_id) DO NOTHING`,
 		strings.Join(valueStrings, ", "),
 	)
 
-	executor := getExecutor(ctx, r.db)
+	executor := getExecutor(ctx, r.db, "SQLRepo.AddMembersToTeam")
 	_, err := executor.ExecContext(ctx, query, valueArgs...)
 	if err != nil {
 		return err
@@ -70,14 +81,15 @@ func (r *SQLRepo) AddMembersToTeam(ctx context.Context, teamID string, users []d
 func (r *SQLRepo) GetTeamByName(ctx context.Context, teamName string) (*dto.Team, error) {
 
 	query := `
-				SELECT t.team_name, u.id, u.username, u.is_active 
+				SELECT t.id, t.team_name, t.reviewer_strategy, t.reviewers_per_pr,
+				       t.merge_min_approvals, t.merge_block_on_changes_requested, t.merge_require_all_approved, t.archived_at, u.id, u.username, u.is_active, tm.review_weight, u.max_open_reviews
 				FROM teams t 
 				LEFT JOIN team_members tm ON t.id = tm.team_id 
 				LEFT JOIN users u ON tm.user_id = u.id 
 				WHERE t.team_name = $1
 			`
 
-	executor := getExecutor(ctx, r.db)
+	executor := getExecutor(ctx, r.db, "SQLRepo.GetTeamByName")
 	rows, err := executor.QueryContext(ctx, query, teamName)
 	if err != nil {
 		return nil, err
@@ -88,26 +100,47 @@ func (r *SQLRepo) GetTeamByName(ctx context.Context, teamName string) (*dto.Team
 	members := make([]dto.TeamMember, 0)
 
 	for rows.Next() {
-		var teamName, userID, username string
-		var isActive bool
+		var teamID, teamName string
+		var strategy enums.ReviewerStrategy
+		var reviewersPerPR int
+		var policy dto.MergePolicy
+		var archivedAt sql.NullTime
+		var userID, username sql.NullString
+		var isActive sql.NullBool
+		var reviewWeight, maxOpenReviews sql.NullInt64
 
-		err := rows.Scan(&teamName, &userID, &username, &isActive)
+		err := rows.Scan(&teamID, &teamName, &strategy, &reviewersPerPR,
+			&policy.MinApprovals, &policy.BlockOnChangesRequested, &policy.RequireAllApproved, &archivedAt, &userID, &username, &isActive, &reviewWeight, &maxOpenReviews)
 		if err != nil {
 			return nil, err
 		}
 
 		if team == nil {
 			team = &dto.Team{
+				ID:       teamID,
 				TeamName: teamName,
 				Members:  []dto.TeamMember{},
+				TeamSettings: dto.TeamSettings{
+					ReviewerStrategy: strategy,
+					ReviewersPerPR:   reviewersPerPR,
+					MergePolicy:      &policy,
+				},
+			}
+			if archivedAt.Valid {
+				team.ArchivedAt = &archivedAt.Time
 			}
 		}
 
-		if userID != "" {
+		if userID.Valid {
 			member := dto.TeamMember{
-				UserID:   userID,
-				Username: username,
-				IsActive: isActive,
+				UserID:       userID.String,
+				Username:     username.String,
+				IsActive:     isActive.Bool,
+				ReviewWeight: int(reviewWeight.Int64),
+			}
+			if maxOpenReviews.Valid {
+				limit := int(maxOpenReviews.Int64)
+				member.MaxOpenReviews = &limit
 			}
 			members = append(members, member)
 		}
@@ -122,5 +155,169 @@ func (r *SQLRepo) GetTeamByName(ctx context.Context, teamName string) (*dto.Team
 	}
 
 	team.Members = members
+
+	team.FallbackTeams, err = r.getTeamFallbacks(ctx, team.ID)
+	if err != nil {
+		return nil, err
+	}
+
 	return team, nil
 }
+
+func (r *SQLRepo) getTeamFallbacks(ctx context.Context, teamID string) ([]string, error) {
+	query := `
+				SELECT ft.team_name
+				FROM team_fallbacks f
+				JOIN teams ft ON ft.id = f.fallback_team_id
+				WHERE f.team_id = $1
+				ORDER BY f.position
+			`
+
+	executor := getExecutor(ctx, r.db, "SQLRepo.getTeamFallbacks")
+	rows, err := executor.QueryContext(ctx, query, teamID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	fallbacks := make([]string, 0)
+	for rows.Next() {
+		var teamName string
+		if err := rows.Scan(&teamName); err != nil {
+			return nil, err
+		}
+		fallbacks = append(fallbacks, teamName)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return fallbacks, nil
+}
+
+func (r *SQLRepo) SetTeamFallbacks(ctx context.Context, teamID string, fallbackTeams []string) error {
+	executor := getExecutor(ctx, r.db, "SQLRepo.SetTeamFallbacks")
+
+	_, err := executor.ExecContext(ctx, `DELETE FROM team_fallbacks WHERE team_id = $1`, teamID)
+	if err != nil {
+		return err
+	}
+
+	if len(fallbackTeams) == 0 {
+		return nil
+	}
+
+	query := `
+				INSERT INTO team_fallbacks (team_id, fallback_team_id, position)
+				SELECT $1, t.id, v.position
+				FROM unnest($2::text[]) WITH ORDINALITY AS v(team_name, position)
+				JOIN teams t ON t.team_name = v.team_name AND t.archived_at IS NULL
+			`
+
+	result, err := executor.ExecContext(ctx, query, teamID, fallbackTeams)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected != int64(len(fallbackTeams)) {
+		return errs.ErrFallbackTeamNotFound
+	}
+
+	return nil
+}
+
+func (r *SQLRepo) UpdateTeamSettings(ctx context.Context, update dto.UpdateTeamSettingsRequest) error {
+	var setStrings []string
+	var valueArgs []interface{}
+
+	if update.ReviewerStrategy != nil {
+		valueArgs = append(valueArgs, *update.ReviewerStrategy)
+		setStrings = append(setStrings, fmt.Sprintf("reviewer_strategy = $%d", len(valueArgs)))
+	}
+
+	if update.ReviewersPerPR != nil {
+		valueArgs = append(valueArgs, *update.ReviewersPerPR)
+		setStrings = append(setStrings, fmt.Sprintf("reviewers_per_pr = $%d", len(valueArgs)))
+	}
+
+	if update.MergePolicy != nil {
+		valueArgs = append(valueArgs, update.MergePolicy.MinApprovals)
+		setStrings = append(setStrings, fmt.Sprintf("merge_min_approvals = $%d", len(valueArgs)))
+		valueArgs = append(valueArgs, update.MergePolicy.BlockOnChangesRequested)
+		setStrings = append(setStrings, fmt.Sprintf("merge_block_on_changes_requested = $%d", len(valueArgs)))
+		valueArgs = append(valueArgs, update.MergePolicy.RequireAllApproved)
+		setStrings = append(setStrings, fmt.Sprintf("merge_require_all_approved = $%d", len(valueArgs)))
+	}
+
+	if len(setStrings) == 0 {
+		exists, err := r.IsTeamExistsByName(ctx, update.TeamName)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return errs.ErrNotFound
+		}
+		return nil
+	}
+
+	valueArgs = append(valueArgs, update.TeamName)
+	query := fmt.Sprintf("UPDATE teams SET %s WHERE team_name = $%d", strings.Join(setStrings, ", "), len(valueArgs))
+
+	executor := getExecutor(ctx, r.db, "SQLRepo.UpdateTeamSettings")
+	result, err := executor.ExecContext(ctx, query, valueArgs...)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return errs.ErrNotFound
+	}
+	return nil
+}
+
+func (r *SQLRepo) RemoveMemberFromTeam(ctx context.Context, teamID string, userID string) error {
+	query := `DELETE FROM team_members WHERE team_id = $1 AND user_id = $2`
+
+	executor := getExecutor(ctx, r.db, "SQLRepo.RemoveMemberFromTeam")
+	result, err := executor.ExecContext(ctx, query, teamID, userID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return errs.ErrNotTeamMember
+	}
+
+	promote := `
+        UPDATE team_members SET is_primary = TRUE
+        WHERE user_id = $1
+          AND team_id = (
+              SELECT tm.team_id FROM team_members tm JOIN teams t ON t.id = tm.team_id
+              WHERE tm.user_id = $1
+              ORDER BY t.team_name
+              LIMIT 1
+          )
+          AND NOT EXISTS (SELECT 1 FROM team_members WHERE user_id = $1 AND is_primary)
+    `
+	_, err = executor.ExecContext(ctx, promote, userID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
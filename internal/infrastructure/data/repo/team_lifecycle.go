@@ -0,0 +1,178 @@
+package repo
+
+import (
+	"PRReviewer/internal/core/entities"
+	"PRReviewer/internal/core/enums"
+	"PRReviewer/internal/core/errs"
+	"context"
+	"database/sql"
+)
+
+func (r *SQLRepo) RenameTeam(ctx context.Context, teamID string, newName string) error {
+	query := `UPDATE teams SET team_name = $2 WHERE id = $1`
+
+	executor := getExecutor(ctx, r.db, "SQLRepo.RenameTeam")
+	result, err := executor.ExecContext(ctx, query, teamID, newName)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return errs.ErrNotFound
+	}
+	return nil
+}
+
+func (r *SQLRepo) ArchiveTeam(ctx context.Context, teamID string) error {
+	query := `UPDATE teams SET archived_at = COALESCE(archived_at, now()) WHERE id = $1`
+
+	executor := getExecutor(ctx, r.db, "SQLRepo.ArchiveTeam")
+	result, err := executor.ExecContext(ctx, query, teamID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return errs.ErrNotFound
+	}
+	return nil
+}
+
+// DeleteTeam удаляет команду вместе с участием в ней, запасными командами и правилами владения.
+// Участникам, для которых команда была основной, основной назначается одна из оставшихся команд.
+func (r *SQLRepo) DeleteTeam(ctx context.Context, teamID string) error {
+	query := `DELETE FROM teams WHERE id = $1`
+
+	executor := getExecutor(ctx, r.db, "SQLRepo.DeleteTeam")
+
+	memberIDs := make([]string, 0)
+	rows, err := executor.QueryContext(ctx, `SELECT user_id FROM team_members WHERE team_id = $1 AND is_primary`, teamID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var userID string
+		if err := rows.Scan(&userID); err != nil {
+			return err
+		}
+		memberIDs = append(memberIDs, userID)
+	}
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	result, err := executor.ExecContext(ctx, query, teamID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return errs.ErrNotFound
+	}
+
+	if len(memberIDs) == 0 {
+		return nil
+	}
+
+	promote := `
+        UPDATE team_members SET is_primary = TRUE
+        WHERE (team_id, user_id) IN (
+            SELECT DISTINCT ON (tm.user_id) tm.team_id, tm.user_id
+            FROM team_members tm
+            JOIN teams t ON t.id = tm.team_id
+            WHERE tm.user_id = ANY($1)
+            ORDER BY tm.user_id, t.team_name
+        )
+    `
+	_, err = executor.ExecContext(ctx, promote, memberIDs)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *SQLRepo) CountOpenTeamPRs(ctx context.Context, teamID string) (int, error) {
+	query := `SELECT COUNT(*) FROM pull_requests WHERE team_id = $1 AND status = $2`
+
+	var count int
+
+	executor := getExecutor(ctx, r.db, "SQLRepo.CountOpenTeamPRs")
+	err := executor.QueryRowContext(ctx, query, teamID, enums.PRStatusOpened).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *SQLRepo) AddTeamEvent(ctx context.Context, event entities.TeamEvent) error {
+	query := `
+        INSERT INTO team_events (team_id, team_name, old_team_name, event_type, actor_id)
+        VALUES ($1, $2, NULLIF($3, ''), $4, NULLIF($5, ''))
+    `
+
+	executor := getExecutor(ctx, r.db, "SQLRepo.AddTeamEvent")
+	_, err := executor.ExecContext(ctx, query, event.TeamID, event.TeamName, event.OldTeamName, event.Type, event.ActorID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetTeamEvents возвращает журнал всех команд, которые когда-либо носили указанное название,
+// включая переименованные и удалённые.
+func (r *SQLRepo) GetTeamEvents(ctx context.Context, teamName string) ([]entities.TeamEvent, error) {
+	query := `
+        SELECT id, team_id, team_name, old_team_name, event_type, actor_id, created_at
+        FROM team_events
+        WHERE team_id IN (
+            SELECT team_id FROM team_events WHERE team_name = $1 OR old_team_name = $1
+            UNION
+            SELECT id FROM teams WHERE team_name = $1
+        )
+        ORDER BY id
+    `
+
+	executor := getExecutor(ctx, r.db, "SQLRepo.GetTeamEvents")
+	rows, err := executor.QueryContext(ctx, query, teamName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := make([]entities.TeamEvent, 0)
+	for rows.Next() {
+		var event entities.TeamEvent
+		var oldTeamName, actorID sql.NullString
+
+		err := rows.Scan(&event.ID, &event.TeamID, &event.TeamName, &oldTeamName, &event.Type, &actorID, &event.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		event.OldTeamName = oldTeamName.String
+		event.ActorID = actorID.String
+		events = append(events, event)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
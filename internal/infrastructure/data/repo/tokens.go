@@ -0,0 +1,42 @@
+package repo
+
+import (
+	"PRReviewer/internal/core/entities"
+	"PRReviewer/internal/core/errs"
+	"context"
+	"database/sql"
+	"errors"
+)
+
+func (r *SQLRepo) AddToken(ctx context.Context, userID string, tokenHash string) error {
+	query := `INSERT INTO api_tokens (token_hash, user_id) VALUES ($1, $2)`
+
+	executor := getExecutor(ctx, r.db, "SQLRepo.AddToken")
+	_, err := executor.ExecContext(ctx, query, tokenHash, userID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetTokenOwner возвращает владельца токена по его хешу. Команды пользователя не заполняются.
+func (r *SQLRepo) GetTokenOwner(ctx context.Context, tokenHash string) (*entities.User, error) {
+	query := `
+        SELECT u.id, u.username, u.is_active, u.is_admin
+        FROM api_tokens t
+        JOIN users u ON u.id = t.user_id
+        WHERE t.token_hash = $1
+    `
+
+	var user entities.User
+
+	executor := getExecutor(ctx, r.db, "SQLRepo.GetTokenOwner")
+	err := executor.QueryRowContext(ctx, query, tokenHash).Scan(&user.ID, &user.Username, &user.IsActive, &user.IsAdmin)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errs.ErrNotFound
+		}
+		return nil, err
+	}
+	return &user, nil
+}
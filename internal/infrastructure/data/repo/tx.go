@@ -13,13 +13,30 @@ func NewSQLTransactor(db *sql.DB) *SQLTransactor {
 	return &SQLTransactor{db: db}
 }
 
+// WithinTransaction переиспользует транзакцию из контекста, если она уже открыта,
+// чтобы сервисы могли вызывать друг друга внутри одной транзакции.
 func (t *SQLTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := GetTxFromCtx(ctx); ok {
+		return fn(ctx)
+	}
+
+	ctx, span := tracer.Start(ctx, "SQLTransactor.WithinTransaction")
+	defer span.End()
+
+	err := t.runInTransaction(ctx, fn)
+	recordSpanError(span, err)
+	return err
+}
+
+func (t *SQLTransactor) runInTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
 	tx, err := t.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
 
+	var hooks []func()
 	ctxWithTx := context.WithValue(ctx, txKey{}, tx)
+	ctxWithTx = context.WithValue(ctxWithTx, afterCommitKey{}, &hooks)
 
 	if err := fn(ctxWithTx); err != nil {
 		if rbErr := tx.Rollback(); rbErr != nil {
@@ -28,11 +45,30 @@ func (t *SQLTransactor) WithinTransaction(ctx context.Context, fn func(ctx conte
 		return err
 	}
 
-	return tx.Commit()
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	for _, hook := range hooks {
+		hook()
+	}
+	return nil
+}
+
+// AfterCommit откладывает fn до успешного коммита транзакции из контекста; при откате fn не вызывается.
+// Вне транзакции fn выполняется сразу.
+func (t *SQLTransactor) AfterCommit(ctx context.Context, fn func()) {
+	hooks, ok := ctx.Value(afterCommitKey{}).(*[]func())
+	if !ok {
+		fn()
+		return
+	}
+	*hooks = append(*hooks, fn)
 }
 
 type txKey struct{}
 
+type afterCommitKey struct{}
+
 func GetTxFromCtx(ctx context.Context) (*sql.Tx, bool) {
 	tx, ok := ctx.Value(txKey{}).(*sql.Tx)
 
@@ -5,11 +5,26 @@ import (
 	"PRReviewer/internal/core/entities"
 	"PRReviewer/internal/core/errs"
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"strings"
 )
 
+// AddUsers создаёт пользователей, а уже существующим обновляет имя и лимит ревью.
 func (r *SQLRepo) AddUsers(ctx context.Context, users []dto.TeamMember) error {
+	return r.insertUsers(ctx, "SQLRepo.AddUsers", users, `
+		ON CONFLICT (id) DO UPDATE SET
+			username = EXCLUDED.username,
+			max_open_reviews = COALESCE(EXCLUDED.max_open_reviews, users.max_open_reviews)`)
+}
+
+// AddMissingUsers создаёт только отсутствующих пользователей, существующие остаются без изменений.
+func (r *SQLRepo) AddMissingUsers(ctx context.Context, users []dto.TeamMember) error {
+	return r.insertUsers(ctx, "SQLRepo.AddMissingUsers", users, `ON CONFLICT (id) DO NOTHING`)
+}
+
+func (r *SQLRepo) insertUsers(ctx context.Context, operation string, users []dto.TeamMember, onConflict string) error {
 	if len(users) == 0 {
 		return nil
 	}
@@ -18,17 +33,18 @@ func (r *SQLRepo) AddUsers(ctx context.Context, users []dto.TeamMember) error {
 	var valueArgs []interface{}
 
 	for i, user := range users {
-		pos := i * 2
-		valueStrings = append(valueStrings, fmt.Sprintf("($%d, $%d)", pos+1, pos+2))
-		valueArgs = append(valueArgs, user.UserID, user.Username)
+		pos := i * 3
+		valueStrings = append(valueStrings, fmt.Sprintf("($%d, $%d, $%d::int)", pos+1, pos+2, pos+3))
+		valueArgs = append(valueArgs, user.UserID, user.Username, user.MaxOpenReviews)
 	}
 
 	query := fmt.Sprintf(
-		"INSERT INTO users (id, username) VALUES %s ON CONFLICT (id) DO UPDATE SET username = EXCLUDED.username",
+		`INSERT INTO users (id, username, max_open_reviews) VALUES %s %s`,
 		strings.Join(valueStrings, ", "),
+		onConflict,
 	)
 
-	executor := getExecutor(ctx, r.db)
+	executor := getExecutor(ctx, r.db, operation)
 	_, err := executor.ExecContext(ctx, query, valueArgs...)
 	if err != nil {
 		return err
@@ -40,7 +56,7 @@ func (r *SQLRepo) AddUsers(ctx context.Context, users []dto.TeamMember) error {
 func (r *SQLRepo) SetIsActive(ctx context.Context, userID string, isActive bool) error {
 	query := `UPDATE users SET is_active=$1 WHERE id=$2`
 
-	executor := getExecutor(ctx, r.db)
+	executor := getExecutor(ctx, r.db, "SQLRepo.SetIsActive")
 	result, err := executor.ExecContext(ctx, query, isActive, userID)
 	if err != nil {
 		return err
@@ -57,12 +73,115 @@ func (r *SQLRepo) SetIsActive(ctx context.Context, userID string, isActive bool)
 	return nil
 }
 
+func (r *SQLRepo) SetUsersActive(ctx context.Context, userIDs []string, isActive bool) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	query := `UPDATE users SET is_active=$1 WHERE id = ANY($2)`
+
+	executor := getExecutor(ctx, r.db, "SQLRepo.SetUsersActive")
+	_, err := executor.ExecContext(ctx, query, isActive, userIDs)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *SQLRepo) UpdateUser(ctx context.Context, update dto.UpdateUserRequest) error {
+	var setStrings []string
+	var valueArgs []interface{}
+
+	if update.Username != nil {
+		valueArgs = append(valueArgs, *update.Username)
+		setStrings = append(setStrings, fmt.Sprintf("username = $%d", len(valueArgs)))
+	}
+
+	if update.ResetMaxOpenReviews {
+		setStrings = append(setStrings, "max_open_reviews = NULL")
+	} else if update.MaxOpenReviews != nil {
+		valueArgs = append(valueArgs, *update.MaxOpenReviews)
+		setStrings = append(setStrings, fmt.Sprintf("max_open_reviews = $%d", len(valueArgs)))
+	}
+
+	if len(setStrings) == 0 {
+		exists, err := r.IsUserExist(ctx, update.UserID)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return errs.ErrNotFound
+		}
+		return nil
+	}
+
+	valueArgs = append(valueArgs, update.UserID)
+	query := fmt.Sprintf("UPDATE users SET %s WHERE id = $%d", strings.Join(setStrings, ", "), len(valueArgs))
+
+	executor := getExecutor(ctx, r.db, "SQLRepo.UpdateUser")
+	result, err := executor.ExecContext(ctx, query, valueArgs...)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return errs.ErrNotFound
+	}
+	return nil
+}
+
+func (r *SQLRepo) SetAdmin(ctx context.Context, userID string, isAdmin bool) error {
+	query := `UPDATE users SET is_admin = $1 WHERE id = $2`
+
+	executor := getExecutor(ctx, r.db, "SQLRepo.SetAdmin")
+	result, err := executor.ExecContext(ctx, query, isAdmin, userID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return errs.ErrNotFound
+	}
+	return nil
+}
+
+// SetGitHubLogin привязывает github-логин к пользователю, пустой логин снимает привязку.
+func (r *SQLRepo) SetGitHubLogin(ctx context.Context, userID string, login string) error {
+	query := `UPDATE users SET github_login = NULLIF($1, '') WHERE id = $2`
+
+	executor := getExecutor(ctx, r.db, "SQLRepo.SetGitHubLogin")
+	result, err := executor.ExecContext(ctx, query, login, userID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return errs.ErrNotFound
+	}
+	return nil
+}
+
 func (r *SQLRepo) IsUserExist(ctx context.Context, userID string) (bool, error) {
 	query := `SELECT EXISTS(SELECT 1 FROM users WHERE id=$1)`
 
 	var exists bool
 
-	executor := getExecutor(ctx, r.db)
+	executor := getExecutor(ctx, r.db, "SQLRepo.IsUserExist")
 	err := executor.QueryRowContext(ctx, query, userID).Scan(&exists)
 	if err != nil {
 		return false, err
@@ -71,23 +190,139 @@ func (r *SQLRepo) IsUserExist(ctx context.Context, userID string) (bool, error)
 }
 
 func (r *SQLRepo) GetUserByID(ctx context.Context, userID string) (*entities.User, error) {
-	query := `SELECT u.id, u.username, u.is_active, t.team_name FROM users u JOIN team_members tm ON u.id = tm.user_id JOIN teams t ON tm.team_id = t.id WHERE u.id=$1 LIMIT 1`
+	query := `SELECT id, username, is_active, is_admin, max_open_reviews, COALESCE(github_login, '') FROM users WHERE id = $1`
 	var user entities.User
-	var teamName *string
+	var maxOpenReviews sql.NullInt64
 
-	executor := getExecutor(ctx, r.db)
+	executor := getExecutor(ctx, r.db, "SQLRepo.GetUserByID")
 	err := executor.QueryRowContext(ctx, query, userID).Scan(
 		&user.ID,
 		&user.Username,
 		&user.IsActive,
-		&teamName,
+		&user.IsAdmin,
+		&maxOpenReviews,
+		&user.GitHubLogin,
 	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errs.ErrNotFound
+		}
+		return nil, err
+	}
+	if maxOpenReviews.Valid {
+		limit := int(maxOpenReviews.Int64)
+		user.MaxOpenReviews = &limit
+	}
+
+	user.Teams, err = r.getUserTeams(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
-	if teamName != nil {
-		user.TeamName = *teamName
+	for _, team := range user.Teams {
+		if team.IsPrimary {
+			user.TeamName = team.TeamName
+		}
 	}
 
 	return &user, nil
 }
+
+func (r *SQLRepo) getUserTeams(ctx context.Context, userID string) ([]entities.UserTeam, error) {
+	query := `
+        SELECT t.team_name, tm.is_primary
+        FROM team_members tm
+        JOIN teams t ON t.id = tm.team_id
+        WHERE tm.user_id = $1
+        ORDER BY tm.is_primary DESC, t.team_name
+    `
+
+	executor := getExecutor(ctx, r.db, "SQLRepo.getUserTeams")
+	rows, err := executor.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	teams := make([]entities.UserTeam, 0)
+	for rows.Next() {
+		var team entities.UserTeam
+		if err := rows.Scan(&team.TeamName, &team.IsPrimary); err != nil {
+			return nil, err
+		}
+		teams = append(teams, team)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return teams, nil
+}
+
+// SetPrimaryTeam делает команду основной для пользователя. Пользователь должен уже состоять в ней.
+// Для несуществующего пользователя возвращает errs.ErrNotFound.
+func (r *SQLRepo) SetPrimaryTeam(ctx context.Context, userID string, teamName string) error {
+	query := `
+        SELECT u.id, tm.team_id
+        FROM users u
+        LEFT JOIN team_members tm ON tm.user_id = u.id
+            AND tm.team_id = (SELECT id FROM teams WHERE team_name = $2)
+        WHERE u.id = $1
+    `
+
+	executor := getExecutor(ctx, r.db, "SQLRepo.SetPrimaryTeam")
+
+	var foundUserID string
+	var teamID sql.NullString
+	err := executor.QueryRowContext(ctx, query, userID, teamName).Scan(&foundUserID, &teamID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errs.ErrNotFound
+		}
+		return err
+	}
+	if !teamID.Valid {
+		return errs.ErrNotTeamMember
+	}
+
+	_, err = executor.ExecContext(ctx, `UPDATE team_members SET is_primary = FALSE WHERE user_id = $1 AND is_primary`, userID)
+	if err != nil {
+		return err
+	}
+
+	_, err = executor.ExecContext(ctx, `UPDATE team_members SET is_primary = TRUE WHERE user_id = $1 AND team_id = $2`, userID, teamID.String)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *SQLRepo) LockUsers(ctx context.Context, userIDs []string) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	query := `SELECT id FROM users WHERE id = ANY($1) ORDER BY id FOR UPDATE`
+
+	executor := getExecutor(ctx, r.db, "SQLRepo.LockUsers")
+	_, err := executor.ExecContext(ctx, query, userIDs)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetUserIDByGitHubLogin ищет пользователя по логину GitHub без учёта регистра, как его сравнивает сам GitHub.
+func (r *SQLRepo) GetUserIDByGitHubLogin(ctx context.Context, login string) (string, error) {
+	query := `SELECT id FROM users WHERE lower(github_login) = lower($1)`
+
+	var userID string
+	executor := getExecutor(ctx, r.db, "SQLRepo.GetUserIDByGitHubLogin")
+	err := executor.QueryRowContext(ctx, query, login).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", errs.ErrNotFound
+		}
+		return "", err
+	}
+	return userID, nil
+}
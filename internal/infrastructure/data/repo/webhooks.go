@@ -0,0 +1,299 @@
+package repo
+
+import (
+	"PRReviewer/internal/core/dto"
+	"PRReviewer/internal/core/entities"
+	"PRReviewer/internal/core/enums"
+	"PRReviewer/internal/core/errs"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"slices"
+	"time"
+)
+
+type deliveryCursor struct {
+	ID int64 `json:"delivery_id"`
+}
+
+func (r *SQLRepo) CreateWebhookSubscription(ctx context.Context, req dto.CreateWebhookSubscriptionRequest) (*entities.WebhookSubscription, error) {
+	subscription := entities.WebhookSubscription{
+		ID:         uuid.New().String(),
+		URL:        req.URL,
+		Secret:     req.Secret,
+		EventTypes: req.EventTypes,
+	}
+
+	eventTypes := make([]string, len(req.EventTypes))
+	for i, eventType := range req.EventTypes {
+		eventTypes[i] = string(eventType)
+	}
+
+	query := `INSERT INTO webhook_subscriptions (id, url, secret, event_types) VALUES ($1, $2, $3, $4) RETURNING created_at`
+
+	executor := getExecutor(ctx, r.db, "SQLRepo.CreateWebhookSubscription")
+	err := executor.QueryRowContext(ctx, query, subscription.ID, subscription.URL, subscription.Secret, eventTypes).Scan(&subscription.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+func (r *SQLRepo) GetWebhookSubscriptions(ctx context.Context) ([]entities.WebhookSubscription, error) {
+	query := `SELECT id, url, secret, event_types, created_at FROM webhook_subscriptions ORDER BY created_at, id`
+
+	executor := getExecutor(ctx, r.db, "SQLRepo.GetWebhookSubscriptions")
+	rows, err := executor.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	types := pgtype.NewMap()
+	subscriptions := make([]entities.WebhookSubscription, 0)
+	for rows.Next() {
+		var subscription entities.WebhookSubscription
+		var eventTypes []string
+		if err := rows.Scan(&subscription.ID, &subscription.URL, &subscription.Secret, types.SQLScanner(&eventTypes), &subscription.CreatedAt); err != nil {
+			return nil, err
+		}
+		for _, eventType := range eventTypes {
+			subscription.EventTypes = append(subscription.EventTypes, enums.WebhookEventType(eventType))
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return subscriptions, nil
+}
+
+// DeleteWebhookSubscription удаляет подписку вместе с её неотправленными и мёртвыми доставками.
+func (r *SQLRepo) DeleteWebhookSubscription(ctx context.Context, subscriptionID string) error {
+	query := `DELETE FROM webhook_subscriptions WHERE id = $1`
+
+	executor := getExecutor(ctx, r.db, "SQLRepo.DeleteWebhookSubscription")
+	result, err := executor.ExecContext(ctx, query, subscriptionID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errs.ErrNotFound
+	}
+	return nil
+}
+
+// EnqueueWebhookEvents кладёт события в исходящую очередь: по строке на каждую подписку, которой интересен тип события.
+// Должен вызываться в транзакции изменения, чтобы откаченное изменение не породило уведомлений.
+func (r *SQLRepo) EnqueueWebhookEvents(ctx context.Context, payloads []dto.WebhookPayload) error {
+	if len(payloads) == 0 {
+		return nil
+	}
+
+	eventTypes := make([]string, len(payloads))
+	bodies := make([]string, len(payloads))
+	for i, payload := range payloads {
+		body, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("marshal webhook payload: %w", err)
+		}
+		eventTypes[i] = string(payload.Event)
+		bodies[i] = string(body)
+	}
+
+	query := `
+        INSERT INTO webhook_outbox (subscription_id, event_type, payload)
+        SELECT s.id, e.event_type, e.payload::jsonb
+        FROM unnest($1::text[], $2::text[]) WITH ORDINALITY AS e(event_type, payload, ord)
+        JOIN webhook_subscriptions s ON e.event_type = ANY(s.event_types)
+        ORDER BY e.ord, s.id
+    `
+
+	executor := getExecutor(ctx, r.db, "SQLRepo.EnqueueWebhookEvents")
+	_, err := executor.ExecContext(ctx, query, eventTypes, bodies)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// ClaimWebhookDeliveries забирает до limit доставок, срок которых подошёл, и откладывает их на lease,
+// чтобы другой экземпляр не отправил их повторно, пока идёт отправка.
+// Если отправитель упал, доставки вернутся в работу по истечении lease.
+func (r *SQLRepo) ClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]entities.WebhookDelivery, error) {
+	query := `
+        UPDATE webhook_outbox o
+        SET next_attempt_at = now() + make_interval(secs => $3)
+        FROM webhook_subscriptions s
+        WHERE s.id = o.subscription_id
+          AND o.id IN (
+              SELECT id FROM webhook_outbox
+              WHERE status = $1 AND next_attempt_at <= now()
+              ORDER BY next_attempt_at, id
+              LIMIT $2
+              FOR UPDATE SKIP LOCKED
+          )
+        RETURNING o.id, o.subscription_id, s.url, s.secret, o.event_type, o.payload::text, o.status, o.attempts, o.created_at
+    `
+
+	executor := getExecutor(ctx, r.db, "SQLRepo.ClaimWebhookDeliveries")
+	rows, err := executor.QueryContext(ctx, query, enums.DeliveryPending, limit, lease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := make([]entities.WebhookDelivery, 0)
+	for rows.Next() {
+		var delivery entities.WebhookDelivery
+		var payload string
+		err := rows.Scan(&delivery.ID, &delivery.SubscriptionID, &delivery.URL, &delivery.Secret,
+			&delivery.EventType, &payload, &delivery.Status, &delivery.Attempts, &delivery.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		delivery.Payload = json.RawMessage(payload)
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(deliveries, func(a, b entities.WebhookDelivery) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+	return deliveries, nil
+}
+
+func (r *SQLRepo) MarkWebhookDelivered(ctx context.Context, deliveryID int64, statusCode int) error {
+	query := `
+        UPDATE webhook_outbox
+        SET status = $2, attempts = attempts + 1, last_status_code = $3, last_error = NULL, delivered_at = now()
+        WHERE id = $1
+    `
+
+	executor := getExecutor(ctx, r.db, "SQLRepo.MarkWebhookDelivered")
+	_, err := executor.ExecContext(ctx, query, deliveryID, enums.DeliveryDelivered, statusCode)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// MarkWebhookFailed записывает неудачную попытку. Без nextAttemptAt доставка уходит в мёртвые письма.
+// Нулевой statusCode означает, что ответа не было.
+func (r *SQLRepo) MarkWebhookFailed(ctx context.Context, deliveryID int64, statusCode int, reason string, nextAttemptAt *time.Time) error {
+	status := enums.DeliveryDead
+	if nextAttemptAt != nil {
+		status = enums.DeliveryPending
+	}
+
+	query := `
+        UPDATE webhook_outbox
+        SET status = $2, attempts = attempts + 1, last_status_code = NULLIF($3, 0), last_error = $4,
+            next_attempt_at = COALESCE($5, next_attempt_at)
+        WHERE id = $1
+    `
+
+	executor := getExecutor(ctx, r.db, "SQLRepo.MarkWebhookFailed")
+	_, err := executor.ExecContext(ctx, query, deliveryID, status, statusCode, reason, nextAttemptAt)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetDeadLetters возвращает страницу доставок, исчерпавших попытки, от старых к новым.
+func (r *SQLRepo) GetDeadLetters(ctx context.Context, query dto.DeadLettersQuery) ([]entities.WebhookDelivery, string, error) {
+	scope := cursorScope("dead_letters", query.SubscriptionID)
+	var after deliveryCursor
+	if err := decodeCursor(query.Cursor, scope, &after); err != nil {
+		return nil, "", err
+	}
+
+	var f filter
+	f.add("o.status = $%d", enums.DeliveryDead)
+	if query.SubscriptionID != "" {
+		f.add("o.subscription_id = $%d", query.SubscriptionID)
+	}
+	if after.ID != 0 {
+		f.add("o.id > $%d", after.ID)
+	}
+	limit := f.arg(query.Limit + 1)
+
+	sqlQuery := fmt.Sprintf(`
+        SELECT o.id, o.subscription_id, s.url, o.event_type, o.payload::text, o.status, o.attempts,
+               COALESCE(o.last_error, ''), COALESCE(o.last_status_code, 0), o.created_at
+        FROM webhook_outbox o
+        JOIN webhook_subscriptions s ON s.id = o.subscription_id
+        %s
+        ORDER BY o.id
+        LIMIT $%d
+    `, f.where(), limit)
+
+	executor := getExecutor(ctx, r.db, "SQLRepo.GetDeadLetters")
+	rows, err := executor.QueryContext(ctx, sqlQuery, f.args...)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	deliveries := make([]entities.WebhookDelivery, 0)
+	for rows.Next() {
+		var delivery entities.WebhookDelivery
+		var payload string
+		err := rows.Scan(&delivery.ID, &delivery.SubscriptionID, &delivery.URL, &delivery.EventType, &payload,
+			&delivery.Status, &delivery.Attempts, &delivery.LastError, &delivery.LastStatusCode, &delivery.CreatedAt)
+		if err != nil {
+			return nil, "", err
+		}
+		delivery.Payload = json.RawMessage(payload)
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, "", err
+	}
+
+	if len(deliveries) <= query.Limit {
+		return deliveries, "", nil
+	}
+	deliveries = deliveries[:query.Limit]
+	next, err := encodeCursor(scope, deliveryCursor{ID: deliveries[len(deliveries)-1].ID})
+	if err != nil {
+		return nil, "", err
+	}
+	return deliveries, next, nil
+}
+
+// RetryDeadLetters возвращает мёртвые доставки в очередь с обнулённым счётчиком попыток.
+// Возвращает число доставок, которые действительно были мёртвыми.
+func (r *SQLRepo) RetryDeadLetters(ctx context.Context, deliveryIDs []int64) (int, error) {
+	query := `
+        UPDATE webhook_outbox
+        SET status = $2, attempts = 0, next_attempt_at = now()
+        WHERE id = ANY($1) AND status = $3
+    `
+
+	executor := getExecutor(ctx, r.db, "SQLRepo.RetryDeadLetters")
+	result, err := executor.ExecContext(ctx, query, deliveryIDs, enums.DeliveryPending, enums.DeliveryDead)
+	if err != nil {
+		return 0, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return int(rowsAffected), nil
+}
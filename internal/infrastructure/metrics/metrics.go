@@ -0,0 +1,104 @@
+package metrics
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const namespace = "prreviewer"
+
+// Metrics хранит http и доменные метрики сервиса и отдаёт их в формате Prometheus.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	requests        *prometheus.CounterVec
+	requestDuration *prometheus.HistogramVec
+
+	prsCreated  *prometheus.CounterVec
+	prsMerged   *prometheus.CounterVec
+	reassigned  *prometheus.CounterVec
+	noCandidate *prometheus.CounterVec
+}
+
+func New(registry *prometheus.Registry) *Metrics {
+	m := &Metrics{
+		registry: registry,
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "Number of HTTP requests by route and status.",
+		}, []string{"method", "route", "status"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency by route and status.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		prsCreated: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "pull_requests_created_total",
+			Help:      "Number of created pull requests by reviewing team.",
+		}, []string{"team"}),
+		prsMerged: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "pull_requests_merged_total",
+			Help:      "Number of merged pull requests by reviewing team.",
+		}, []string{"team"}),
+		reassigned: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "reviewers_reassigned_total",
+			Help:      "Number of reviewer replacements by reviewing team.",
+		}, []string{"team"}),
+		noCandidate: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "no_candidate_total",
+			Help:      "Number of reviewer selections that found no candidate, by team.",
+		}, []string{"team"}),
+	}
+
+	registry.MustRegister(m.requests, m.requestDuration, m.prsCreated, m.prsMerged, m.reassigned, m.noCandidate)
+	return m
+}
+
+// Middleware считает запросы и их длительность. Маршрут берётся из шаблона gin,
+// чтобы параметры пути не раздували число меток; запросы мимо маршрутов попадают в "unmatched".
+func (m *Metrics) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		status := strconv.Itoa(c.Writer.Status())
+
+		m.requests.WithLabelValues(c.Request.Method, route, status).Inc()
+		m.requestDuration.WithLabelValues(c.Request.Method, route, status).Observe(time.Since(start).Seconds())
+	}
+}
+
+// Handler отдаёт все метрики реестра.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+func (m *Metrics) PRCreated(teamName string) {
+	m.prsCreated.WithLabelValues(teamName).Inc()
+}
+
+func (m *Metrics) PRMerged(teamName string) {
+	m.prsMerged.WithLabelValues(teamName).Inc()
+}
+
+func (m *Metrics) ReviewerReassigned(teamName string) {
+	m.reassigned.WithLabelValues(teamName).Inc()
+}
+
+func (m *Metrics) NoCandidate(teamName string) {
+	m.noCandidate.WithLabelValues(teamName).Inc()
+}
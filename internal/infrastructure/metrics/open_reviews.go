@@ -0,0 +1,54 @@
+package metrics
+
+import (
+	"context"
+	"github.com/prometheus/client_golang/prometheus"
+	"log/slog"
+	"time"
+)
+
+const collectTimeout = 5 * time.Second
+
+type OpenReviewsSource interface {
+	CountOpenReviewsByTeam(ctx context.Context) (map[string]int, error)
+}
+
+// OpenReviewsCollector считает открытые ревью по командам в момент сбора метрик,
+// поэтому значение не расходится с базой после рестарта или ручных правок.
+type OpenReviewsCollector struct {
+	source OpenReviewsSource
+	desc   *prometheus.Desc
+	log    *slog.Logger
+}
+
+func NewOpenReviewsCollector(source OpenReviewsSource, log *slog.Logger) *OpenReviewsCollector {
+	return &OpenReviewsCollector{
+		source: source,
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "open_reviews"),
+			"Number of open review assignments by reviewing team.",
+			[]string{"team"}, nil,
+		),
+		log: log,
+	}
+}
+
+func (c *OpenReviewsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *OpenReviewsCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), collectTimeout)
+	defer cancel()
+
+	counts, err := c.source.CountOpenReviewsByTeam(ctx)
+	if err != nil {
+		c.log.Error("не удалось посчитать открытые ревью по командам", "error", err)
+		ch <- prometheus.NewInvalidMetric(c.desc, err)
+		return
+	}
+
+	for team, count := range counts {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(count), team)
+	}
+}
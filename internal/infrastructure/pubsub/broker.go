@@ -0,0 +1,89 @@
+package pubsub
+
+import (
+	"PRReviewer/internal/core/entities"
+	"PRReviewer/internal/core/errs"
+	"sync"
+)
+
+// bufferSize — сколько событий подписчик может не вычитать, прежде чем брокер его отключит.
+const bufferSize = 64
+
+// Broker раздаёт события ревью подписчикам внутри процесса по id пользователя.
+// Publish не блокируется: подписчика, который не успевает читать, брокер отключает, закрывая его канал,
+// а пропущенное он дочитывает из журнала pr при переподключении.
+type Broker struct {
+	mu          sync.Mutex
+	subscribers map[string]map[chan entities.PREvent]struct{}
+	closed      bool
+}
+
+func NewBroker() *Broker {
+	return &Broker{subscribers: make(map[string]map[chan entities.PREvent]struct{})}
+}
+
+// Subscribe подписывает на события пользователя. Возвращённая функция отписывает и закрывает канал;
+// её можно вызывать повторно. После Close возвращает errs.ErrStreamClosed.
+func (b *Broker) Subscribe(userID string) (<-chan entities.PREvent, func(), error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return nil, nil, errs.ErrStreamClosed
+	}
+
+	ch := make(chan entities.PREvent, bufferSize)
+	if b.subscribers[userID] == nil {
+		b.subscribers[userID] = make(map[chan entities.PREvent]struct{})
+	}
+	b.subscribers[userID][ch] = struct{}{}
+
+	return ch, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		b.remove(userID, ch)
+	}, nil
+}
+
+func (b *Broker) Publish(userID string, event entities.PREvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.subscribers[userID] {
+		select {
+		case ch <- event:
+		default:
+			b.remove(userID, ch)
+		}
+	}
+}
+
+// Close отключает всех подписчиков и больше не принимает новых.
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for userID, subscribers := range b.subscribers {
+		for ch := range subscribers {
+			b.remove(userID, ch)
+		}
+	}
+}
+
+// remove вызывается под mu.
+func (b *Broker) remove(userID string, ch chan entities.PREvent) {
+	subscribers, ok := b.subscribers[userID]
+	if !ok {
+		return
+	}
+	if _, ok := subscribers[ch]; !ok {
+		return
+	}
+
+	delete(subscribers, ch)
+	close(ch)
+	if len(subscribers) == 0 {
+		delete(b.subscribers, userID)
+	}
+}
@@ -0,0 +1,274 @@
+package webhook
+
+import (
+	"PRReviewer/internal/core/entities"
+	"PRReviewer/internal/core/errs"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const (
+	HeaderEvent     = "X-PRReviewer-Event"
+	HeaderDelivery  = "X-PRReviewer-Delivery"
+	HeaderSignature = "X-PRReviewer-Signature-256"
+	HeaderTimestamp = "X-PRReviewer-Timestamp"
+
+	// leaseMargin добавляется к таймауту запроса, чтобы доставка не вернулась в очередь, пока её ещё отправляют.
+	leaseMargin = 30 * time.Second
+	// maxErrorLength ограничивает длину ошибки, сохраняемой в очереди.
+	maxErrorLength = 512
+	// markTimeout ограничивает запись результата доставки, которая выполняется и после отмены ctx диспетчера.
+	markTimeout = 5 * time.Second
+)
+
+type Store interface {
+	ClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]entities.WebhookDelivery, error)
+	MarkWebhookDelivered(ctx context.Context, deliveryID int64, statusCode int) error
+	MarkWebhookFailed(ctx context.Context, deliveryID int64, statusCode int, reason string, nextAttemptAt *time.Time) error
+}
+
+type Config struct {
+	PollInterval time.Duration
+	Timeout      time.Duration
+	BaseBackoff  time.Duration
+	MaxBackoff   time.Duration
+	BatchSize    int
+	// MaxAttempts — число попыток, после которого доставка уходит в мёртвые письма.
+	MaxAttempts int
+	// AllowPrivateTargets разрешает отправку на loopback и частные адреса. Нужен только для локальной разработки и тестов.
+	AllowPrivateTargets bool
+}
+
+func DefaultConfig() Config {
+	return Config{
+		PollInterval: time.Second,
+		Timeout:      10 * time.Second,
+		BaseBackoff:  10 * time.Second,
+		MaxBackoff:   time.Hour,
+		BatchSize:    50,
+		MaxAttempts:  10,
+	}
+}
+
+// Dispatcher отправляет вебхуки из исходящей очереди.
+// Доставка гарантируется не реже одного раза: получатель должен быть готов к повторам с тем же X-PRReviewer-Delivery.
+type Dispatcher struct {
+	store  Store
+	cfg    Config
+	client *http.Client
+	log    *slog.Logger
+}
+
+func NewDispatcher(store Store, cfg Config, log *slog.Logger) *Dispatcher {
+	dialer := &net.Dialer{Timeout: cfg.Timeout}
+	if !cfg.AllowPrivateTargets {
+		// адрес проверяется после разрешения имени, поэтому DNS не может подменить его на внутренний
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return err
+			}
+			if !PublicAddr(addrPort.Addr()) {
+				return fmt.Errorf("адрес %s недоступен для вебхуков", addrPort.Addr())
+			}
+			return nil
+		}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &Dispatcher{
+		store: store,
+		cfg:   cfg,
+		client: &http.Client{
+			Timeout:   cfg.Timeout,
+			Transport: transport,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		log: log,
+	}
+}
+
+// Run опрашивает очередь до отмены ctx.
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := d.DispatchOnce(ctx); err != nil && ctx.Err() == nil {
+				d.log.Error("не удалось отправить вебхуки", "error", err)
+			}
+		}
+	}
+}
+
+// ValidateTarget проверяет адрес подписки: только http(s) и, если не разрешено явно, не loopback и не частная сеть.
+// Имена хостов окончательно проверяются при каждом соединении, когда известен их адрес.
+func (d *Dispatcher) ValidateTarget(rawURL string) error {
+	target, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errs.ErrInvalidWebhookURL, err)
+	}
+	if target.Scheme != "http" && target.Scheme != "https" {
+		return fmt.Errorf("%w: поддерживаются только http и https", errs.ErrInvalidWebhookURL)
+	}
+
+	host := target.Hostname()
+	if host == "" {
+		return fmt.Errorf("%w: не указан хост", errs.ErrInvalidWebhookURL)
+	}
+	if d.cfg.AllowPrivateTargets {
+		return nil
+	}
+
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return fmt.Errorf("%w: адрес указывает на локальный хост", errs.ErrInvalidWebhookURL)
+	}
+	if addr, err := netip.ParseAddr(host); err == nil && !PublicAddr(addr) {
+		return fmt.Errorf("%w: адрес %s недоступен для вебхуков", errs.ErrInvalidWebhookURL, addr)
+	}
+	return nil
+}
+
+// DispatchOnce забирает одну пачку доставок, которым пора уходить, и отправляет их параллельно.
+// Возвращает число забранных доставок.
+func (d *Dispatcher) DispatchOnce(ctx context.Context) (int, error) {
+	deliveries, err := d.store.ClaimWebhookDeliveries(ctx, d.cfg.BatchSize, d.cfg.Timeout+leaseMargin)
+	if err != nil {
+		return 0, err
+	}
+
+	var wg sync.WaitGroup
+	for _, delivery := range deliveries {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			d.deliver(ctx, delivery)
+		}()
+	}
+	wg.Wait()
+
+	return len(deliveries), nil
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, delivery entities.WebhookDelivery) {
+	statusCode, err := d.send(ctx, delivery)
+
+	// результат записывается, даже если диспетчер уже останавливают, иначе доставка будет ждать истечения аренды
+	markCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), markTimeout)
+	defer cancel()
+
+	if err == nil {
+		if err := d.store.MarkWebhookDelivered(markCtx, delivery.ID, statusCode); err != nil {
+			d.log.Error("не удалось отметить вебхук доставленным", "error", err, "delivery ID", delivery.ID)
+		}
+		return
+	}
+
+	reason := err.Error()
+	if len(reason) > maxErrorLength {
+		reason = reason[:maxErrorLength]
+	}
+
+	var nextAttemptAt *time.Time
+	attempt := delivery.Attempts + 1
+	if attempt < d.cfg.MaxAttempts {
+		next := time.Now().Add(d.backoff(attempt))
+		nextAttemptAt = &next
+	} else {
+		d.log.Warn("вебхук исчерпал попытки доставки", "delivery ID", delivery.ID, "url", delivery.URL, "error", reason)
+	}
+
+	if err := d.store.MarkWebhookFailed(markCtx, delivery.ID, statusCode, reason, nextAttemptAt); err != nil {
+		d.log.Error("не удалось записать неудачную доставку вебхука", "error", err, "delivery ID", delivery.ID)
+	}
+}
+
+// send возвращает код ответа получателя или 0, если ответа не было. Успехом считается любой 2xx.
+func (d *Dispatcher) send(ctx context.Context, delivery entities.WebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, string(delivery.EventType))
+	req.Header.Set(HeaderDelivery, strconv.FormatInt(delivery.ID, 10))
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderSignature, Sign(delivery.Secret, SignedPayload(timestamp, delivery.Payload)))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("получатель ответил %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+// backoff возвращает паузу перед следующей попыткой: BaseBackoff, удваиваемый с каждой попыткой, но не больше MaxBackoff.
+// Случайная половина паузы разносит повторы доставок, упавших одновременно.
+func (d *Dispatcher) backoff(attempt int) time.Duration {
+	delay := d.cfg.BaseBackoff
+	for i := 1; i < attempt && delay < d.cfg.MaxBackoff; i++ {
+		delay *= 2
+	}
+	delay = min(delay, d.cfg.MaxBackoff)
+
+	half := delay / 2
+	if half <= 0 {
+		return delay
+	}
+	return half + rand.N(delay-half+1)
+}
+
+// SignedPayload возвращает подписываемую строку "timestamp.body". Метка времени из X-PRReviewer-Timestamp входит в подпись,
+// чтобы получатель мог отбрасывать старые запросы и перехваченный запрос нельзя было повторить позже.
+func SignedPayload(timestamp string, body []byte) []byte {
+	payload := make([]byte, 0, len(timestamp)+1+len(body))
+	payload = append(payload, timestamp...)
+	payload = append(payload, '.')
+	return append(payload, body...)
+}
+
+// PublicAddr сообщает, можно ли отправлять вебхук на адрес: loopback, частные, link-local и служебные адреса запрещены.
+func PublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsGlobalUnicast() && !addr.IsPrivate() && !addr.IsLoopback() && !addr.IsLinkLocalUnicast()
+}
+
+// Sign возвращает подпись в формате заголовка X-PRReviewer-Signature-256: "sha256=" и hex HMAC-SHA256 с секретом подписки.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
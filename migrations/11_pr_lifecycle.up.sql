@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS pull_request_files
+(
+    pr_id VARCHAR(36),
+    path TEXT,
+    PRIMARY KEY (pr_id, path),
+    FOREIGN KEY (pr_id) REFERENCES pull_requests(id) ON DELETE CASCADE
+);
+
+ALTER TABLE pull_requests
+    ADD COLUMN IF NOT EXISTS closed_at TIMESTAMPTZ;
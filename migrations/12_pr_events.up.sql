@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS pr_events
+(
+    id BIGSERIAL PRIMARY KEY,
+    pr_id VARCHAR(36) NOT NULL,
+    event_type TEXT NOT NULL,
+    actor_id VARCHAR(36),
+    reviewer_id VARCHAR(36),
+    old_reviewer_id VARCHAR(36),
+    decision TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    FOREIGN KEY (pr_id) REFERENCES pull_requests(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS pr_events_pr_id_idx ON pr_events (pr_id, id);
@@ -0,0 +1,15 @@
+ALTER TABLE pull_requests
+    ADD COLUMN IF NOT EXISTS team_id VARCHAR(36) REFERENCES teams(id) ON DELETE SET NULL;
+
+UPDATE pull_requests p
+SET team_id = (
+    SELECT tm.team_id
+    FROM team_members tm
+    JOIN teams t ON t.id = tm.team_id
+    WHERE tm.user_id = p.author_id
+    ORDER BY t.team_name
+    LIMIT 1
+)
+WHERE p.team_id IS NULL;
+
+CREATE INDEX IF NOT EXISTS pull_requests_team_id_idx ON pull_requests (team_id);
@@ -0,0 +1,13 @@
+ALTER TABLE team_members
+    ADD COLUMN IF NOT EXISTS is_primary BOOLEAN NOT NULL DEFAULT FALSE;
+
+UPDATE team_members
+SET is_primary = TRUE
+WHERE (team_id, user_id) IN (
+    SELECT DISTINCT ON (tm.user_id) tm.team_id, tm.user_id
+    FROM team_members tm
+    JOIN teams t ON t.id = tm.team_id
+    ORDER BY tm.user_id, t.team_name
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS team_members_primary_idx ON team_members (user_id) WHERE is_primary;
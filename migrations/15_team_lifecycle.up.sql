@@ -0,0 +1,17 @@
+ALTER TABLE teams
+    ADD COLUMN IF NOT EXISTS archived_at TIMESTAMPTZ;
+
+CREATE TABLE IF NOT EXISTS team_events
+(
+    id BIGSERIAL PRIMARY KEY,
+    team_id VARCHAR(36) NOT NULL,
+    team_name TEXT NOT NULL,
+    old_team_name TEXT,
+    event_type TEXT NOT NULL,
+    actor_id VARCHAR(36),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS team_events_team_id_idx ON team_events (team_id, id);
+CREATE INDEX IF NOT EXISTS team_events_team_name_idx ON team_events (team_name);
+CREATE INDEX IF NOT EXISTS team_events_old_team_name_idx ON team_events (old_team_name);
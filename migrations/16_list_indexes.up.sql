@@ -0,0 +1,10 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS teams_team_name_trgm_idx ON teams USING gin (team_name gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS users_username_trgm_idx ON users USING gin (username gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS team_members_user_id_idx ON team_members (user_id);
+
+CREATE INDEX IF NOT EXISTS pull_requests_created_idx ON pull_requests (created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS pull_requests_status_created_idx ON pull_requests (status, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS pull_requests_author_created_idx ON pull_requests (author_id, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS pull_requests_team_created_idx ON pull_requests (team_id, created_at DESC, id DESC);
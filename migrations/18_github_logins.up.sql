@@ -0,0 +1,4 @@
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS github_login TEXT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS users_github_login_idx ON users (lower(github_login));
@@ -0,0 +1,2 @@
+ALTER TABLE pull_requests
+    ADD COLUMN IF NOT EXISTS status_before_close TEXT;
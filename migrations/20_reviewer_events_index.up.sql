@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS pr_events_reviewer_id_idx ON pr_events (reviewer_id, id);
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS api_tokens
+(
+    token_hash CHAR(64) PRIMARY KEY,
+    user_id VARCHAR(36) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS api_tokens_user_id_idx ON api_tokens (user_id);
+
+ALTER TABLE merge_overrides
+    ALTER COLUMN actor_id DROP NOT NULL;
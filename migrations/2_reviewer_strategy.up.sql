@@ -0,0 +1,5 @@
+ALTER TABLE teams
+    ADD COLUMN IF NOT EXISTS reviewer_strategy TEXT NOT NULL DEFAULT 'random';
+
+ALTER TABLE team_members
+    ADD COLUMN IF NOT EXISTS review_weight INT NOT NULL DEFAULT 1;
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS team_fallbacks
+(
+    team_id VARCHAR(36),
+    fallback_team_id VARCHAR(36),
+    position INT NOT NULL,
+    PRIMARY KEY (team_id, fallback_team_id),
+    FOREIGN KEY (team_id) REFERENCES teams(id) ON DELETE CASCADE,
+    FOREIGN KEY (fallback_team_id) REFERENCES teams(id) ON DELETE CASCADE,
+    CHECK (team_id <> fallback_team_id)
+);
+
+ALTER TABLE pull_request_reviewers
+    ADD COLUMN IF NOT EXISTS fallback_team_id VARCHAR(36) REFERENCES teams(id) ON DELETE SET NULL;
@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS team_owner_groups
+(
+    team_id VARCHAR(36),
+    group_name TEXT,
+    user_id VARCHAR(36),
+    PRIMARY KEY (team_id, group_name, user_id),
+    FOREIGN KEY (team_id) REFERENCES teams(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS team_ownership_rules
+(
+    team_id VARCHAR(36),
+    position INT,
+    pattern TEXT NOT NULL,
+    PRIMARY KEY (team_id, position),
+    FOREIGN KEY (team_id) REFERENCES teams(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS team_ownership_rule_owners
+(
+    team_id VARCHAR(36),
+    position INT,
+    owner TEXT,
+    PRIMARY KEY (team_id, position, owner),
+    FOREIGN KEY (team_id, position) REFERENCES team_ownership_rules(team_id, position) ON DELETE CASCADE
+);
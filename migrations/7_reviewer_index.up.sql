@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS idx_pull_request_reviewers_reviewer_id ON pull_request_reviewers (reviewer_id);
@@ -0,0 +1,3 @@
+ALTER TABLE pull_request_reviewers
+    ADD COLUMN IF NOT EXISTS decision TEXT NOT NULL DEFAULT 'PENDING',
+    ADD COLUMN IF NOT EXISTS decided_at TIMESTAMPTZ;
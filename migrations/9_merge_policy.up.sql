@@ -0,0 +1,19 @@
+ALTER TABLE teams
+    ADD COLUMN IF NOT EXISTS merge_min_approvals INT NOT NULL DEFAULT 0 CHECK (merge_min_approvals >= 0),
+    ADD COLUMN IF NOT EXISTS merge_block_on_changes_requested BOOLEAN NOT NULL DEFAULT TRUE,
+    ADD COLUMN IF NOT EXISTS merge_require_all_approved BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS is_admin BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE IF NOT EXISTS merge_overrides
+(
+    id BIGSERIAL PRIMARY KEY,
+    pr_id VARCHAR(36) NOT NULL,
+    actor_id VARCHAR(36) NOT NULL,
+    reason TEXT,
+    unmet_conditions TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    FOREIGN KEY (pr_id) REFERENCES pull_requests(id) ON DELETE CASCADE,
+    FOREIGN KEY (actor_id) REFERENCES users(id)
+);
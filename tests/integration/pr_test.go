@@ -0,0 +1,2694 @@
+package integration
+
+import (
+	"PRReviewer/internal/adapter/server/handlers"
+	"PRReviewer/internal/core/dto"
+	"PRReviewer/internal/core/entities"
+	"PRReviewer/internal/core/enums"
+	"PRReviewer/internal/core/errs"
+	"PRReviewer/internal/core/selector"
+	"PRReviewer/internal/core/service"
+	"PRReviewer/internal/infrastructure/data/repo"
+	"PRReviewer/internal/infrastructure/metrics"
+	"PRReviewer/internal/infrastructure/pubsub"
+	"PRReviewer/internal/infrastructure/webhook"
+	"bufio"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+const gitHubSecret = "github-webhook-secret"
+
+const adminToken = "admin-token"
+
+type PullRequestIntegrationTestSuite struct {
+	suite.Suite
+	postgresContainer *postgres.PostgresContainer
+	router            *gin.Engine
+	ctx               context.Context
+	db                *sql.DB
+	repository        *repo.SQLRepo
+	prService         *service.PullRequestService
+	authService       *service.AuthService
+	spans             *tracetest.SpanRecorder
+}
+
+func TestPullRequestIntegrationTestSuite(t *testing.T) {
+	suite.Run(t, new(PullRequestIntegrationTestSuite))
+}
+
+func (suite *PullRequestIntegrationTestSuite) SetupSuite() {
+	suite.ctx = context.Background()
+	suite.spans = tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(suite.spans)))
+
+	postgresContainer, db, err := startPostgres(suite.ctx)
+	suite.postgresContainer = postgresContainer
+	suite.db = db
+	suite.Require().NoError(err)
+
+	_, err = applyMigrations(db, migrationsDir)
+	suite.Require().NoError(err, "Failed to apply migrations")
+
+	repository := repo.New(db)
+	suite.repository = repository
+	transactor := repo.NewSQLTransactor(db)
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{
+		Level: slog.LevelError,
+	}))
+
+	appMetrics := metrics.New(prometheus.NewRegistry())
+	prService := service.NewPullRequestService(repository, repository, repository, transactor, selector.NewRegistry(), appMetrics, pubsub.NewBroker(), logger)
+	suite.prService = prService
+	teamHandler := handlers.NewTeamHandler(service.NewTeamService(repository, repository, prService, transactor, logger))
+	prHandler := handlers.NewPullRequestHandler(prService)
+	usersHandler := handlers.NewUsersHandler(service.NewUsersService(repository, prService, transactor, logger))
+	statsHandler := handlers.NewStatsHandler(service.NewStatsService(repository, repository, logger))
+	webhookHandler := handlers.NewWebhookHandler(service.NewWebhookService(repository, suite.newDispatcher(1), logger))
+	streamHandler := handlers.NewStreamHandler(prService, handlers.DefaultHeartbeatInterval)
+	githubHandler := handlers.NewGitHubHandler(service.NewGitHubService(prService, repository, logger), gitHubSecret)
+	suite.authService = service.NewAuthService(repository, repository, adminToken, logger)
+	authHandler := handlers.NewAuthHandler(suite.authService)
+
+	suite.router = gin.Default()
+	suite.router.Use(otelgin.Middleware("PRReviewer"), appMetrics.Middleware(), authHandler.Authenticate)
+	suite.router.GET("/metrics", gin.WrapH(appMetrics.Handler()))
+	suite.router.POST("/team/add", teamHandler.CreateTeam)
+	suite.router.GET("/team/get", teamHandler.GetTeam)
+	suite.router.POST("/team/settings", authHandler.RequireAdmin, teamHandler.UpdateSettings)
+	suite.router.POST("/team/owners/set", authHandler.RequireAdmin, teamHandler.SetOwnership)
+	suite.router.POST("/team/deactivateUsers", authHandler.RequireAdmin, teamHandler.DeactivateUsers)
+	suite.router.POST("/team/members/add", authHandler.RequireAdmin, teamHandler.AddMembers)
+	suite.router.POST("/team/members/remove", authHandler.RequireAdmin, teamHandler.RemoveMember)
+	suite.router.POST("/team/members/move", authHandler.RequireAdmin, teamHandler.MoveMember)
+	suite.router.POST("/team/rename", authHandler.RequireAdmin, teamHandler.RenameTeam)
+	suite.router.POST("/team/archive", authHandler.RequireAdmin, teamHandler.ArchiveTeam)
+	suite.router.POST("/team/delete", authHandler.RequireAdmin, teamHandler.DeleteTeam)
+	suite.router.GET("/team/history", teamHandler.GetHistory)
+	suite.router.GET("/team/list", teamHandler.ListTeams)
+	suite.router.POST("/pullRequest/create", prHandler.CreatePullRequest)
+	suite.router.POST("/pullRequest/merge", prHandler.MergerPullRequest)
+	suite.router.POST("/pullRequest/reassign", prHandler.ReassignPullRequest)
+	suite.router.POST("/pullRequest/review", prHandler.SubmitReview)
+	suite.router.POST("/pullRequest/ready", prHandler.MarkReadyForReview)
+	suite.router.POST("/pullRequest/close", prHandler.ClosePullRequest)
+	suite.router.POST("/pullRequest/reopen", prHandler.ReopenPullRequest)
+	suite.router.POST("/pullRequest/draft", prHandler.ConvertToDraft)
+	suite.router.GET("/pullRequest/history", prHandler.GetHistory)
+	suite.router.GET("/pullRequest/list", prHandler.ListPullRequests)
+	suite.router.GET("/users/getReview", prHandler.GetReview)
+	suite.router.GET("/users/reviewStream", streamHandler.ReviewStream)
+	suite.router.POST("/users/setIsActive", authHandler.RequireAdmin, usersHandler.SetIsActive)
+	suite.router.POST("/users/update", authHandler.RequireAdmin, usersHandler.UpdateUser)
+	suite.router.POST("/users/setPrimaryTeam", authHandler.RequireAdmin, usersHandler.SetPrimaryTeam)
+	suite.router.GET("/users/list", usersHandler.ListUsers)
+	suite.router.GET("/stats/reviewers", statsHandler.GetReviewerStats)
+	suite.router.GET("/stats/teams", statsHandler.GetTeamStats)
+	suite.router.POST("/webhooks/subscriptions/add", authHandler.RequireAdmin, webhookHandler.CreateSubscription)
+	suite.router.GET("/webhooks/deadLetters", authHandler.RequireAdmin, webhookHandler.GetDeadLetters)
+	suite.router.POST("/webhooks/deadLetters/retry", authHandler.RequireAdmin, webhookHandler.RetryDeadLetters)
+	suite.router.POST("/integrations/github/webhook", githubHandler.Webhook)
+	suite.router.POST("/admin/users/setAdmin", authHandler.RequireAdmin, usersHandler.SetAdmin)
+	suite.router.POST("/admin/users/setGitHubLogin", authHandler.RequireAdmin, usersHandler.SetGitHubLogin)
+	suite.router.POST("/admin/tokens/issue", authHandler.RequireAdmin, authHandler.IssueToken)
+}
+
+func (suite *PullRequestIntegrationTestSuite) TearDownSuite() {
+	if suite.db != nil {
+		suite.db.Close()
+	}
+	if suite.postgresContainer != nil {
+		suite.Require().NoError(suite.postgresContainer.Terminate(suite.ctx))
+	}
+}
+
+func (suite *PullRequestIntegrationTestSuite) SetupTest() {
+	if suite.db != nil {
+		_, err := suite.db.ExecContext(suite.ctx, `
+            DELETE FROM webhook_subscriptions;
+            DELETE FROM pull_request_reviewers;
+            DELETE FROM pull_requests;
+            DELETE FROM team_members;
+            DELETE FROM teams;
+            DELETE FROM users;
+        `)
+		if err != nil {
+			suite.T().Logf("error: %s", err)
+		}
+	}
+}
+
+func (suite *PullRequestIntegrationTestSuite) makeRequest(method, path string, body interface{}) *httptest.ResponseRecorder {
+	return suite.makeAuthorizedRequest(method, path, "", body)
+}
+
+// makeAuthorizedRequest отправляет запрос с bearer-токеном; пустой токен означает анонимный запрос.
+func (suite *PullRequestIntegrationTestSuite) makeAuthorizedRequest(method, path, token string, body interface{}) *httptest.ResponseRecorder {
+	var bodyBytes []byte
+	if body != nil {
+		var err error
+		bodyBytes, err = json.Marshal(body)
+		suite.Require().NoError(err)
+	}
+
+	req, err := http.NewRequest(method, path, bytes.NewBuffer(bodyBytes))
+	suite.Require().NoError(err)
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	return w
+}
+
+// issueToken выдаёт пользователю токен через сервис, как это делает администратор.
+func (suite *PullRequestIntegrationTestSuite) issueToken(userID string) string {
+	issued, err := suite.authService.IssueToken(suite.ctx, dto.IssueTokenRequest{UserID: userID})
+	suite.Require().NoError(err)
+	return issued.Token
+}
+
+func (suite *PullRequestIntegrationTestSuite) createTeam(team dto.Team) {
+	response := suite.makeRequest("POST", "/team/add", team)
+	suite.Require().Equal(http.StatusCreated, response.Code, response.Body.String())
+}
+
+// createPR создаёт pr от имени его автора.
+func (suite *PullRequestIntegrationTestSuite) createPR(id, authorID string) entities.PullRequest {
+	response := suite.makeAuthorizedRequest("POST", "/pullRequest/create", suite.issueToken(authorID), dto.CreatePullRequest{
+		PullRequestID:   id,
+		PullRequestName: "PR " + id,
+		AuthorID:        authorID,
+	})
+	suite.Require().Equal(http.StatusCreated, response.Code, response.Body.String())
+
+	var pr entities.PullRequest
+	suite.Require().NoError(json.Unmarshal(response.Body.Bytes(), &pr))
+	return pr
+}
+
+func reviewerIDs(pr entities.PullRequest) []string {
+	ids := make([]string, len(pr.Reviewers))
+	for i, reviewer := range pr.Reviewers {
+		ids[i] = reviewer.UserID
+	}
+	return ids
+}
+
+func (suite *PullRequestIntegrationTestSuite) TestCreatePR_WhenTeamUsesRoundRobin_ShouldRotateReviewers() {
+	// Arrange
+	suite.createTeam(dto.Team{
+		TeamName: "round-robin",
+		Members: []dto.TeamMember{
+			{UserID: "rr1", Username: "Alice", IsActive: true},
+			{UserID: "rr2", Username: "Bob", IsActive: true},
+			{UserID: "rr3", Username: "Carol", IsActive: true},
+			{UserID: "rr4", Username: "Dave", IsActive: true},
+		},
+		TeamSettings: dto.TeamSettings{ReviewerStrategy: "round_robin"},
+	})
+
+	// Act
+	first := suite.createPR("pr-rr-1", "rr1")
+	second := suite.createPR("pr-rr-2", "rr1")
+
+	// Assert
+	assert.ElementsMatch(suite.T(), []string{"rr2", "rr3"}, reviewerIDs(first))
+	assert.ElementsMatch(suite.T(), []string{"rr4", "rr2"}, reviewerIDs(second))
+}
+
+func (suite *PullRequestIntegrationTestSuite) TestCreatePR_WhenTeamUsesLeastLoaded_ShouldSkipBusyReviewers() {
+	// Arrange
+	suite.createTeam(dto.Team{
+		TeamName: "least-loaded",
+		Members: []dto.TeamMember{
+			{UserID: "ll1", Username: "Alice", IsActive: true},
+			{UserID: "ll2", Username: "Bob", IsActive: true},
+			{UserID: "ll3", Username: "Carol", IsActive: true},
+			{UserID: "ll4", Username: "Dave", IsActive: true},
+		},
+		TeamSettings: dto.TeamSettings{ReviewerStrategy: "least_loaded"},
+	})
+	first := suite.createPR("pr-ll-1", "ll1")
+
+	// Act
+	second := suite.createPR("pr-ll-2", "ll1")
+
+	// Assert
+	assert.Len(suite.T(), reviewerIDs(second), 2)
+	idle := []string{"ll2", "ll3", "ll4"}
+	for _, id := range reviewerIDs(first) {
+		idle = removeID(idle, id)
+	}
+	assert.Contains(suite.T(), reviewerIDs(second), idle[0])
+}
+
+func (suite *PullRequestIntegrationTestSuite) TestCreateTeam_WhenStrategyUnknown_ShouldReturnBadRequest() {
+	// Arrange
+	team := dto.Team{
+		TeamName:     "unknown-strategy",
+		Members:      []dto.TeamMember{{UserID: "us1", Username: "Alice", IsActive: true}},
+		TeamSettings: dto.TeamSettings{ReviewerStrategy: "by_mood"},
+	}
+
+	// Act
+	response := suite.makeRequest("POST", "/team/add", team)
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusBadRequest, response.Code)
+}
+
+func removeID(ids []string, id string) []string {
+	result := make([]string, 0, len(ids))
+	for _, existing := range ids {
+		if existing != id {
+			result = append(result, existing)
+		}
+	}
+	return result
+}
+
+func (suite *PullRequestIntegrationTestSuite) TestCreatePR_WhenAllReviewersAtCapacity_ShouldReturnNoCandidate() {
+	// Arrange
+	limit := 1
+	suite.createTeam(dto.Team{
+		TeamName: "capacity",
+		Members: []dto.TeamMember{
+			{UserID: "cap1", Username: "Alice", IsActive: true},
+			{UserID: "cap2", Username: "Bob", IsActive: true, MaxOpenReviews: &limit},
+			{UserID: "cap3", Username: "Carol", IsActive: true, MaxOpenReviews: &limit},
+		},
+	})
+	suite.createPR("pr-cap-1", "cap1")
+
+	// Act
+	response := suite.makeRequest("POST", "/pullRequest/create", dto.CreatePullRequest{
+		PullRequestID:   "pr-cap-2",
+		PullRequestName: "PR pr-cap-2",
+		AuthorID:        "cap1",
+	})
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusConflict, response.Code)
+
+	var errorResponse dto.ErrorResponse
+	suite.Require().NoError(json.Unmarshal(response.Body.Bytes(), &errorResponse))
+	assert.Equal(suite.T(), enums.CodeNoCandidate, errorResponse.Code)
+}
+
+// makeConcurrentRequests отправляет запросы одновременно и возвращает ответы в порядке запросов.
+// Тела сериализуются заранее, чтобы проверки suite не вызывались из других горутин.
+func (suite *PullRequestIntegrationTestSuite) makeConcurrentRequests(path string, bodies []interface{}) []*httptest.ResponseRecorder {
+	requests := make([]*http.Request, len(bodies))
+	for i, body := range bodies {
+		bodyBytes, err := json.Marshal(body)
+		suite.Require().NoError(err)
+
+		requests[i], err = http.NewRequest("POST", path, bytes.NewBuffer(bodyBytes))
+		suite.Require().NoError(err)
+		requests[i].Header.Set("Content-Type", "application/json")
+	}
+
+	responses := make([]*httptest.ResponseRecorder, len(requests))
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for i, req := range requests {
+		responses[i] = httptest.NewRecorder()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			suite.router.ServeHTTP(responses[i], req)
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	return responses
+}
+
+func (suite *PullRequestIntegrationTestSuite) TestCreatePR_WhenCreatedConcurrently_ShouldNotExceedReviewerCapacity() {
+	// Arrange
+	limit := 1
+	suite.createTeam(dto.Team{
+		TeamName: "concurrent-create",
+		Members: []dto.TeamMember{
+			{UserID: "cc1", Username: "Alice", IsActive: true},
+			{UserID: "cc2", Username: "Bob", IsActive: true, MaxOpenReviews: &limit},
+			{UserID: "cc3", Username: "Carol", IsActive: true, MaxOpenReviews: &limit},
+			{UserID: "cc4", Username: "Dave", IsActive: true, MaxOpenReviews: &limit},
+		},
+		TeamSettings: dto.TeamSettings{ReviewersPerPR: 1},
+	})
+
+	bodies := make([]interface{}, 8)
+	for i := range bodies {
+		id := "pr-cc-" + strconv.Itoa(i)
+		bodies[i] = dto.CreatePullRequest{PullRequestID: id, PullRequestName: "PR " + id, AuthorID: "cc1"}
+	}
+
+	// Act
+	responses := suite.makeConcurrentRequests("/pullRequest/create", bodies)
+
+	// Assert
+	assigned := make([]string, 0)
+	for _, response := range responses {
+		if response.Code != http.StatusCreated {
+			suite.Require().Equal(http.StatusConflict, response.Code, response.Body.String())
+
+			var errorResponse dto.ErrorResponse
+			suite.Require().NoError(json.Unmarshal(response.Body.Bytes(), &errorResponse))
+			assert.Equal(suite.T(), enums.CodeNoCandidate, errorResponse.Code)
+			continue
+		}
+
+		var pr entities.PullRequest
+		suite.Require().NoError(json.Unmarshal(response.Body.Bytes(), &pr))
+		assigned = append(assigned, reviewerIDs(pr)...)
+	}
+
+	assert.ElementsMatch(suite.T(), []string{"cc2", "cc3", "cc4"}, assigned)
+}
+
+func (suite *PullRequestIntegrationTestSuite) TestMergePR_WhenMergedAndClosedConcurrently_ShouldApplyOneOutcome() {
+	// Arrange
+	suite.createTeam(dto.Team{
+		TeamName: "racers",
+		Members: []dto.TeamMember{
+			{UserID: "rc1", Username: "Alice", IsActive: true},
+			{UserID: "rc2", Username: "Bob", IsActive: true},
+		},
+	})
+	suite.createPR("pr-rc-1", "rc1")
+
+	bodies := make([]interface{}, 8)
+	for i := range bodies {
+		bodies[i] = dto.MergePullRequest{PullRequestID: "pr-rc-1"}
+	}
+
+	// Act
+	var closed *httptest.ResponseRecorder
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		closed = suite.makeRequest("POST", "/pullRequest/close", dto.PullRequestTransition{PullRequestID: "pr-rc-1"})
+	}()
+	responses := suite.makeConcurrentRequests("/pullRequest/merge", bodies)
+	<-done
+
+	// Assert
+	response := suite.makeRequest("GET", "/pullRequest/history?pull_request_id=pr-rc-1", nil)
+	suite.Require().Equal(http.StatusOK, response.Code, response.Body.String())
+	var history dto.PullRequestHistoryResponse
+	suite.Require().NoError(json.Unmarshal(response.Body.Bytes(), &history))
+
+	outcomes := 0
+	for _, event := range history.Events {
+		if event.Type == enums.EventMerged || event.Type == enums.EventClosed {
+			outcomes++
+		}
+	}
+	assert.Equal(suite.T(), 1, outcomes)
+
+	final := history.Events[len(history.Events)-1].Type
+	for _, merge := range responses {
+		if final == enums.EventMerged {
+			assert.Equal(suite.T(), http.StatusOK, merge.Code, merge.Body.String())
+		} else {
+			assert.Equal(suite.T(), http.StatusConflict, merge.Code, merge.Body.String())
+		}
+	}
+	if final == enums.EventMerged {
+		assert.Equal(suite.T(), http.StatusConflict, closed.Code, closed.Body.String())
+	} else {
+		assert.Equal(suite.T(), http.StatusOK, closed.Code, closed.Body.String())
+	}
+}
+
+func (suite *PullRequestIntegrationTestSuite) TestReassignPR_WhenReassignedConcurrently_ShouldNotShareFreeReviewer() {
+	// Arrange
+	limit := 1
+	suite.createTeam(dto.Team{
+		TeamName: "concurrent-reassign",
+		Members: []dto.TeamMember{
+			{UserID: "cr1", Username: "Alice", IsActive: true},
+			{UserID: "cr2", Username: "Bob", IsActive: true, MaxOpenReviews: &limit},
+			{UserID: "cr3", Username: "Carol", IsActive: true, MaxOpenReviews: &limit},
+			{UserID: "cr4", Username: "Dave", IsActive: true, MaxOpenReviews: &limit},
+			{UserID: "cr5", Username: "Eve", IsActive: true, MaxOpenReviews: &limit},
+		},
+		TeamSettings: dto.TeamSettings{ReviewersPerPR: 1},
+	})
+	first := suite.createPR("pr-cr-1", "cr1")
+	second := suite.createPR("pr-cr-2", "cr1")
+
+	// Act
+	responses := suite.makeConcurrentRequests("/pullRequest/reassign", []interface{}{
+		dto.ReassignReviewer{PullRequestID: "pr-cr-1", OldUserID: first.Reviewers[0].UserID},
+		dto.ReassignReviewer{PullRequestID: "pr-cr-2", OldUserID: second.Reviewers[0].UserID},
+	})
+
+	// Assert
+	assigned := make([]string, 0, len(responses))
+	for _, response := range responses {
+		suite.Require().Equal(http.StatusOK, response.Code, response.Body.String())
+
+		var reassigned entities.ReassignedPullRequest
+		suite.Require().NoError(json.Unmarshal(response.Body.Bytes(), &reassigned))
+		assigned = append(assigned, reviewerIDs(reassigned.PR)...)
+	}
+
+	suite.Require().Len(assigned, 2)
+	assert.NotEqual(suite.T(), assigned[0], assigned[1])
+	assert.NotContains(suite.T(), assigned, "cr1")
+}
+
+func (suite *PullRequestIntegrationTestSuite) TestCreatePR_WhenTeamConfiguresReviewerCount_ShouldAssignThatMany() {
+	// Arrange
+	suite.createTeam(dto.Team{
+		TeamName: "security",
+		Members: []dto.TeamMember{
+			{UserID: "sec1", Username: "Alice", IsActive: true},
+			{UserID: "sec2", Username: "Bob", IsActive: true},
+			{UserID: "sec3", Username: "Carol", IsActive: true},
+			{UserID: "sec4", Username: "Dave", IsActive: true},
+			{UserID: "sec5", Username: "Eve", IsActive: true},
+		},
+		TeamSettings: dto.TeamSettings{ReviewersPerPR: 3},
+	})
+
+	// Act
+	pr := suite.createPR("pr-sec-1", "sec1")
+
+	// Assert
+	assert.Len(suite.T(), pr.Reviewers, 3)
+	assert.NotContains(suite.T(), reviewerIDs(pr), "sec1")
+}
+
+func (suite *PullRequestIntegrationTestSuite) TestReassignPR_WhenTeamSettingsChanged_ShouldKeepConfiguredCount() {
+	// Arrange
+	suite.createTeam(dto.Team{
+		TeamName: "platform",
+		Members: []dto.TeamMember{
+			{UserID: "pl1", Username: "Alice", IsActive: true},
+			{UserID: "pl2", Username: "Bob", IsActive: true},
+			{UserID: "pl3", Username: "Carol", IsActive: true},
+			{UserID: "pl4", Username: "Dave", IsActive: true},
+			{UserID: "pl5", Username: "Eve", IsActive: true},
+		},
+		TeamSettings: dto.TeamSettings{ReviewersPerPR: 1},
+	})
+	pr := suite.createPR("pr-pl-1", "pl1")
+	suite.Require().Len(pr.Reviewers, 1)
+
+	reviewersPerPR := 3
+	response := suite.makeAuthorizedRequest("POST", "/team/settings", adminToken, dto.UpdateTeamSettingsRequest{
+		TeamName:       "platform",
+		ReviewersPerPR: &reviewersPerPR,
+	})
+	suite.Require().Equal(http.StatusOK, response.Code, response.Body.String())
+
+	// Act
+	response = suite.makeRequest("POST", "/pullRequest/reassign", dto.ReassignReviewer{
+		PullRequestID: "pr-pl-1",
+		OldUserID:     pr.Reviewers[0].UserID,
+	})
+
+	// Assert
+	suite.Require().Equal(http.StatusOK, response.Code, response.Body.String())
+
+	var reassigned entities.ReassignedPullRequest
+	suite.Require().NoError(json.Unmarshal(response.Body.Bytes(), &reassigned))
+	assert.Len(suite.T(), reassigned.PR.Reviewers, 3)
+	assert.NotContains(suite.T(), reviewerIDs(reassigned.PR), pr.Reviewers[0].UserID)
+	assert.Contains(suite.T(), reviewerIDs(reassigned.PR), reassigned.ReplacedBY)
+}
+
+func (suite *PullRequestIntegrationTestSuite) TestCreatePR_WhenTeamTooSmall_ShouldFillFromFallbackTeam() {
+	// Arrange
+	suite.createTeam(dto.Team{
+		TeamName: "helpers",
+		Members: []dto.TeamMember{
+			{UserID: "hl1", Username: "Helen", IsActive: true},
+		},
+	})
+	suite.createTeam(dto.Team{
+		TeamName: "tiny",
+		Members: []dto.TeamMember{
+			{UserID: "tn1", Username: "Alice", IsActive: true},
+			{UserID: "tn2", Username: "Bob", IsActive: true},
+		},
+		TeamSettings: dto.TeamSettings{FallbackTeams: []string{"helpers"}},
+	})
+
+	// Act
+	pr := suite.createPR("pr-tn-1", "tn1")
+
+	// Assert
+	suite.Require().Len(pr.Reviewers, 2)
+	assert.Contains(suite.T(), pr.Reviewers, entities.Reviewer{UserID: "tn2", Username: "Bob", IsActive: true, Decision: enums.DecisionPending})
+	assert.Contains(suite.T(), pr.Reviewers, entities.Reviewer{UserID: "hl1", Username: "Helen", IsActive: true, FallbackTeam: "helpers", Decision: enums.DecisionPending})
+}
+
+func (suite *PullRequestIntegrationTestSuite) TestCreateTeam_WhenFallbackInvalid_ShouldExplainWhy() {
+	// Act
+	missing := suite.makeRequest("POST", "/team/add", dto.Team{
+		TeamName:     "lonely",
+		Members:      []dto.TeamMember{{UserID: "ln1", Username: "Alice", IsActive: true}},
+		TeamSettings: dto.TeamSettings{FallbackTeams: []string{"nobody"}},
+	})
+	self := suite.makeRequest("POST", "/team/add", dto.Team{
+		TeamName:     "selfish",
+		Members:      []dto.TeamMember{{UserID: "sf1", Username: "Bob", IsActive: true}},
+		TeamSettings: dto.TeamSettings{FallbackTeams: []string{"selfish"}},
+	})
+
+	// Assert
+	var errorResponse dto.ErrorResponse
+	suite.Require().Equal(http.StatusNotFound, missing.Code, missing.Body.String())
+	suite.Require().NoError(json.Unmarshal(missing.Body.Bytes(), &errorResponse))
+	assert.Equal(suite.T(), enums.CodeFallbackNotFound, errorResponse.Code)
+
+	suite.Require().Equal(http.StatusBadRequest, self.Code, self.Body.String())
+	suite.Require().NoError(json.Unmarshal(self.Body.Bytes(), &errorResponse))
+	assert.Equal(suite.T(), enums.CodeInvalidSettings, errorResponse.Code)
+}
+
+func (suite *PullRequestIntegrationTestSuite) TestCreatePR_WhenFilesHaveOwners_ShouldAssignOwnersFirst() {
+	// Arrange
+	suite.createTeam(dto.Team{
+		TeamName: "owned",
+		Members: []dto.TeamMember{
+			{UserID: "ow1", Username: "Alice", IsActive: true},
+			{UserID: "ow2", Username: "Bob", IsActive: true},
+			{UserID: "ow3", Username: "Carol", IsActive: true},
+			{UserID: "ow4", Username: "Dave", IsActive: true},
+		},
+		TeamSettings: dto.TeamSettings{ReviewersPerPR: 2},
+	})
+	response := suite.makeAuthorizedRequest("POST", "/team/owners/set", adminToken, dto.TeamOwnership{
+		TeamName: "owned",
+		Groups:   []dto.OwnerGroup{{Name: "db", Members: []string{"ow4"}}},
+		Rules: []dto.OwnershipRule{
+			{Pattern: "*.go", Owners: []string{"ow2"}},
+			{Pattern: "/migrations/", Owners: []string{"@db"}},
+		},
+	})
+	suite.Require().Equal(http.StatusOK, response.Code, response.Body.String())
+
+	// Act
+	response = suite.makeRequest("POST", "/pullRequest/create", dto.CreatePullRequest{
+		PullRequestID:   "pr-ow-1",
+		PullRequestName: "PR pr-ow-1",
+		AuthorID:        "ow1",
+		ChangedFiles:    []string{"internal/app/app.go", "migrations/7_new.up.sql"},
+	})
+
+	// Assert
+	suite.Require().Equal(http.StatusCreated, response.Code, response.Body.String())
+
+	var pr entities.PullRequest
+	suite.Require().NoError(json.Unmarshal(response.Body.Bytes(), &pr))
+	assert.ElementsMatch(suite.T(), []string{"ow2", "ow4"}, reviewerIDs(pr))
+}
+
+func (suite *PullRequestIntegrationTestSuite) TestReassignPR_WhenFilesHaveOwners_ShouldReplaceWithAnotherOwner() {
+	// Arrange
+	suite.createTeam(dto.Team{
+		TeamName: "owned-reassign",
+		Members: []dto.TeamMember{
+			{UserID: "or1", Username: "Alice", IsActive: true},
+			{UserID: "or2", Username: "Bob", IsActive: true},
+			{UserID: "or3", Username: "Carol", IsActive: true},
+			{UserID: "or4", Username: "Dave", IsActive: true},
+			{UserID: "or5", Username: "Eve", IsActive: true},
+		},
+		TeamSettings: dto.TeamSettings{ReviewersPerPR: 1},
+	})
+	response := suite.makeAuthorizedRequest("POST", "/team/owners/set", adminToken, dto.TeamOwnership{
+		TeamName: "owned-reassign",
+		Rules:    []dto.OwnershipRule{{Pattern: "*.go", Owners: []string{"or2", "or3"}}},
+	})
+	suite.Require().Equal(http.StatusOK, response.Code, response.Body.String())
+
+	response = suite.makeRequest("POST", "/pullRequest/create", dto.CreatePullRequest{
+		PullRequestID:   "pr-or-1",
+		PullRequestName: "PR pr-or-1",
+		AuthorID:        "or1",
+		ChangedFiles:    []string{"internal/app/app.go"},
+	})
+	suite.Require().Equal(http.StatusCreated, response.Code, response.Body.String())
+
+	var pr entities.PullRequest
+	suite.Require().NoError(json.Unmarshal(response.Body.Bytes(), &pr))
+	suite.Require().Len(pr.Reviewers, 1)
+	oldReviewer := pr.Reviewers[0].UserID
+	suite.Require().Contains([]string{"or2", "or3"}, oldReviewer)
+
+	// Act
+	response = suite.makeRequest("POST", "/pullRequest/reassign", dto.ReassignReviewer{
+		PullRequestID: "pr-or-1",
+		OldUserID:     oldReviewer,
+	})
+
+	// Assert
+	suite.Require().Equal(http.StatusOK, response.Code, response.Body.String())
+
+	var reassigned entities.ReassignedPullRequest
+	suite.Require().NoError(json.Unmarshal(response.Body.Bytes(), &reassigned))
+	otherOwner := map[string]string{"or2": "or3", "or3": "or2"}[oldReviewer]
+	assert.Equal(suite.T(), otherOwner, reassigned.ReplacedBY)
+	assert.Equal(suite.T(), []string{otherOwner}, reviewerIDs(reassigned.PR))
+}
+
+func (suite *PullRequestIntegrationTestSuite) TestSetIsActive_WhenReviewerDeactivated_ShouldReassignOpenReviews() {
+	// Arrange
+	suite.createTeam(dto.Team{
+		TeamName: "deactivation",
+		Members: []dto.TeamMember{
+			{UserID: "de1", Username: "Alice", IsActive: true},
+			{UserID: "de2", Username: "Bob", IsActive: true},
+			{UserID: "de3", Username: "Carol", IsActive: true},
+		},
+		TeamSettings: dto.TeamSettings{ReviewersPerPR: 1},
+	})
+	pr := suite.createPR("pr-de-1", "de1")
+	oldReviewer := pr.Reviewers[0].UserID
+
+	// Act
+	response := suite.makeAuthorizedRequest("POST", "/users/setIsActive", adminToken, map[string]interface{}{
+		"user_id":    oldReviewer,
+		"is_active ": false,
+	})
+
+	// Assert
+	suite.Require().Equal(http.StatusOK, response.Code, response.Body.String())
+
+	var result dto.SetUserActiveResponse
+	suite.Require().NoError(json.Unmarshal(response.Body.Bytes(), &result))
+	assert.False(suite.T(), result.IsActive)
+	suite.Require().NotNil(result.Reassignment)
+	suite.Require().Len(result.Reassignment.Reassigned, 1)
+	assert.Equal(suite.T(), "pr-de-1", result.Reassignment.Reassigned[0].PullRequestID)
+	assert.NotEqual(suite.T(), oldReviewer, result.Reassignment.Reassigned[0].ReplacedBy)
+	assert.NotEqual(suite.T(), "de1", result.Reassignment.Reassigned[0].ReplacedBy)
+	assert.Empty(suite.T(), result.Reassignment.Failed)
+}
+
+func (suite *PullRequestIntegrationTestSuite) TestDeactivateTeamUsers_WhenSeveralReviewersLeave_ShouldReassignAllTheirPRs() {
+	// Arrange
+	suite.createTeam(dto.Team{
+		TeamName: "reorg",
+		Members: []dto.TeamMember{
+			{UserID: "ro1", Username: "Alice", IsActive: true},
+			{UserID: "ro2", Username: "Bob", IsActive: true},
+			{UserID: "ro3", Username: "Carol", IsActive: true},
+			{UserID: "ro4", Username: "Dave", IsActive: true},
+			{UserID: "ro5", Username: "Eve", IsActive: true},
+		},
+		TeamSettings: dto.TeamSettings{ReviewersPerPR: 2},
+	})
+	for _, id := range []string{"pr-ro-1", "pr-ro-2", "pr-ro-3"} {
+		suite.createPR(id, "ro1")
+	}
+
+	// Act
+	response := suite.makeAuthorizedRequest("POST", "/team/deactivateUsers", adminToken, dto.DeactivateTeamUsersRequest{
+		TeamName: "reorg",
+		UserIDs:  []string{"ro2", "ro3"},
+	})
+
+	// Assert
+	suite.Require().Equal(http.StatusOK, response.Code, response.Body.String())
+
+	var result dto.DeactivateTeamUsersResponse
+	suite.Require().NoError(json.Unmarshal(response.Body.Bytes(), &result))
+	assert.ElementsMatch(suite.T(), []string{"ro2", "ro3"}, result.Deactivated)
+
+	for _, id := range []string{"pr-ro-1", "pr-ro-2", "pr-ro-3"} {
+		var reviewers []string
+		rows, err := suite.db.QueryContext(suite.ctx, `SELECT reviewer_id FROM pull_request_reviewers WHERE pr_id = $1`, id)
+		suite.Require().NoError(err)
+		for rows.Next() {
+			var reviewer string
+			suite.Require().NoError(rows.Scan(&reviewer))
+			reviewers = append(reviewers, reviewer)
+		}
+		suite.Require().NoError(rows.Close())
+
+		assert.Len(suite.T(), reviewers, 2, id)
+		assert.ElementsMatch(suite.T(), []string{"ro4", "ro5"}, reviewers, id)
+	}
+}
+
+func (suite *PullRequestIntegrationTestSuite) TestDeactivateTeamUsers_WhenUserNotInTeam_ShouldReturnBadRequest() {
+	// Arrange
+	suite.createTeam(dto.Team{
+		TeamName: "strangers",
+		Members:  []dto.TeamMember{{UserID: "st1", Username: "Alice", IsActive: true}},
+	})
+
+	// Act
+	response := suite.makeAuthorizedRequest("POST", "/team/deactivateUsers", adminToken, dto.DeactivateTeamUsersRequest{
+		TeamName: "strangers",
+		UserIDs:  []string{"nobody"},
+	})
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusBadRequest, response.Code)
+}
+
+func (suite *PullRequestIntegrationTestSuite) TestSubmitReview_WhenReviewerApproves_ShouldStoreDecision() {
+	// Arrange
+	suite.createTeam(dto.Team{
+		TeamName: "reviews",
+		Members: []dto.TeamMember{
+			{UserID: "rv1", Username: "Alice", IsActive: true},
+			{UserID: "rv2", Username: "Bob", IsActive: true},
+		},
+	})
+	pr := suite.createPR("pr-rv-1", "rv1")
+	suite.Require().Equal(enums.DecisionPending, pr.Reviewers[0].Decision)
+
+	// Act
+	response := suite.makeAuthorizedRequest("POST", "/pullRequest/review", suite.issueToken("rv2"), dto.SubmitReviewRequest{
+		PullRequestID: "pr-rv-1",
+		ReviewerID:    "rv2",
+		Decision:      enums.DecisionApproved,
+	})
+
+	// Assert
+	suite.Require().Equal(http.StatusOK, response.Code, response.Body.String())
+
+	var reviewed entities.PullRequest
+	suite.Require().NoError(json.Unmarshal(response.Body.Bytes(), &reviewed))
+	suite.Require().Len(reviewed.Reviewers, 1)
+	assert.Equal(suite.T(), enums.DecisionApproved, reviewed.Reviewers[0].Decision)
+	assert.NotNil(suite.T(), reviewed.Reviewers[0].DecidedAt)
+}
+
+func (suite *PullRequestIntegrationTestSuite) TestSubmitReview_WhenSubmittedForAnotherUser_ShouldReject() {
+	// Arrange
+	suite.createTeam(dto.Team{
+		TeamName: "impostors",
+		Members: []dto.TeamMember{
+			{UserID: "im1", Username: "Alice", IsActive: true},
+			{UserID: "im2", Username: "Bob", IsActive: true},
+		},
+		TeamSettings: dto.TeamSettings{ReviewersPerPR: 1, MergePolicy: &dto.MergePolicy{MinApprovals: 1}},
+	})
+	suite.createPR("pr-im-1", "im1")
+	review := dto.SubmitReviewRequest{
+		PullRequestID: "pr-im-1",
+		ReviewerID:    "im2",
+		Decision:      enums.DecisionApproved,
+	}
+
+	// Act
+	anonymous := suite.makeRequest("POST", "/pullRequest/review", review)
+	impostor := suite.makeAuthorizedRequest("POST", "/pullRequest/review", suite.issueToken("im1"), review)
+	merge := suite.makeRequest("POST", "/pullRequest/merge", dto.MergePullRequest{PullRequestID: "pr-im-1"})
+
+	// Assert
+	var errorResponse dto.ErrorResponse
+	suite.Require().Equal(http.StatusUnauthorized, anonymous.Code, anonymous.Body.String())
+	suite.Require().NoError(json.Unmarshal(anonymous.Body.Bytes(), &errorResponse))
+	assert.Equal(suite.T(), enums.CodeUnauthorized, errorResponse.Code)
+
+	suite.Require().Equal(http.StatusForbidden, impostor.Code, impostor.Body.String())
+	suite.Require().NoError(json.Unmarshal(impostor.Body.Bytes(), &errorResponse))
+	assert.Equal(suite.T(), enums.CodeForbidden, errorResponse.Code)
+
+	response := suite.makeRequest("GET", "/pullRequest/history?pull_request_id=pr-im-1", nil)
+	suite.Require().Equal(http.StatusOK, response.Code, response.Body.String())
+	var history dto.PullRequestHistoryResponse
+	suite.Require().NoError(json.Unmarshal(response.Body.Bytes(), &history))
+	for _, event := range history.Events {
+		assert.NotEqual(suite.T(), enums.EventReviewSubmitted, event.Type)
+	}
+	assert.Equal(suite.T(), http.StatusConflict, merge.Code, merge.Body.String())
+}
+
+func (suite *PullRequestIntegrationTestSuite) TestMergedPR_WhenChanged_ShouldExplainOperationSpecifically() {
+	// Arrange
+	suite.createTeam(dto.Team{
+		TeamName: "finished",
+		Members: []dto.TeamMember{
+			{UserID: "fn1", Username: "Alice", IsActive: true},
+			{UserID: "fn2", Username: "Bob", IsActive: true},
+		},
+	})
+	suite.createPR("pr-fn-1", "fn1")
+	response := suite.makeRequest("POST", "/pullRequest/merge", dto.MergePullRequest{PullRequestID: "pr-fn-1"})
+	suite.Require().Equal(http.StatusOK, response.Code, response.Body.String())
+
+	// Act
+	closed := suite.makeRequest("POST", "/pullRequest/close", dto.PullRequestTransition{PullRequestID: "pr-fn-1"})
+	reassigned := suite.makeRequest("POST", "/pullRequest/reassign", dto.ReassignReviewer{PullRequestID: "pr-fn-1", OldUserID: "fn2"})
+
+	// Assert
+	var errorResponse dto.ErrorResponse
+	suite.Require().Equal(http.StatusConflict, closed.Code, closed.Body.String())
+	suite.Require().NoError(json.Unmarshal(closed.Body.Bytes(), &errorResponse))
+	assert.Equal(suite.T(), enums.CodeMerged, errorResponse.Code)
+	assert.Equal(suite.T(), errs.ErrPRMerged.Error(), errorResponse.Message)
+
+	suite.Require().Equal(http.StatusConflict, reassigned.Code, reassigned.Body.String())
+	suite.Require().NoError(json.Unmarshal(reassigned.Body.Bytes(), &errorResponse))
+	assert.Equal(suite.T(), enums.CodeMerged, errorResponse.Code)
+	assert.Equal(suite.T(), errs.ErrAlreadyMerged.Error(), errorResponse.Message)
+}
+
+func (suite *PullRequestIntegrationTestSuite) TestSubmitReview_WhenUserNotAssigned_ShouldReturnNotAssigned() {
+	// Arrange
+	suite.createTeam(dto.Team{
+		TeamName: "outsiders",
+		Members: []dto.TeamMember{
+			{UserID: "os1", Username: "Alice", IsActive: true},
+			{UserID: "os2", Username: "Bob", IsActive: true},
+		},
+	})
+	suite.createPR("pr-os-1", "os1")
+
+	// Act
+	response := suite.makeAuthorizedRequest("POST", "/pullRequest/review", suite.issueToken("os1"), dto.SubmitReviewRequest{
+		PullRequestID: "pr-os-1",
+		ReviewerID:    "os1",
+		Decision:      enums.DecisionApproved,
+	})
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusConflict, response.Code)
+
+	var errorResponse dto.ErrorResponse
+	suite.Require().NoError(json.Unmarshal(response.Body.Bytes(), &errorResponse))
+	assert.Equal(suite.T(), enums.CodeNotAssigned, errorResponse.Code)
+}
+
+func (suite *PullRequestIntegrationTestSuite) TestSubmitReview_WhenCommentedAfterApproval_ShouldKeepDecisionAndTime() {
+	// Arrange
+	suite.createTeam(dto.Team{
+		TeamName: "commenters",
+		Members: []dto.TeamMember{
+			{UserID: "cm1", Username: "Alice", IsActive: true},
+			{UserID: "cm2", Username: "Bob", IsActive: true},
+		},
+	})
+	suite.createPR("pr-cm-1", "cm1")
+
+	response := suite.makeAuthorizedRequest("POST", "/pullRequest/review", suite.issueToken("cm2"), dto.SubmitReviewRequest{
+		PullRequestID: "pr-cm-1",
+		ReviewerID:    "cm2",
+		Decision:      enums.DecisionApproved,
+	})
+	suite.Require().Equal(http.StatusOK, response.Code, response.Body.String())
+
+	var approved entities.PullRequest
+	suite.Require().NoError(json.Unmarshal(response.Body.Bytes(), &approved))
+	suite.Require().NotNil(approved.Reviewers[0].DecidedAt)
+
+	// Act
+	response = suite.makeAuthorizedRequest("POST", "/pullRequest/review", suite.issueToken("cm2"), dto.SubmitReviewRequest{
+		PullRequestID: "pr-cm-1",
+		ReviewerID:    "cm2",
+		Decision:      enums.DecisionCommented,
+	})
+
+	// Assert
+	suite.Require().Equal(http.StatusOK, response.Code, response.Body.String())
+
+	var commented entities.PullRequest
+	suite.Require().NoError(json.Unmarshal(response.Body.Bytes(), &commented))
+	assert.Equal(suite.T(), enums.DecisionApproved, commented.Reviewers[0].Decision)
+	suite.Require().NotNil(commented.Reviewers[0].DecidedAt)
+	assert.True(suite.T(), approved.Reviewers[0].DecidedAt.Equal(*commented.Reviewers[0].DecidedAt))
+
+	response = suite.makeRequest("POST", "/pullRequest/close", dto.PullRequestTransition{PullRequestID: "pr-cm-1"})
+	suite.Require().Equal(http.StatusOK, response.Code, response.Body.String())
+
+	response = suite.makeAuthorizedRequest("POST", "/pullRequest/review", suite.issueToken("cm2"), dto.SubmitReviewRequest{
+		PullRequestID: "pr-cm-1",
+		ReviewerID:    "cm2",
+		Decision:      enums.DecisionApproved,
+	})
+	suite.Require().Equal(http.StatusConflict, response.Code, response.Body.String())
+
+	var errorResponse dto.ErrorResponse
+	suite.Require().NoError(json.Unmarshal(response.Body.Bytes(), &errorResponse))
+	assert.Equal(suite.T(), enums.CodeClosed, errorResponse.Code)
+}
+
+func (suite *PullRequestIntegrationTestSuite) TestMergePR_WhenApprovalsMissing_ShouldListUnmetConditions() {
+	// Arrange
+	suite.createTeam(dto.Team{
+		TeamName: "strict",
+		Members: []dto.TeamMember{
+			{UserID: "st1", Username: "Alice", IsActive: true},
+			{UserID: "st2", Username: "Bob", IsActive: true},
+		},
+		TeamSettings: dto.TeamSettings{
+			MergePolicy: &dto.MergePolicy{MinApprovals: 1, BlockOnChangesRequested: true},
+		},
+	})
+	suite.createPR("pr-st-1", "st1")
+
+	// Act
+	response := suite.makeRequest("POST", "/pullRequest/merge", dto.MergePullRequest{PullRequestID: "pr-st-1"})
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusConflict, response.Code)
+
+	var errorResponse dto.ErrorResponse
+	suite.Require().NoError(json.Unmarshal(response.Body.Bytes(), &errorResponse))
+	assert.Equal(suite.T(), enums.CodeMergeBlocked, errorResponse.Code)
+	assert.Len(suite.T(), errorResponse.Details, 1)
+}
+
+func (suite *PullRequestIntegrationTestSuite) TestMergePR_WhenAdminOverrides_ShouldMergeAndRecordOverride() {
+	// Arrange
+	suite.createTeam(dto.Team{
+		TeamName: "overrides",
+		Members: []dto.TeamMember{
+			{UserID: "ov1", Username: "Alice", IsActive: true},
+			{UserID: "ov2", Username: "Bob", IsActive: true},
+			{UserID: "ov3", Username: "Carol", IsActive: true},
+		},
+		TeamSettings: dto.TeamSettings{
+			MergePolicy: &dto.MergePolicy{MinApprovals: 2},
+		},
+	})
+	suite.createPR("pr-ov-1", "ov1")
+
+	response := suite.makeRequest("POST", "/pullRequest/merge", dto.MergePullRequest{
+		PullRequestID: "pr-ov-1",
+		Override:      true,
+	})
+	suite.Require().Equal(http.StatusUnauthorized, response.Code, response.Body.String())
+
+	response = suite.makeAuthorizedRequest("POST", "/pullRequest/merge", suite.issueToken("ov2"), dto.MergePullRequest{
+		PullRequestID: "pr-ov-1",
+		Override:      true,
+	})
+	suite.Require().Equal(http.StatusForbidden, response.Code, response.Body.String())
+
+	response = suite.makeAuthorizedRequest("POST", "/admin/users/setAdmin", adminToken, dto.SetAdminRequest{UserID: "ov3", IsAdmin: true})
+	suite.Require().Equal(http.StatusOK, response.Code, response.Body.String())
+
+	// Act
+	response = suite.makeAuthorizedRequest("POST", "/pullRequest/merge", suite.issueToken("ov3"), dto.MergePullRequest{
+		PullRequestID: "pr-ov-1",
+		Override:      true,
+		Reason:        "hotfix",
+	})
+
+	// Assert
+	suite.Require().Equal(http.StatusOK, response.Code, response.Body.String())
+
+	var merged entities.PullRequest
+	suite.Require().NoError(json.Unmarshal(response.Body.Bytes(), &merged))
+	assert.Equal(suite.T(), string(enums.PRStatusMerged), merged.Status)
+
+	var overrides int
+	err := suite.db.QueryRowContext(suite.ctx,
+		`SELECT COUNT(*) FROM merge_overrides WHERE pr_id = $1 AND actor_id = $2`, "pr-ov-1", "ov3").Scan(&overrides)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 1, overrides)
+}
+
+func (suite *PullRequestIntegrationTestSuite) TestSetAdmin_WhenCallerIsNotAdmin_ShouldRejectPromotion() {
+	// Arrange
+	suite.createTeam(dto.Team{
+		TeamName: "escalation",
+		Members: []dto.TeamMember{
+			{UserID: "es1", Username: "Alice", IsActive: true},
+		},
+	})
+	token := suite.issueToken("es1")
+
+	// Act
+	updated := suite.makeAuthorizedRequest("POST", "/users/update", token, map[string]interface{}{
+		"user_id":      "es1",
+		"is_admin":     true,
+		"github_login": "octo-admin",
+	})
+	promoted := suite.makeAuthorizedRequest("POST", "/admin/users/setAdmin", token, dto.SetAdminRequest{UserID: "es1", IsAdmin: true})
+	anonymous := suite.makeRequest("POST", "/admin/users/setAdmin", dto.SetAdminRequest{UserID: "es1", IsAdmin: true})
+	forged := suite.makeAuthorizedRequest("POST", "/admin/users/setAdmin", "forged", dto.SetAdminRequest{UserID: "es1", IsAdmin: true})
+
+	// Assert
+	var errorResponse dto.ErrorResponse
+	suite.Require().Equal(http.StatusForbidden, updated.Code, updated.Body.String())
+	suite.Require().NoError(json.Unmarshal(updated.Body.Bytes(), &errorResponse))
+	assert.Equal(suite.T(), enums.CodeForbidden, errorResponse.Code)
+
+	suite.Require().Equal(http.StatusForbidden, promoted.Code, promoted.Body.String())
+	suite.Require().NoError(json.Unmarshal(promoted.Body.Bytes(), &errorResponse))
+	assert.Equal(suite.T(), enums.CodeForbidden, errorResponse.Code)
+
+	suite.Require().Equal(http.StatusUnauthorized, anonymous.Code, anonymous.Body.String())
+	suite.Require().Equal(http.StatusUnauthorized, forged.Code, forged.Body.String())
+	suite.Require().NoError(json.Unmarshal(forged.Body.Bytes(), &errorResponse))
+	assert.Equal(suite.T(), enums.CodeUnauthorized, errorResponse.Code)
+
+	var isAdmin bool
+	err := suite.db.QueryRowContext(suite.ctx, `SELECT is_admin FROM users WHERE id = $1`, "es1").Scan(&isAdmin)
+	suite.Require().NoError(err)
+	assert.False(suite.T(), isAdmin)
+}
+
+func (suite *PullRequestIntegrationTestSuite) TestMergePR_WhenMergedTwice_ShouldKeepOriginalMergeTime() {
+	// Arrange
+	suite.createTeam(dto.Team{
+		TeamName: "idempotent",
+		Members: []dto.TeamMember{
+			{UserID: "id1", Username: "Alice", IsActive: true},
+			{UserID: "id2", Username: "Bob", IsActive: true},
+		},
+	})
+	suite.createPR("pr-id-1", "id1")
+
+	response := suite.makeRequest("POST", "/pullRequest/merge", dto.MergePullRequest{PullRequestID: "pr-id-1"})
+	suite.Require().Equal(http.StatusOK, response.Code, response.Body.String())
+
+	var first entities.PullRequest
+	suite.Require().NoError(json.Unmarshal(response.Body.Bytes(), &first))
+	suite.Require().NotNil(first.MergedAt)
+
+	// Act
+	response = suite.makeRequest("POST", "/pullRequest/merge", dto.MergePullRequest{PullRequestID: "pr-id-1"})
+
+	// Assert
+	suite.Require().Equal(http.StatusOK, response.Code, response.Body.String())
+
+	var second entities.PullRequest
+	suite.Require().NoError(json.Unmarshal(response.Body.Bytes(), &second))
+	suite.Require().NotNil(second.MergedAt)
+	assert.True(suite.T(), first.MergedAt.Equal(*second.MergedAt))
+	assert.False(suite.T(), second.CreatedAt.IsZero())
+	assert.False(suite.T(), second.MergedAt.Before(second.CreatedAt))
+}
+
+func (suite *PullRequestIntegrationTestSuite) TestCreatePR_WhenDraft_ShouldAssignReviewersOnlyWhenReady() {
+	// Arrange
+	suite.createTeam(dto.Team{
+		TeamName: "drafts",
+		Members: []dto.TeamMember{
+			{UserID: "dr1", Username: "Alice", IsActive: true},
+			{UserID: "dr2", Username: "Bob", IsActive: true},
+		},
+	})
+
+	response := suite.makeRequest("POST", "/pullRequest/create", dto.CreatePullRequest{
+		PullRequestID:   "pr-dr-1",
+		PullRequestName: "Draft",
+		AuthorID:        "dr1",
+		Draft:           true,
+	})
+	suite.Require().Equal(http.StatusCreated, response.Code, response.Body.String())
+
+	var draft entities.PullRequest
+	suite.Require().NoError(json.Unmarshal(response.Body.Bytes(), &draft))
+	suite.Require().Equal(string(enums.PRStatusDraft), draft.Status)
+	suite.Require().Empty(draft.Reviewers)
+
+	response = suite.makeRequest("POST", "/pullRequest/merge", dto.MergePullRequest{PullRequestID: "pr-dr-1"})
+	suite.Require().Equal(http.StatusConflict, response.Code, response.Body.String())
+
+	var errorResponse dto.ErrorResponse
+	suite.Require().NoError(json.Unmarshal(response.Body.Bytes(), &errorResponse))
+	suite.Require().Equal(enums.CodeDraft, errorResponse.Code)
+
+	// Act
+	response = suite.makeRequest("POST", "/pullRequest/ready", dto.PullRequestTransition{PullRequestID: "pr-dr-1"})
+
+	// Assert
+	suite.Require().Equal(http.StatusOK, response.Code, response.Body.String())
+
+	var ready entities.PullRequest
+	suite.Require().NoError(json.Unmarshal(response.Body.Bytes(), &ready))
+	assert.Equal(suite.T(), string(enums.PRStatusOpened), ready.Status)
+	assert.Equal(suite.T(), []string{"dr2"}, reviewerIDs(ready))
+}
+
+func (suite *PullRequestIntegrationTestSuite) TestReopenPR_WhenClosedAsDraft_ShouldStayDraftWithoutReviewers() {
+	// Arrange
+	suite.createTeam(dto.Team{
+		TeamName: "draft-closers",
+		Members: []dto.TeamMember{
+			{UserID: "dc1", Username: "Alice", IsActive: true},
+			{UserID: "dc2", Username: "Bob", IsActive: true},
+		},
+	})
+	response := suite.makeRequest("POST", "/pullRequest/create", dto.CreatePullRequest{
+		PullRequestID:   "pr-dc-1",
+		PullRequestName: "Draft",
+		AuthorID:        "dc1",
+		Draft:           true,
+	})
+	suite.Require().Equal(http.StatusCreated, response.Code, response.Body.String())
+
+	response = suite.makeRequest("POST", "/pullRequest/close", dto.PullRequestTransition{PullRequestID: "pr-dc-1"})
+	suite.Require().Equal(http.StatusOK, response.Code, response.Body.String())
+
+	// Act
+	response = suite.makeRequest("POST", "/pullRequest/reopen", dto.PullRequestTransition{PullRequestID: "pr-dc-1"})
+
+	// Assert
+	suite.Require().Equal(http.StatusOK, response.Code, response.Body.String())
+
+	var reopened entities.PullRequest
+	suite.Require().NoError(json.Unmarshal(response.Body.Bytes(), &reopened))
+	assert.Equal(suite.T(), string(enums.PRStatusDraft), reopened.Status)
+	assert.Empty(suite.T(), reopened.Reviewers)
+	assert.Nil(suite.T(), reopened.ClosedAt)
+
+	response = suite.makeRequest("POST", "/pullRequest/ready", dto.PullRequestTransition{PullRequestID: "pr-dc-1"})
+	suite.Require().Equal(http.StatusOK, response.Code, response.Body.String())
+
+	var ready entities.PullRequest
+	suite.Require().NoError(json.Unmarshal(response.Body.Bytes(), &ready))
+	assert.Equal(suite.T(), string(enums.PRStatusOpened), ready.Status)
+	assert.Equal(suite.T(), []string{"dc2"}, reviewerIDs(ready))
+}
+
+func (suite *PullRequestIntegrationTestSuite) TestClosePR_WhenClosed_ShouldRejectReassignUntilReopened() {
+	// Arrange
+	suite.createTeam(dto.Team{
+		TeamName: "closers",
+		Members: []dto.TeamMember{
+			{UserID: "cl1", Username: "Alice", IsActive: true},
+			{UserID: "cl2", Username: "Bob", IsActive: true},
+			{UserID: "cl3", Username: "Carol", IsActive: true},
+		},
+		TeamSettings: dto.TeamSettings{ReviewersPerPR: 1},
+	})
+	pr := suite.createPR("pr-cl-1", "cl1")
+	suite.Require().Len(pr.Reviewers, 1)
+
+	response := suite.makeRequest("POST", "/pullRequest/close", dto.PullRequestTransition{PullRequestID: "pr-cl-1"})
+	suite.Require().Equal(http.StatusOK, response.Code, response.Body.String())
+
+	var closed entities.PullRequest
+	suite.Require().NoError(json.Unmarshal(response.Body.Bytes(), &closed))
+	suite.Require().Equal(string(enums.PRStatusClosed), closed.Status)
+	suite.Require().NotNil(closed.ClosedAt)
+
+	// Act
+	response = suite.makeRequest("POST", "/pullRequest/reassign", dto.ReassignReviewer{
+		PullRequestID: "pr-cl-1",
+		OldUserID:     pr.Reviewers[0].UserID,
+	})
+
+	// Assert
+	suite.Require().Equal(http.StatusConflict, response.Code, response.Body.String())
+
+	var errorResponse dto.ErrorResponse
+	suite.Require().NoError(json.Unmarshal(response.Body.Bytes(), &errorResponse))
+	assert.Equal(suite.T(), enums.CodeClosed, errorResponse.Code)
+
+	response = suite.makeRequest("POST", "/pullRequest/reopen", dto.PullRequestTransition{PullRequestID: "pr-cl-1"})
+	suite.Require().Equal(http.StatusOK, response.Code, response.Body.String())
+
+	var reopened entities.PullRequest
+	suite.Require().NoError(json.Unmarshal(response.Body.Bytes(), &reopened))
+	assert.Equal(suite.T(), string(enums.PRStatusOpened), reopened.Status)
+	assert.Equal(suite.T(), reviewerIDs(pr), reviewerIDs(reopened))
+	assert.Nil(suite.T(), reopened.ClosedAt)
+}
+
+func (suite *PullRequestIntegrationTestSuite) TestGetHistory_WhenActorInBody_ShouldRecordAuthenticatedCaller() {
+	// Arrange
+	suite.createTeam(dto.Team{
+		TeamName: "spoofing",
+		Members: []dto.TeamMember{
+			{UserID: "sp1", Username: "Alice", IsActive: true},
+			{UserID: "sp2", Username: "Bob", IsActive: true},
+		},
+	})
+	suite.createPR("pr-sp-1", "sp1")
+
+	// Act
+	closed := suite.makeRequest("POST", "/pullRequest/close", map[string]interface{}{
+		"pull_request_id": "pr-sp-1",
+		"actor_id":        "sp2",
+	})
+	reopened := suite.makeAuthorizedRequest("POST", "/pullRequest/reopen", suite.issueToken("sp1"), map[string]interface{}{
+		"pull_request_id": "pr-sp-1",
+		"actor_id":        "sp2",
+	})
+
+	// Assert
+	suite.Require().Equal(http.StatusOK, closed.Code, closed.Body.String())
+	suite.Require().Equal(http.StatusOK, reopened.Code, reopened.Body.String())
+
+	response := suite.makeRequest("GET", "/pullRequest/history?pull_request_id=pr-sp-1", nil)
+	suite.Require().Equal(http.StatusOK, response.Code, response.Body.String())
+
+	var history dto.PullRequestHistoryResponse
+	suite.Require().NoError(json.Unmarshal(response.Body.Bytes(), &history))
+
+	actors := make(map[enums.PREventType]string)
+	for _, event := range history.Events {
+		actors[event.Type] = event.ActorID
+	}
+	assert.Equal(suite.T(), "", actors[enums.EventClosed])
+	assert.Equal(suite.T(), "sp1", actors[enums.EventReopened])
+}
+
+func (suite *PullRequestIntegrationTestSuite) TestGetHistory_WhenReviewerReplacedAndMerged_ShouldListEventsInOrder() {
+	// Arrange
+	suite.createTeam(dto.Team{
+		TeamName: "history",
+		Members: []dto.TeamMember{
+			{UserID: "hs1", Username: "Alice", IsActive: true},
+			{UserID: "hs2", Username: "Bob", IsActive: true},
+			{UserID: "hs3", Username: "Carol", IsActive: true},
+		},
+		TeamSettings: dto.TeamSettings{ReviewersPerPR: 1},
+	})
+	pr := suite.createPR("pr-hs-1", "hs1")
+	oldReviewer := pr.Reviewers[0].UserID
+
+	token := suite.issueToken("hs1")
+	response := suite.makeAuthorizedRequest("POST", "/pullRequest/reassign", token, dto.ReassignReviewer{
+		PullRequestID: "pr-hs-1",
+		OldUserID:     oldReviewer,
+	})
+	suite.Require().Equal(http.StatusOK, response.Code, response.Body.String())
+
+	response = suite.makeAuthorizedRequest("POST", "/pullRequest/merge", token, dto.MergePullRequest{PullRequestID: "pr-hs-1"})
+	suite.Require().Equal(http.StatusOK, response.Code, response.Body.String())
+
+	// Act
+	response = suite.makeRequest("GET", "/pullRequest/history?pull_request_id=pr-hs-1", nil)
+
+	// Assert
+	suite.Require().Equal(http.StatusOK, response.Code, response.Body.String())
+
+	var history dto.PullRequestHistoryResponse
+	suite.Require().NoError(json.Unmarshal(response.Body.Bytes(), &history))
+
+	types := make([]enums.PREventType, len(history.Events))
+	for i, event := range history.Events {
+		types[i] = event.Type
+		assert.Equal(suite.T(), "hs1", event.ActorID)
+	}
+	assert.Equal(suite.T(), []enums.PREventType{
+		enums.EventCreated,
+		enums.EventReviewerAssigned,
+		enums.EventReviewerReplaced,
+		enums.EventMerged,
+	}, types)
+	assert.Equal(suite.T(), oldReviewer, history.Events[2].OldReviewerID)
+	assert.NotEqual(suite.T(), oldReviewer, history.Events[2].ReviewerID)
+}
+
+func (suite *PullRequestIntegrationTestSuite) TestReassignPR_WhenReplacementRequested_ShouldUseRequestedUser() {
+	// Arrange
+	suite.createTeam(dto.Team{
+		TeamName: "explicit",
+		Members: []dto.TeamMember{
+			{UserID: "ex1", Username: "Alice", IsActive: true},
+			{UserID: "ex2", Username: "Bob", IsActive: true},
+			{UserID: "ex3", Username: "Carol", IsActive: true},
+			{UserID: "ex4", Username: "Dave", IsActive: false},
+		},
+		TeamSettings: dto.TeamSettings{ReviewersPerPR: 1},
+	})
+	pr := suite.createPR("pr-ex-1", "ex1")
+	oldReviewer := pr.Reviewers[0].UserID
+	newReviewer := "ex2"
+	if oldReviewer == newReviewer {
+		newReviewer = "ex3"
+	}
+
+	response := suite.makeRequest("POST", "/pullRequest/reassign", dto.ReassignReviewer{
+		PullRequestID: "pr-ex-1",
+		OldUserID:     oldReviewer,
+		NewUserID:     "ex4",
+	})
+	suite.Require().Equal(http.StatusBadRequest, response.Code, response.Body.String())
+
+	var errorResponse dto.ErrorResponse
+	suite.Require().NoError(json.Unmarshal(response.Body.Bytes(), &errorResponse))
+	suite.Require().Equal(enums.CodeInvalidReviewer, errorResponse.Code)
+
+	// Act
+	response = suite.makeRequest("POST", "/pullRequest/reassign", dto.ReassignReviewer{
+		PullRequestID: "pr-ex-1",
+		OldUserID:     oldReviewer,
+		NewUserID:     newReviewer,
+	})
+
+	// Assert
+	suite.Require().Equal(http.StatusOK, response.Code, response.Body.String())
+
+	var reassigned entities.ReassignedPullRequest
+	suite.Require().NoError(json.Unmarshal(response.Body.Bytes(), &reassigned))
+	assert.Equal(suite.T(), newReviewer, reassigned.ReplacedBY)
+	assert.Equal(suite.T(), []string{newReviewer}, reviewerIDs(reassigned.PR))
+}
+
+func (suite *PullRequestIntegrationTestSuite) TestMoveMember_WhenMemberReviewsFormerTeamPR_ShouldReassignIt() {
+	// Arrange
+	suite.createTeam(dto.Team{
+		TeamName: "movers",
+		Members: []dto.TeamMember{
+			{UserID: "mv1", Username: "Alice", IsActive: true},
+			{UserID: "mv2", Username: "Bob", IsActive: true},
+		},
+		TeamSettings: dto.TeamSettings{ReviewersPerPR: 1},
+	})
+	suite.createTeam(dto.Team{
+		TeamName: "destination",
+		Members: []dto.TeamMember{
+			{UserID: "ds1", Username: "Dave", IsActive: true},
+		},
+	})
+	pr := suite.createPR("pr-mv-1", "mv1")
+	suite.Require().Equal([]string{"mv2"}, reviewerIDs(pr))
+
+	response := suite.makeAuthorizedRequest("POST", "/team/members/add", adminToken, dto.AddTeamMembersRequest{
+		TeamName: "movers",
+		Members:  []dto.TeamMember{{UserID: "mv3", Username: "Carol", IsActive: true}},
+	})
+	suite.Require().Equal(http.StatusOK, response.Code, response.Body.String())
+
+	// Act
+	response = suite.makeAuthorizedRequest("POST", "/team/members/move", adminToken, dto.MoveTeamMemberRequest{
+		FromTeam: "movers",
+		ToTeam:   "destination",
+		UserID:   "mv2",
+	})
+
+	// Assert
+	suite.Require().Equal(http.StatusOK, response.Code, response.Body.String())
+
+	var moved dto.TeamMemberChangeResponse
+	suite.Require().NoError(json.Unmarshal(response.Body.Bytes(), &moved))
+	suite.Require().Len(moved.PullRequests, 1)
+	suite.Require().Len(moved.PullRequests[0].Reassigned, 1)
+	assert.Equal(suite.T(), "mv3", moved.PullRequests[0].Reassigned[0].ReplacedBy)
+
+	response = suite.makeRequest("GET", "/team/get?TeamName=destination", nil)
+	suite.Require().Equal(http.StatusOK, response.Code, response.Body.String())
+
+	var destination dto.Team
+	suite.Require().NoError(json.Unmarshal(response.Body.Bytes(), &destination))
+	assert.Len(suite.T(), destination.Members, 2)
+}
+
+func (suite *PullRequestIntegrationTestSuite) TestRemoveMember_WhenTeamIsUsersOnlyTeam_ShouldRejectRemoval() {
+	// Arrange
+	suite.createTeam(dto.Team{
+		TeamName: "leavers",
+		Members: []dto.TeamMember{
+			{UserID: "lv1", Username: "Alice", IsActive: true},
+			{UserID: "lv2", Username: "Bob", IsActive: true},
+		},
+	})
+	suite.createTeam(dto.Team{
+		TeamName: "second-home",
+		Members: []dto.TeamMember{
+			{UserID: "sh1", Username: "Dave", IsActive: true},
+		},
+	})
+
+	response := suite.makeAuthorizedRequest("POST", "/team/members/add", adminToken, dto.AddTeamMembersRequest{
+		TeamName: "second-home",
+		Members:  []dto.TeamMember{{UserID: "lv1", Username: "Renamed", IsActive: true}},
+	})
+	suite.Require().Equal(http.StatusOK, response.Code, response.Body.String())
+
+	var secondHome dto.Team
+	suite.Require().NoError(json.Unmarshal(response.Body.Bytes(), &secondHome))
+	for _, member := range secondHome.Members {
+		if member.UserID == "lv1" {
+			assert.Equal(suite.T(), "Alice", member.Username)
+		}
+	}
+
+	// Act
+	response = suite.makeAuthorizedRequest("POST", "/team/members/remove", adminToken, dto.RemoveTeamMemberRequest{TeamName: "leavers", UserID: "lv2"})
+
+	// Assert
+	suite.Require().Equal(http.StatusConflict, response.Code, response.Body.String())
+
+	var errorResponse dto.ErrorResponse
+	suite.Require().NoError(json.Unmarshal(response.Body.Bytes(), &errorResponse))
+	assert.Equal(suite.T(), enums.CodeLastTeam, errorResponse.Code)
+
+	response = suite.makeAuthorizedRequest("POST", "/team/members/remove", adminToken, dto.RemoveTeamMemberRequest{TeamName: "leavers", UserID: "lv1"})
+	suite.Require().Equal(http.StatusOK, response.Code, response.Body.String())
+
+	pr := suite.createPR("pr-lv-1", "lv1")
+	assert.Equal(suite.T(), "second-home", pr.TeamName)
+}
+
+func (suite *PullRequestIntegrationTestSuite) TestCreatePR_WhenAuthorChoosesSecondaryTeam_ShouldUseItsReviewers() {
+	// Arrange
+	suite.createTeam(dto.Team{
+		TeamName: "primary",
+		Members: []dto.TeamMember{
+			{UserID: "mt1", Username: "Alice", IsActive: true},
+			{UserID: "mt2", Username: "Bob", IsActive: true},
+		},
+	})
+	suite.createTeam(dto.Team{
+		TeamName: "secondary",
+		Members: []dto.TeamMember{
+			{UserID: "mt1", Username: "Alice", IsActive: true},
+			{UserID: "mt3", Username: "Carol", IsActive: true},
+		},
+	})
+
+	// Act
+	byDefault := suite.createPR("pr-mt-1", "mt1")
+	response := suite.makeRequest("POST", "/pullRequest/create", dto.CreatePullRequest{
+		PullRequestID:   "pr-mt-2",
+		PullRequestName: "Secondary",
+		AuthorID:        "mt1",
+		TeamName:        "secondary",
+	})
+
+	// Assert
+	suite.Require().Equal(http.StatusCreated, response.Code, response.Body.String())
+
+	var chosen entities.PullRequest
+	suite.Require().NoError(json.Unmarshal(response.Body.Bytes(), &chosen))
+	assert.Equal(suite.T(), "primary", byDefault.TeamName)
+	assert.Equal(suite.T(), []string{"mt2"}, reviewerIDs(byDefault))
+	assert.Equal(suite.T(), "secondary", chosen.TeamName)
+	assert.Equal(suite.T(), []string{"mt3"}, reviewerIDs(chosen))
+
+	response = suite.makeRequest("POST", "/pullRequest/create", dto.CreatePullRequest{
+		PullRequestID:   "pr-mt-3",
+		PullRequestName: "Foreign",
+		AuthorID:        "mt2",
+		TeamName:        "secondary",
+	})
+	assert.Equal(suite.T(), http.StatusBadRequest, response.Code)
+}
+
+func (suite *PullRequestIntegrationTestSuite) TestSetPrimaryTeam_WhenUserOrMembershipMissing_ShouldTellThemApart() {
+	// Arrange
+	suite.createTeam(dto.Team{
+		TeamName: "primaries",
+		Members: []dto.TeamMember{
+			{UserID: "pm1", Username: "Alice", IsActive: true},
+		},
+	})
+	suite.createTeam(dto.Team{
+		TeamName: "strangers",
+		Members: []dto.TeamMember{
+			{UserID: "pm2", Username: "Bob", IsActive: true},
+		},
+	})
+
+	// Act
+	missingUser := suite.makeAuthorizedRequest("POST", "/users/setPrimaryTeam", adminToken, dto.SetPrimaryTeamRequest{UserID: "pm-missing", TeamName: "primaries"})
+	notMember := suite.makeAuthorizedRequest("POST", "/users/setPrimaryTeam", adminToken, dto.SetPrimaryTeamRequest{UserID: "pm1", TeamName: "strangers"})
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusNotFound, missingUser.Code, missingUser.Body.String())
+	assert.Equal(suite.T(), http.StatusBadRequest, notMember.Code, notMember.Body.String())
+}
+
+func (suite *PullRequestIntegrationTestSuite) TestTeamLifecycle_WhenRenamedArchivedAndDeleted_ShouldKeepHistory() {
+	// Arrange
+	suite.createTeam(dto.Team{
+		TeamName: "typo-teem",
+		Members: []dto.TeamMember{
+			{UserID: "lc1", Username: "Alice", IsActive: true},
+			{UserID: "lc2", Username: "Bob", IsActive: true},
+		},
+	})
+	suite.createPR("pr-lc-1", "lc1")
+
+	// Act
+	response := suite.makeAuthorizedRequest("POST", "/team/rename", adminToken, dto.RenameTeamRequest{TeamName: "typo-teem", NewName: "typo-team"})
+	suite.Require().Equal(http.StatusOK, response.Code, response.Body.String())
+
+	response = suite.makeAuthorizedRequest("POST", "/team/archive", adminToken, dto.ArchiveTeamRequest{TeamName: "typo-team"})
+	suite.Require().Equal(http.StatusOK, response.Code, response.Body.String())
+
+	response = suite.makeRequest("POST", "/pullRequest/create", dto.CreatePullRequest{
+		PullRequestID:   "pr-lc-2",
+		PullRequestName: "Archived",
+		AuthorID:        "lc1",
+	})
+	suite.Require().Equal(http.StatusConflict, response.Code, response.Body.String())
+
+	response = suite.makeAuthorizedRequest("POST", "/team/delete", adminToken, dto.DeleteTeamRequest{TeamName: "typo-team"})
+	suite.Require().Equal(http.StatusConflict, response.Code, response.Body.String())
+
+	response = suite.makeAuthorizedRequest("POST", "/team/delete", adminToken, dto.DeleteTeamRequest{TeamName: "typo-team", Force: true})
+	suite.Require().Equal(http.StatusNoContent, response.Code, response.Body.String())
+
+	// Assert
+	response = suite.makeRequest("GET", "/team/history?team_name=typo-teem", nil)
+	suite.Require().Equal(http.StatusOK, response.Code, response.Body.String())
+
+	var history dto.TeamHistoryResponse
+	suite.Require().NoError(json.Unmarshal(response.Body.Bytes(), &history))
+
+	types := make([]enums.TeamEventType, len(history.Events))
+	for i, event := range history.Events {
+		types[i] = event.Type
+	}
+	assert.Equal(suite.T(), []enums.TeamEventType{
+		enums.TeamEventCreated,
+		enums.TeamEventRenamed,
+		enums.TeamEventArchived,
+		enums.TeamEventDeleted,
+	}, types)
+	assert.Equal(suite.T(), "typo-teem", history.Events[1].OldTeamName)
+}
+
+func (suite *PullRequestIntegrationTestSuite) TestTeamLifecycle_WhenCallerIsNotAdmin_ShouldReject() {
+	// Arrange
+	suite.createTeam(dto.Team{
+		TeamName: "guarded",
+		Members:  []dto.TeamMember{{UserID: "gd1", Username: "Alice", IsActive: true}},
+	})
+	memberToken := suite.issueToken("gd1")
+
+	// Act
+	anonymousRename := suite.makeRequest("POST", "/team/rename", dto.RenameTeamRequest{TeamName: "guarded", NewName: "hijacked"})
+	memberRename := suite.makeAuthorizedRequest("POST", "/team/rename", memberToken, dto.RenameTeamRequest{TeamName: "guarded", NewName: "hijacked"})
+	memberArchive := suite.makeAuthorizedRequest("POST", "/team/archive", memberToken, dto.ArchiveTeamRequest{TeamName: "guarded"})
+	memberDelete := suite.makeAuthorizedRequest("POST", "/team/delete", memberToken, dto.DeleteTeamRequest{TeamName: "guarded", Force: true})
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusUnauthorized, anonymousRename.Code, anonymousRename.Body.String())
+	assert.Equal(suite.T(), http.StatusForbidden, memberRename.Code, memberRename.Body.String())
+	assert.Equal(suite.T(), http.StatusForbidden, memberArchive.Code, memberArchive.Body.String())
+	assert.Equal(suite.T(), http.StatusForbidden, memberDelete.Code, memberDelete.Body.String())
+
+	response := suite.makeRequest("GET", "/team/get?team_name=guarded", nil)
+	suite.Require().Equal(http.StatusOK, response.Code, response.Body.String())
+	var team dto.Team
+	suite.Require().NoError(json.Unmarshal(response.Body.Bytes(), &team))
+	assert.Nil(suite.T(), team.ArchivedAt)
+}
+
+func (suite *PullRequestIntegrationTestSuite) TestDeleteTeam_WhenForcedWithOpenPRs_ShouldCloseThem() {
+	// Arrange
+	suite.createTeam(dto.Team{
+		TeamName: "doomed",
+		Members: []dto.TeamMember{
+			{UserID: "dm1", Username: "Alice", IsActive: true},
+			{UserID: "dm2", Username: "Bob", IsActive: true},
+		},
+		TeamSettings: dto.TeamSettings{ReviewersPerPR: 1},
+	})
+	suite.createPR("pr-dm-1", "dm1")
+
+	// Act
+	response := suite.makeAuthorizedRequest("POST", "/team/delete", adminToken, dto.DeleteTeamRequest{TeamName: "doomed", Force: true})
+	suite.Require().Equal(http.StatusNoContent, response.Code, response.Body.String())
+
+	deactivated := suite.makeAuthorizedRequest("POST", "/users/setIsActive", adminToken, map[string]interface{}{
+		"user_id":    "dm2",
+		"is_active ": false,
+	})
+
+	// Assert
+	response = suite.makeRequest("GET", "/pullRequest/list?author_id=dm1", nil)
+	suite.Require().Equal(http.StatusOK, response.Code, response.Body.String())
+	var list dto.ListPullRequestsResponse
+	suite.Require().NoError(json.Unmarshal(response.Body.Bytes(), &list))
+	suite.Require().Len(list.PullRequests, 1)
+	assert.Equal(suite.T(), string(enums.PRStatusClosed), list.PullRequests[0].Status)
+
+	response = suite.makeRequest("GET", "/pullRequest/history?pull_request_id=pr-dm-1", nil)
+	suite.Require().Equal(http.StatusOK, response.Code, response.Body.String())
+	var history dto.PullRequestHistoryResponse
+	suite.Require().NoError(json.Unmarshal(response.Body.Bytes(), &history))
+	suite.Require().NotEmpty(history.Events)
+	assert.Equal(suite.T(), enums.EventClosed, history.Events[len(history.Events)-1].Type)
+
+	suite.Require().Equal(http.StatusOK, deactivated.Code, deactivated.Body.String())
+}
+
+func (suite *PullRequestIntegrationTestSuite) TestSetIsActive_WhenOpenPRHasNoTeam_ShouldReportItAndReassignTheRest() {
+	// Arrange
+	suite.createTeam(dto.Team{
+		TeamName: "orphans",
+		Members: []dto.TeamMember{
+			{UserID: "op1", Username: "Alice", IsActive: true},
+			{UserID: "op2", Username: "Bob", IsActive: true},
+			{UserID: "op3", Username: "Carol", IsActive: true},
+			{UserID: "op4", Username: "Dave", IsActive: true},
+		},
+		TeamSettings: dto.TeamSettings{ReviewersPerPR: 2, ReviewerStrategy: enums.StrategyRoundRobin},
+	})
+	orphan := suite.createPR("pr-op-1", "op1")
+	suite.Require().ElementsMatch([]string{"op2", "op3"}, reviewerIDs(orphan))
+	// Так выглядят pr, отвязанные от команды до того, как удаление стало закрывать их.
+	_, err := suite.db.ExecContext(suite.ctx, `UPDATE pull_requests SET team_id = NULL WHERE id = $1`, "pr-op-1")
+	suite.Require().NoError(err)
+
+	pr := suite.createPR("pr-op-2", "op1")
+	suite.Require().Contains(reviewerIDs(pr), "op2")
+
+	// Act
+	response := suite.makeAuthorizedRequest("POST", "/users/setIsActive", adminToken, map[string]interface{}{
+		"user_id":    "op2",
+		"is_active ": false,
+	})
+
+	// Assert
+	suite.Require().Equal(http.StatusOK, response.Code, response.Body.String())
+
+	var result dto.SetUserActiveResponse
+	suite.Require().NoError(json.Unmarshal(response.Body.Bytes(), &result))
+	suite.Require().NotNil(result.Reassignment)
+	suite.Require().Len(result.Reassignment.Failed, 1)
+	assert.Equal(suite.T(), "pr-op-1", result.Reassignment.Failed[0].PullRequestID)
+	assert.Equal(suite.T(), errs.ErrPRWithoutTeam.Error(), result.Reassignment.Failed[0].Reason)
+	suite.Require().Len(result.Reassignment.Reassigned, 1)
+	assert.Equal(suite.T(), "pr-op-2", result.Reassignment.Reassigned[0].PullRequestID)
+	assert.Equal(suite.T(), "op3", result.Reassignment.Reassigned[0].ReplacedBy)
+}
+
+func (suite *PullRequestIntegrationTestSuite) TestTeamFallbacks_WhenFallbackArchived_ShouldIgnoreIt() {
+	// Arrange
+	suite.createTeam(dto.Team{
+		TeamName: "retired-helpers",
+		Members:  []dto.TeamMember{{UserID: "rh1", Username: "Helen", IsActive: true}},
+	})
+	suite.createTeam(dto.Team{
+		TeamName: "needs-help",
+		Members: []dto.TeamMember{
+			{UserID: "nh1", Username: "Alice", IsActive: true},
+			{UserID: "nh2", Username: "Bob", IsActive: true},
+		},
+		TeamSettings: dto.TeamSettings{FallbackTeams: []string{"retired-helpers"}},
+	})
+	response := suite.makeAuthorizedRequest("POST", "/team/archive", adminToken, dto.ArchiveTeamRequest{TeamName: "retired-helpers"})
+	suite.Require().Equal(http.StatusOK, response.Code, response.Body.String())
+
+	// Act
+	pr := suite.createPR("pr-nh-1", "nh1")
+	created := suite.makeRequest("POST", "/team/add", dto.Team{
+		TeamName:     "late-joiner",
+		Members:      []dto.TeamMember{{UserID: "lj1", Username: "Dave", IsActive: true}},
+		TeamSettings: dto.TeamSettings{FallbackTeams: []string{"retired-helpers"}},
+	})
+
+	// Assert
+	assert.Equal(suite.T(), []string{"nh2"}, reviewerIDs(pr))
+
+	var errorResponse dto.ErrorResponse
+	suite.Require().Equal(http.StatusNotFound, created.Code, created.Body.String())
+	suite.Require().NoError(json.Unmarshal(created.Body.Bytes(), &errorResponse))
+	assert.Equal(suite.T(), enums.CodeFallbackNotFound, errorResponse.Code)
+}
+
+func (suite *PullRequestIntegrationTestSuite) TestListPullRequests_WhenPaginated_ShouldReturnEveryPROnce() {
+	// Arrange
+	suite.createTeam(dto.Team{
+		TeamName: "listing",
+		Members: []dto.TeamMember{
+			{UserID: "ls1", Username: "Alice", IsActive: true},
+			{UserID: "ls2", Username: "Bob", IsActive: true},
+		},
+	})
+	for _, id := range []string{"pr-ls-1", "pr-ls-2", "pr-ls-3"} {
+		suite.createPR(id, "ls1")
+	}
+	suite.createPR("pr-ls-4", "ls2")
+
+	// Act
+	var seen []string
+	cursor := ""
+	for page := 0; page < 5; page++ {
+		response := suite.makeRequest("GET", "/pullRequest/list?author_id=ls1&limit=2&cursor="+cursor, nil)
+		suite.Require().Equal(http.StatusOK, response.Code, response.Body.String())
+
+		var list dto.ListPullRequestsResponse
+		suite.Require().NoError(json.Unmarshal(response.Body.Bytes(), &list))
+		for _, pr := range list.PullRequests {
+			seen = append(seen, pr.PullRequestID)
+		}
+		if list.NextCursor == "" {
+			break
+		}
+		cursor = list.NextCursor
+	}
+
+	// Assert
+	assert.ElementsMatch(suite.T(), []string{"pr-ls-1", "pr-ls-2", "pr-ls-3"}, seen)
+	assert.Len(suite.T(), seen, 3)
+
+	response := suite.makeRequest("GET", "/pullRequest/list?reviewer_id=ls1", nil)
+	suite.Require().Equal(http.StatusOK, response.Code, response.Body.String())
+	var reviewed dto.ListPullRequestsResponse
+	suite.Require().NoError(json.Unmarshal(response.Body.Bytes(), &reviewed))
+	suite.Require().Len(reviewed.PullRequests, 1)
+	assert.Equal(suite.T(), "pr-ls-4", reviewed.PullRequests[0].PullRequestID)
+
+	response = suite.makeRequest("GET", "/pullRequest/list?cursor=broken!", nil)
+	assert.Equal(suite.T(), http.StatusBadRequest, response.Code)
+}
+
+func (suite *PullRequestIntegrationTestSuite) TestListPullRequests_WhenCursorReusedWithOtherFilters_ShouldReturnBadRequest() {
+	// Arrange
+	suite.createTeam(dto.Team{
+		TeamName: "cursors",
+		Members: []dto.TeamMember{
+			{UserID: "cr1", Username: "Alice", IsActive: true},
+			{UserID: "cr2", Username: "Bob", IsActive: true},
+		},
+	})
+	suite.createPR("pr-cr-1", "cr1")
+	suite.createPR("pr-cr-2", "cr1")
+
+	response := suite.makeRequest("GET", "/pullRequest/list?author_id=cr1&limit=1", nil)
+	suite.Require().Equal(http.StatusOK, response.Code, response.Body.String())
+
+	var list dto.ListPullRequestsResponse
+	suite.Require().NoError(json.Unmarshal(response.Body.Bytes(), &list))
+	suite.Require().NotEmpty(list.NextCursor)
+
+	// Act
+	otherFilter := suite.makeRequest("GET", "/pullRequest/list?author_id=cr2&limit=1&cursor="+list.NextCursor, nil)
+	otherList := suite.makeRequest("GET", "/users/list?limit=1&cursor="+list.NextCursor, nil)
+	otherLimit := suite.makeRequest("GET", "/pullRequest/list?author_id=cr1&limit=5&cursor="+list.NextCursor, nil)
+
+	// Assert
+	for _, response := range []*httptest.ResponseRecorder{otherFilter, otherList} {
+		suite.Require().Equal(http.StatusBadRequest, response.Code, response.Body.String())
+
+		var errorResponse dto.ErrorResponse
+		suite.Require().NoError(json.Unmarshal(response.Body.Bytes(), &errorResponse))
+		assert.Equal(suite.T(), enums.CodeInvalidCursor, errorResponse.Code)
+	}
+	assert.Equal(suite.T(), http.StatusOK, otherLimit.Code, otherLimit.Body.String())
+}
+
+func (suite *PullRequestIntegrationTestSuite) TestListTeamsAndUsers_WhenFilteredByName_ShouldHideArchivedTeams() {
+	// Arrange
+	suite.createTeam(dto.Team{
+		TeamName: "search-backend",
+		Members:  []dto.TeamMember{{UserID: "sr1", Username: "Alice", IsActive: true}},
+	})
+	suite.createTeam(dto.Team{
+		TeamName: "search-frontend",
+		Members:  []dto.TeamMember{{UserID: "sr2", Username: "Alina", IsActive: true}},
+	})
+	suite.createTeam(dto.Team{
+		TeamName: "search-legacy",
+		Members:  []dto.TeamMember{{UserID: "sr3", Username: "Bob", IsActive: true}},
+	})
+	response := suite.makeAuthorizedRequest("POST", "/team/archive", adminToken, dto.ArchiveTeamRequest{TeamName: "search-legacy"})
+	suite.Require().Equal(http.StatusOK, response.Code, response.Body.String())
+
+	// Act
+	response = suite.makeRequest("GET", "/team/list?name=search", nil)
+	suite.Require().Equal(http.StatusOK, response.Code, response.Body.String())
+	var teams dto.ListTeamsResponse
+	suite.Require().NoError(json.Unmarshal(response.Body.Bytes(), &teams))
+
+	response = suite.makeRequest("GET", "/users/list?name=ali", nil)
+	suite.Require().Equal(http.StatusOK, response.Code, response.Body.String())
+	var users dto.ListUsersResponse
+	suite.Require().NoError(json.Unmarshal(response.Body.Bytes(), &users))
+
+	// Assert
+	teamNames := make([]string, len(teams.Teams))
+	for i, team := range teams.Teams {
+		teamNames[i] = team.TeamName
+	}
+	assert.Equal(suite.T(), []string{"search-backend", "search-frontend"}, teamNames)
+	assert.Empty(suite.T(), teams.NextCursor)
+
+	userIDs := make([]string, len(users.Users))
+	for i, user := range users.Users {
+		userIDs[i] = user.UserID
+	}
+	assert.Equal(suite.T(), []string{"sr1", "sr2"}, userIDs)
+	assert.Equal(suite.T(), "search-backend", users.Users[0].TeamName)
+}
+
+func (suite *PullRequestIntegrationTestSuite) TestGetReview_WhenFilteredByStatus_ShouldReturnNewestFirstWithDecision() {
+	// Arrange
+	suite.createTeam(dto.Team{
+		TeamName: "reviews",
+		Members: []dto.TeamMember{
+			{UserID: "rv1", Username: "Alice", IsActive: true},
+			{UserID: "rv2", Username: "Bob", IsActive: true},
+		},
+	})
+	suite.createPR("pr-rv-1", "rv1")
+	suite.createPR("pr-rv-2", "rv1")
+	suite.createPR("pr-rv-3", "rv1")
+
+	response := suite.makeAuthorizedRequest("POST", "/pullRequest/review", suite.issueToken("rv2"), dto.SubmitReviewRequest{
+		PullRequestID: "pr-rv-1",
+		ReviewerID:    "rv2",
+		Decision:      enums.DecisionApproved,
+	})
+	suite.Require().Equal(http.StatusOK, response.Code, response.Body.String())
+	response = suite.makeRequest("POST", "/pullRequest/close", dto.PullRequestTransition{PullRequestID: "pr-rv-2"})
+	suite.Require().Equal(http.StatusOK, response.Code, response.Body.String())
+
+	// Act
+	response = suite.makeRequest("GET", "/users/getReview?user_id=rv2&status=OPENED&limit=1", nil)
+	suite.Require().Equal(http.StatusOK, response.Code, response.Body.String())
+	var first dto.GetPullRequestResponse
+	suite.Require().NoError(json.Unmarshal(response.Body.Bytes(), &first))
+
+	response = suite.makeRequest("GET", "/users/getReview?user_id=rv2&status=OPENED&limit=1&cursor="+first.NextCursor, nil)
+	suite.Require().Equal(http.StatusOK, response.Code, response.Body.String())
+	var second dto.GetPullRequestResponse
+	suite.Require().NoError(json.Unmarshal(response.Body.Bytes(), &second))
+
+	// Assert
+	suite.Require().Len(first.PullRequests, 1)
+	assert.Equal(suite.T(), "pr-rv-3", first.PullRequests[0].PullRequestID)
+	assert.Equal(suite.T(), enums.DecisionPending, first.PullRequests[0].Decision)
+	assert.NotEmpty(suite.T(), first.NextCursor)
+
+	suite.Require().Len(second.PullRequests, 1)
+	assert.Equal(suite.T(), "pr-rv-1", second.PullRequests[0].PullRequestID)
+	assert.Equal(suite.T(), enums.DecisionApproved, second.PullRequests[0].Decision)
+	assert.Empty(suite.T(), second.NextCursor)
+}
+
+func (suite *PullRequestIntegrationTestSuite) TestStats_WhenReviewerReplacedAndPRMerged_ShouldCountPerReviewerAndTeam() {
+	// Arrange
+	suite.createTeam(dto.Team{
+		TeamName: "stats",
+		Members: []dto.TeamMember{
+			{UserID: "st1", Username: "Alice", IsActive: true},
+			{UserID: "st2", Username: "Bob", IsActive: true},
+			{UserID: "st3", Username: "Carol", IsActive: true},
+		},
+		TeamSettings: dto.TeamSettings{ReviewersPerPR: 1},
+	})
+	pr := suite.createPR("pr-st-1", "st1")
+	oldReviewer := pr.Reviewers[0].UserID
+	newReviewer := "st2"
+	if oldReviewer == newReviewer {
+		newReviewer = "st3"
+	}
+
+	response := suite.makeRequest("POST", "/pullRequest/reassign", dto.ReassignReviewer{
+		PullRequestID: "pr-st-1",
+		OldUserID:     oldReviewer,
+		NewUserID:     newReviewer,
+	})
+	suite.Require().Equal(http.StatusOK, response.Code, response.Body.String())
+	response = suite.makeAuthorizedRequest("POST", "/pullRequest/review", suite.issueToken(newReviewer), dto.SubmitReviewRequest{
+		PullRequestID: "pr-st-1",
+		ReviewerID:    newReviewer,
+		Decision:      enums.DecisionApproved,
+	})
+	suite.Require().Equal(http.StatusOK, response.Code, response.Body.String())
+	response = suite.makeRequest("POST", "/pullRequest/merge", dto.MergePullRequest{PullRequestID: "pr-st-1"})
+	suite.Require().Equal(http.StatusOK, response.Code, response.Body.String())
+
+	// Act
+	response = suite.makeRequest("GET", "/stats/reviewers?team_name=stats", nil)
+	suite.Require().Equal(http.StatusOK, response.Code, response.Body.String())
+	var reviewers dto.ReviewerStatsResponse
+	suite.Require().NoError(json.Unmarshal(response.Body.Bytes(), &reviewers))
+
+	response = suite.makeRequest("GET", "/stats/teams?team_name=stats", nil)
+	suite.Require().Equal(http.StatusOK, response.Code, response.Body.String())
+	var teams dto.TeamStatsResponse
+	suite.Require().NoError(json.Unmarshal(response.Body.Bytes(), &teams))
+
+	// Assert
+	byUser := make(map[string]dto.ReviewerStats, len(reviewers.Reviewers))
+	for _, stats := range reviewers.Reviewers {
+		byUser[stats.UserID] = stats
+	}
+	suite.Require().Len(byUser, 3)
+	assert.Equal(suite.T(), 0, byUser["st1"].Assigned)
+	assert.Equal(suite.T(), 1, byUser[oldReviewer].Assigned)
+	assert.Equal(suite.T(), 1, byUser[oldReviewer].ReassignedAway)
+	assert.Equal(suite.T(), 1, byUser[newReviewer].Assigned)
+	assert.Equal(suite.T(), 1, byUser[newReviewer].Approved)
+	assert.Equal(suite.T(), 0, byUser[newReviewer].OpenReviews)
+
+	suite.Require().Len(teams.Teams, 1)
+	assert.Equal(suite.T(), 1, teams.Teams[0].Created)
+	assert.Equal(suite.T(), 1, teams.Teams[0].Merged)
+	assert.Equal(suite.T(), 0, teams.Teams[0].Open)
+	assert.NotNil(suite.T(), teams.Teams[0].MedianTimeToMergeSeconds)
+
+	response = suite.makeRequest("GET", "/stats/teams?team_name=missing", nil)
+	assert.Equal(suite.T(), http.StatusNotFound, response.Code)
+
+	from := url.QueryEscape(time.Now().Add(time.Hour).UTC().Format(time.RFC3339))
+	response = suite.makeRequest("GET", "/stats/reviewers?team_name=stats&from="+from, nil)
+	suite.Require().Equal(http.StatusOK, response.Code, response.Body.String())
+	var later dto.ReviewerStatsResponse
+	suite.Require().NoError(json.Unmarshal(response.Body.Bytes(), &later))
+	for _, stats := range later.Reviewers {
+		assert.Zero(suite.T(), stats.Assigned, stats.UserID)
+		assert.Zero(suite.T(), stats.ReassignedAway, stats.UserID)
+	}
+}
+
+func (suite *PullRequestIntegrationTestSuite) TestMetrics_WhenPRCreatedAndMerged_ShouldCountByTeamAndRoute() {
+	// Arrange
+	suite.createTeam(dto.Team{
+		TeamName: "metrics",
+		Members: []dto.TeamMember{
+			{UserID: "mx1", Username: "Alice", IsActive: true},
+			{UserID: "mx2", Username: "Bob", IsActive: true},
+		},
+	})
+	suite.createPR("pr-mx-1", "mx1")
+	response := suite.makeAuthorizedRequest("POST", "/pullRequest/review", suite.issueToken("mx2"), dto.SubmitReviewRequest{
+		PullRequestID: "pr-mx-1",
+		ReviewerID:    "mx2",
+		Decision:      enums.DecisionApproved,
+	})
+	suite.Require().Equal(http.StatusOK, response.Code, response.Body.String())
+	response = suite.makeRequest("POST", "/pullRequest/merge", dto.MergePullRequest{PullRequestID: "pr-mx-1"})
+	suite.Require().Equal(http.StatusOK, response.Code, response.Body.String())
+
+	// Act
+	response = suite.makeRequest("GET", "/metrics", nil)
+
+	// Assert
+	suite.Require().Equal(http.StatusOK, response.Code)
+	body := response.Body.String()
+	assert.Contains(suite.T(), body, `prreviewer_pull_requests_created_total{team="metrics"} 1`)
+	assert.Contains(suite.T(), body, `prreviewer_pull_requests_merged_total{team="metrics"} 1`)
+	assert.Contains(suite.T(), body, `prreviewer_http_requests_total{method="POST",route="/pullRequest/create",status="201"}`)
+}
+
+// requestSpans возвращает span'ы трассы последнего запроса с именем serverSpan (например, "POST /pullRequest/create").
+func (suite *PullRequestIntegrationTestSuite) requestSpans(serverSpan string) (sdktrace.ReadOnlySpan, map[string]sdktrace.ReadOnlySpan) {
+	ended := suite.spans.Ended()
+	var server sdktrace.ReadOnlySpan
+	for _, span := range ended {
+		if span.Name() == serverSpan {
+			server = span
+		}
+	}
+	suite.Require().NotNil(server, "span %q не найден", serverSpan)
+
+	byName := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range ended {
+		if span.SpanContext().TraceID() == server.SpanContext().TraceID() {
+			byName[span.Name()] = span
+		}
+	}
+	return server, byName
+}
+
+func (suite *PullRequestIntegrationTestSuite) TestTracing_WhenPRCreated_ShouldTraceRequestServiceAndQueries() {
+	// Arrange
+	suite.createTeam(dto.Team{
+		TeamName: "tracing",
+		Members: []dto.TeamMember{
+			{UserID: "tr1", Username: "Alice", IsActive: true},
+			{UserID: "tr2", Username: "Bob", IsActive: true},
+		},
+	})
+
+	// Act
+	suite.createPR("pr-tr-1", "tr1")
+
+	// Assert
+	server, spans := suite.requestSpans("POST /pullRequest/create")
+	assert.Equal(suite.T(), trace.SpanKindServer, server.SpanKind())
+	assert.Equal(suite.T(), codes.Unset, server.Status().Code)
+
+	create, ok := spans["PullRequestService.CreatePullRequest"]
+	suite.Require().True(ok, "нет span'а сервиса")
+	assert.Equal(suite.T(), server.SpanContext().SpanID(), create.Parent().SpanID())
+	assert.Equal(suite.T(), codes.Unset, create.Status().Code)
+
+	query, ok := spans["SQLRepo.CreatePR"]
+	suite.Require().True(ok, "нет span'а запроса")
+	assert.Equal(suite.T(), trace.SpanKindClient, query.SpanKind())
+	assert.Equal(suite.T(), codes.Unset, query.Status().Code)
+}
+
+func (suite *PullRequestIntegrationTestSuite) TestTracing_WhenServiceFails_ShouldRecordErrorOnSpans() {
+	// Arrange
+	suite.createTeam(dto.Team{
+		TeamName: "tracing-errors",
+		Members: []dto.TeamMember{
+			{UserID: "te1", Username: "Alice", IsActive: true},
+			{UserID: "te2", Username: "Bob", IsActive: true},
+		},
+	})
+
+	// Act: id длиннее VARCHAR(36), вставка pr падает в базе
+	response := suite.makeAuthorizedRequest("POST", "/pullRequest/create", suite.issueToken("te1"), dto.CreatePullRequest{
+		PullRequestID:   strings.Repeat("x", 40),
+		PullRequestName: "too long",
+		AuthorID:        "te1",
+	})
+
+	// Assert
+	suite.Require().Equal(http.StatusInternalServerError, response.Code, response.Body.String())
+	server, spans := suite.requestSpans("POST /pullRequest/create")
+	assert.Equal(suite.T(), codes.Error, server.Status().Code)
+
+	create, ok := spans["PullRequestService.CreatePullRequest"]
+	suite.Require().True(ok, "нет span'а сервиса")
+	assert.Equal(suite.T(), server.SpanContext().SpanID(), create.Parent().SpanID())
+
+	transaction, ok := spans["SQLTransactor.WithinTransaction"]
+	suite.Require().True(ok, "нет span'а транзакции")
+	assert.Equal(suite.T(), create.SpanContext().SpanID(), transaction.Parent().SpanID())
+	assert.Equal(suite.T(), codes.Error, transaction.Status().Code)
+
+	query, ok := spans["SQLRepo.CreatePR"]
+	suite.Require().True(ok, "нет span'а запроса")
+	assert.Equal(suite.T(), codes.Error, query.Status().Code)
+	suite.Require().NotEmpty(query.Events())
+	assert.Equal(suite.T(), "exception", query.Events()[0].Name)
+}
+
+type receivedWebhook struct {
+	event     string
+	timestamp string
+	signature string
+	body      []byte
+}
+
+// webhookReceiver поднимает получателя вебхуков, который отвечает status и запоминает все запросы.
+func (suite *PullRequestIntegrationTestSuite) webhookReceiver(status int) (*httptest.Server, func() []receivedWebhook) {
+	var mu sync.Mutex
+	var received []receivedWebhook
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		suite.Require().NoError(err)
+		mu.Lock()
+		received = append(received, receivedWebhook{
+			event:     r.Header.Get(webhook.HeaderEvent),
+			timestamp: r.Header.Get(webhook.HeaderTimestamp),
+			signature: r.Header.Get(webhook.HeaderSignature),
+			body:      body,
+		})
+		mu.Unlock()
+		w.WriteHeader(status)
+	}))
+	suite.T().Cleanup(server.Close)
+
+	return server, func() []receivedWebhook {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]receivedWebhook(nil), received...)
+	}
+}
+
+func (suite *PullRequestIntegrationTestSuite) subscribe(url, secret string, eventTypes ...enums.WebhookEventType) {
+	response := suite.makeAuthorizedRequest("POST", "/webhooks/subscriptions/add", adminToken, dto.CreateWebhookSubscriptionRequest{
+		URL:        url,
+		Secret:     secret,
+		EventTypes: eventTypes,
+	})
+	suite.Require().Equal(http.StatusCreated, response.Code, response.Body.String())
+}
+
+func (suite *PullRequestIntegrationTestSuite) newDispatcher(maxAttempts int) *webhook.Dispatcher {
+	cfg := webhook.DefaultConfig()
+	cfg.Timeout = 5 * time.Second
+	cfg.MaxAttempts = maxAttempts
+	cfg.AllowPrivateTargets = true
+	return webhook.NewDispatcher(suite.repository, cfg, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func (suite *PullRequestIntegrationTestSuite) TestWebhooks_WhenPRCreated_ShouldDeliverSignedEvents() {
+	// Arrange
+	secret := "0123456789abcdef"
+	receiver, received := suite.webhookReceiver(http.StatusOK)
+	suite.subscribe(receiver.URL, secret, enums.WebhookPRCreated, enums.WebhookReviewerAssigned)
+	suite.createTeam(dto.Team{
+		TeamName: "webhooks",
+		Members: []dto.TeamMember{
+			{UserID: "wh1", Username: "Alice", IsActive: true},
+			{UserID: "wh2", Username: "Bob", IsActive: true},
+		},
+	})
+	suite.createPR("pr-wh-1", "wh1")
+
+	// Act
+	dispatched, err := suite.newDispatcher(3).DispatchOnce(suite.ctx)
+
+	// Assert
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 2, dispatched)
+
+	requests := received()
+	suite.Require().Len(requests, 2)
+	events := make(map[string]dto.WebhookPayload)
+	for _, request := range requests {
+		suite.Require().NotEmpty(request.timestamp)
+		assert.Equal(suite.T(), webhook.Sign(secret, webhook.SignedPayload(request.timestamp, request.body)), request.signature)
+
+		var payload dto.WebhookPayload
+		suite.Require().NoError(json.Unmarshal(request.body, &payload))
+		assert.Equal(suite.T(), request.event, string(payload.Event))
+		assert.Equal(suite.T(), "pr-wh-1", payload.PullRequestID)
+		events[request.event] = payload
+	}
+	assert.Equal(suite.T(), "wh1", events[string(enums.WebhookPRCreated)].ActorID)
+	assert.Equal(suite.T(), "wh2", events[string(enums.WebhookReviewerAssigned)].ReviewerID)
+
+	dispatched, err = suite.newDispatcher(3).DispatchOnce(suite.ctx)
+	suite.Require().NoError(err)
+	assert.Zero(suite.T(), dispatched)
+}
+
+func (suite *PullRequestIntegrationTestSuite) TestWebhooks_WhenTargetIsPrivate_ShouldRejectSubscription() {
+	// Arrange
+	dispatcher := webhook.NewDispatcher(suite.repository, webhook.DefaultConfig(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	webhookService := service.NewWebhookService(suite.repository, dispatcher, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	for _, target := range []string{
+		"http://127.0.0.1:8080/hook",
+		"http://localhost/hook",
+		"http://10.0.0.5/hook",
+		"http://169.254.169.254/latest/meta-data",
+		"http://[::1]/hook",
+		"ftp://example.com/hook",
+	} {
+		// Act
+		_, err := webhookService.CreateSubscription(suite.ctx, dto.CreateWebhookSubscriptionRequest{
+			URL:        target,
+			Secret:     "0123456789abcdef",
+			EventTypes: []enums.WebhookEventType{enums.WebhookPRCreated},
+		})
+
+		// Assert
+		assert.ErrorIs(suite.T(), err, errs.ErrInvalidWebhookURL, target)
+	}
+
+	subscriptions, err := suite.repository.GetWebhookSubscriptions(suite.ctx)
+	suite.Require().NoError(err)
+	assert.Empty(suite.T(), subscriptions)
+}
+
+func (suite *PullRequestIntegrationTestSuite) TestWebhooks_WhenCreateRolledBack_ShouldNotEnqueueEvents() {
+	// Arrange
+	limit := 1
+	suite.createTeam(dto.Team{
+		TeamName: "webhooks-rollback",
+		Members: []dto.TeamMember{
+			{UserID: "whr1", Username: "Alice", IsActive: true},
+			{UserID: "whr2", Username: "Bob", IsActive: true, MaxOpenReviews: &limit},
+		},
+	})
+	suite.createPR("pr-whr-1", "whr1")
+	receiver, received := suite.webhookReceiver(http.StatusOK)
+	suite.subscribe(receiver.URL, "0123456789abcdef", enums.WebhookPRCreated)
+
+	response := suite.makeRequest("POST", "/pullRequest/create", dto.CreatePullRequest{
+		PullRequestID:   "pr-whr-2",
+		PullRequestName: "PR pr-whr-2",
+		AuthorID:        "whr1",
+	})
+	suite.Require().Equal(http.StatusConflict, response.Code, response.Body.String())
+
+	// Act
+	dispatched, err := suite.newDispatcher(3).DispatchOnce(suite.ctx)
+
+	// Assert
+	suite.Require().NoError(err)
+	assert.Zero(suite.T(), dispatched)
+	assert.Empty(suite.T(), received())
+}
+
+func (suite *PullRequestIntegrationTestSuite) TestWebhooks_WhenAttemptsExhausted_ShouldMoveToDeadLettersUntilRetried() {
+	// Arrange
+	receiver, received := suite.webhookReceiver(http.StatusInternalServerError)
+	suite.subscribe(receiver.URL, "0123456789abcdef", enums.WebhookPRCreated)
+	suite.createTeam(dto.Team{
+		TeamName: "webhooks-dead",
+		Members: []dto.TeamMember{
+			{UserID: "whd1", Username: "Alice", IsActive: true},
+			{UserID: "whd2", Username: "Bob", IsActive: true},
+		},
+	})
+	suite.createPR("pr-whd-1", "whd1")
+
+	dispatched, err := suite.newDispatcher(1).DispatchOnce(suite.ctx)
+	suite.Require().NoError(err)
+	suite.Require().Equal(1, dispatched)
+	suite.Require().Len(received(), 1)
+
+	// Act
+	response := suite.makeAuthorizedRequest("GET", "/webhooks/deadLetters", adminToken, nil)
+
+	// Assert
+	suite.Require().Equal(http.StatusOK, response.Code, response.Body.String())
+	var deadLetters dto.DeadLettersResponse
+	suite.Require().NoError(json.Unmarshal(response.Body.Bytes(), &deadLetters))
+	suite.Require().Len(deadLetters.Deliveries, 1)
+	dead := deadLetters.Deliveries[0]
+	assert.Equal(suite.T(), enums.DeliveryDead, dead.Status)
+	assert.Equal(suite.T(), enums.WebhookPRCreated, dead.EventType)
+	assert.Equal(suite.T(), 1, dead.Attempts)
+	assert.Equal(suite.T(), http.StatusInternalServerError, dead.LastStatusCode)
+
+	response = suite.makeAuthorizedRequest("POST", "/webhooks/deadLetters/retry", adminToken, dto.RetryDeadLettersRequest{
+		DeliveryIDs: []int64{dead.ID},
+	})
+	suite.Require().Equal(http.StatusOK, response.Code, response.Body.String())
+	var retried dto.RetryDeadLettersResponse
+	suite.Require().NoError(json.Unmarshal(response.Body.Bytes(), &retried))
+	assert.Equal(suite.T(), 1, retried.Retried)
+
+	dispatched, err = suite.newDispatcher(1).DispatchOnce(suite.ctx)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 1, dispatched)
+	assert.Len(suite.T(), received(), 2)
+}
+
+func (suite *PullRequestIntegrationTestSuite) TestWebhooks_WhenCallerIsNotAdmin_ShouldRejectManagement() {
+	// Arrange
+	suite.createTeam(dto.Team{
+		TeamName: "webhooks-intruders",
+		Members: []dto.TeamMember{
+			{UserID: "whi1", Username: "Alice", IsActive: true},
+		},
+	})
+	token := suite.issueToken("whi1")
+	subscription := dto.CreateWebhookSubscriptionRequest{
+		URL:        "http://attacker.example/hook",
+		Secret:     "0123456789abcdef",
+		EventTypes: []enums.WebhookEventType{enums.WebhookPRCreated},
+	}
+
+	// Act
+	subscribed := suite.makeAuthorizedRequest("POST", "/webhooks/subscriptions/add", token, subscription)
+	anonymous := suite.makeRequest("POST", "/webhooks/subscriptions/add", subscription)
+	deadLetters := suite.makeAuthorizedRequest("GET", "/webhooks/deadLetters", token, nil)
+	retried := suite.makeAuthorizedRequest("POST", "/webhooks/deadLetters/retry", token, dto.RetryDeadLettersRequest{DeliveryIDs: []int64{1}})
+
+	// Assert
+	var errorResponse dto.ErrorResponse
+	for _, response := range []*httptest.ResponseRecorder{subscribed, deadLetters, retried} {
+		suite.Require().Equal(http.StatusForbidden, response.Code, response.Body.String())
+		suite.Require().NoError(json.Unmarshal(response.Body.Bytes(), &errorResponse))
+		assert.Equal(suite.T(), enums.CodeForbidden, errorResponse.Code)
+	}
+
+	suite.Require().Equal(http.StatusUnauthorized, anonymous.Code, anonymous.Body.String())
+	suite.Require().NoError(json.Unmarshal(anonymous.Body.Bytes(), &errorResponse))
+	assert.Equal(suite.T(), enums.CodeUnauthorized, errorResponse.Code)
+
+	var subscriptions int
+	suite.Require().NoError(suite.db.QueryRowContext(suite.ctx, `SELECT COUNT(*) FROM webhook_subscriptions`).Scan(&subscriptions))
+	assert.Zero(suite.T(), subscriptions)
+}
+
+type sseEvent struct {
+	id    string
+	event string
+	data  string
+}
+
+// openReviewStream подключается к потоку ревью через настоящий http-сервер: recorder не отдаёт ответ по частям.
+func (suite *PullRequestIntegrationTestSuite) openReviewStream(router http.Handler, userID, lastEventID string) *bufio.Reader {
+	server := httptest.NewServer(router)
+	suite.T().Cleanup(server.Close)
+
+	ctx, cancel := context.WithTimeout(suite.ctx, 10*time.Second)
+	suite.T().Cleanup(cancel)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", server.URL+"/users/reviewStream?user_id="+userID, nil)
+	suite.Require().NoError(err)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	suite.Require().NoError(err)
+	suite.T().Cleanup(func() { resp.Body.Close() })
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+	suite.Require().Equal("text/event-stream", resp.Header.Get("Content-Type"))
+
+	return bufio.NewReader(resp.Body)
+}
+
+// readSSE читает следующее событие потока, пропуская комментарии-пинги.
+func (suite *PullRequestIntegrationTestSuite) readSSE(stream *bufio.Reader) sseEvent {
+	var event sseEvent
+	for {
+		line, err := stream.ReadString('\n')
+		suite.Require().NoError(err)
+		line = strings.TrimSuffix(line, "\n")
+
+		switch {
+		case line == "" && event.id != "":
+			return event
+		case strings.HasPrefix(line, "id: "):
+			event.id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "event: "):
+			event.event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			event.data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+}
+
+func (suite *PullRequestIntegrationTestSuite) TestReviewStream_WhenReviewerAssigned_ShouldPushEvent() {
+	// Arrange
+	suite.createTeam(dto.Team{
+		TeamName: "stream",
+		Members: []dto.TeamMember{
+			{UserID: "st1", Username: "Alice", IsActive: true},
+			{UserID: "st2", Username: "Bob", IsActive: true},
+		},
+	})
+	stream := suite.openReviewStream(suite.router, "st2", "")
+
+	// Act
+	suite.createPR("pr-st-1", "st1")
+
+	// Assert
+	received := suite.readSSE(stream)
+	assert.Equal(suite.T(), string(enums.EventReviewerAssigned), received.event)
+
+	var event entities.PREvent
+	suite.Require().NoError(json.Unmarshal([]byte(received.data), &event))
+	assert.Equal(suite.T(), "pr-st-1", event.PullRequestID)
+	assert.Equal(suite.T(), "st2", event.ReviewerID)
+	assert.Equal(suite.T(), strconv.FormatInt(event.ID, 10), received.id)
+}
+
+func (suite *PullRequestIntegrationTestSuite) TestReviewStream_WhenLastEventIDSent_ShouldResumeFromEventLog() {
+	// Arrange
+	suite.createTeam(dto.Team{
+		TeamName: "stream-resume",
+		Members: []dto.TeamMember{
+			{UserID: "sr1", Username: "Alice", IsActive: true},
+			{UserID: "sr2", Username: "Bob", IsActive: true},
+		},
+	})
+	suite.createPR("pr-sr-1", "sr1")
+	response := suite.makeRequest("POST", "/pullRequest/merge", dto.MergePullRequest{PullRequestID: "pr-sr-1"})
+	suite.Require().Equal(http.StatusOK, response.Code, response.Body.String())
+
+	// Act
+	fromStart := suite.openReviewStream(suite.router, "sr2", "0")
+	assigned := suite.readSSE(fromStart)
+	merged := suite.readSSE(fromStart)
+	afterAssigned := suite.openReviewStream(suite.router, "sr2", assigned.id)
+
+	// Assert
+	assert.Equal(suite.T(), string(enums.EventReviewerAssigned), assigned.event)
+	assert.Equal(suite.T(), string(enums.EventMerged), merged.event)
+	assert.Equal(suite.T(), merged, suite.readSSE(afterAssigned))
+}
+
+func (suite *PullRequestIntegrationTestSuite) TestReviewStream_WhenHandlerClosed_ShouldEndStream() {
+	// Arrange
+	suite.createTeam(dto.Team{
+		TeamName: "stream-close",
+		Members: []dto.TeamMember{
+			{UserID: "sc1", Username: "Alice", IsActive: true},
+		},
+	})
+	streamHandler := handlers.NewStreamHandler(suite.prService, 10*time.Millisecond)
+	router := gin.New()
+	router.GET("/users/reviewStream", streamHandler.ReviewStream)
+	stream := suite.openReviewStream(router, "sc1", "")
+
+	heartbeat, err := stream.ReadString('\n')
+	suite.Require().NoError(err)
+	suite.Require().Equal(": heartbeat\n", heartbeat)
+
+	// Act
+	streamHandler.Close()
+
+	// Assert
+	_, err = io.ReadAll(stream)
+	assert.NoError(suite.T(), err)
+}
+
+// gitHubFixturePRID — id pr в сервисе для записанных событий из testdata/github.
+const gitHubFixturePRID = "gh-1879432561"
+
+func (suite *PullRequestIntegrationTestSuite) linkGitHubLogin(userID, login string) {
+	response := suite.makeAuthorizedRequest("POST", "/admin/users/setGitHubLogin", adminToken, dto.SetGitHubLoginRequest{UserID: userID, GitHubLogin: login})
+	suite.Require().Equal(http.StatusOK, response.Code, response.Body.String())
+}
+
+// deliverGitHubEvent отправляет записанный вебхук GitHub так же, как его присылает GitHub.
+func (suite *PullRequestIntegrationTestSuite) deliverGitHubEvent(event, fixture, secret string) *httptest.ResponseRecorder {
+	body, err := os.ReadFile("testdata/github/" + fixture)
+	suite.Require().NoError(err)
+
+	req, err := http.NewRequest("POST", "/integrations/github/webhook", bytes.NewReader(body))
+	suite.Require().NoError(err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-GitHub-Event", event)
+	req.Header.Set("X-GitHub-Delivery", "72d3162e-cc78-11e3-81ab-4c9367dc0958")
+	req.Header.Set("X-Hub-Signature-256", webhook.Sign(secret, body))
+
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	return w
+}
+
+func (suite *PullRequestIntegrationTestSuite) parsePR(response *httptest.ResponseRecorder) entities.PullRequest {
+	var pr entities.PullRequest
+	suite.Require().NoError(json.Unmarshal(response.Body.Bytes(), &pr))
+	return pr
+}
+
+func (suite *PullRequestIntegrationTestSuite) createGitHubTeam() {
+	suite.createTeam(dto.Team{
+		TeamName: "github",
+		Members: []dto.TeamMember{
+			{UserID: "gh1", Username: "Alice", IsActive: true},
+			{UserID: "gh2", Username: "Bob", IsActive: true},
+		},
+	})
+	suite.linkGitHubLogin("gh1", "octo-alice")
+	suite.linkGitHubLogin("gh2", "Octo-Bob")
+}
+
+func (suite *PullRequestIntegrationTestSuite) TestGitHubWebhook_WhenNoReviewersAvailable_ShouldStorePRWithoutReviewers() {
+	// Arrange
+	suite.createTeam(dto.Team{
+		TeamName: "github",
+		Members: []dto.TeamMember{
+			{UserID: "gh1", Username: "Alice", IsActive: true},
+			{UserID: "gh2", Username: "Bob", IsActive: false},
+		},
+	})
+	suite.linkGitHubLogin("gh1", "octo-alice")
+
+	// Act
+	opened := suite.deliverGitHubEvent("pull_request", "pull_request_opened.json", gitHubSecret)
+	merged := suite.deliverGitHubEvent("pull_request", "pull_request_closed_merged.json", gitHubSecret)
+
+	// Assert
+	suite.Require().Equal(http.StatusOK, opened.Code, opened.Body.String())
+	created := suite.parsePR(opened)
+	assert.Equal(suite.T(), gitHubFixturePRID, created.ID)
+	assert.Equal(suite.T(), string(enums.PRStatusOpened), created.Status)
+	assert.Empty(suite.T(), created.Reviewers)
+
+	suite.Require().Equal(http.StatusOK, merged.Code, merged.Body.String())
+	assert.Equal(suite.T(), string(enums.PRStatusMerged), suite.parsePR(merged).Status)
+}
+
+func (suite *PullRequestIntegrationTestSuite) TestGitHubWebhook_WhenPROpenedAndMerged_ShouldCreateAndMergePR() {
+	// Arrange
+	suite.createGitHubTeam()
+
+	// Act
+	opened := suite.deliverGitHubEvent("pull_request", "pull_request_opened.json", gitHubSecret)
+	redelivered := suite.deliverGitHubEvent("pull_request", "pull_request_opened.json", gitHubSecret)
+	merged := suite.deliverGitHubEvent("pull_request", "pull_request_closed_merged.json", gitHubSecret)
+
+	// Assert
+	suite.Require().Equal(http.StatusOK, opened.Code, opened.Body.String())
+	created := suite.parsePR(opened)
+	assert.Equal(suite.T(), gitHubFixturePRID, created.ID)
+	assert.Equal(suite.T(), "Add rate limiting to the public API", created.Name)
+	assert.Equal(suite.T(), "gh1", created.AuthorID)
+	assert.Equal(suite.T(), []string{"gh2"}, reviewerIDs(created))
+
+	assert.Equal(suite.T(), http.StatusNoContent, redelivered.Code, redelivered.Body.String())
+
+	suite.Require().Equal(http.StatusOK, merged.Code, merged.Body.String())
+	assert.Equal(suite.T(), string(enums.PRStatusMerged), suite.parsePR(merged).Status)
+
+	response := suite.makeRequest("GET", "/pullRequest/history?pull_request_id="+gitHubFixturePRID, nil)
+	suite.Require().Equal(http.StatusOK, response.Code, response.Body.String())
+	var history dto.PullRequestHistoryResponse
+	suite.Require().NoError(json.Unmarshal(response.Body.Bytes(), &history))
+	last := history.Events[len(history.Events)-1]
+	assert.Equal(suite.T(), enums.EventMerged, last.Type)
+	assert.Equal(suite.T(), "gh2", last.ActorID)
+}
+
+func (suite *PullRequestIntegrationTestSuite) TestGitHubWebhook_WhenDraftClosedAndReopened_ShouldFollowPRStatus() {
+	// Arrange
+	suite.createGitHubTeam()
+	opened := suite.deliverGitHubEvent("pull_request", "pull_request_opened.json", gitHubSecret)
+	suite.Require().Equal(http.StatusOK, opened.Code, opened.Body.String())
+
+	steps := []struct {
+		fixture string
+		status  enums.PRStatus
+	}{
+		{"pull_request_converted_to_draft.json", enums.PRStatusDraft},
+		{"pull_request_ready_for_review.json", enums.PRStatusOpened},
+		{"pull_request_closed.json", enums.PRStatusClosed},
+		{"pull_request_reopened.json", enums.PRStatusOpened},
+	}
+
+	for _, step := range steps {
+		// Act
+		response := suite.deliverGitHubEvent("pull_request", step.fixture, gitHubSecret)
+
+		// Assert
+		suite.Require().Equal(http.StatusOK, response.Code, step.fixture+": "+response.Body.String())
+		pr := suite.parsePR(response)
+		assert.Equal(suite.T(), string(step.status), pr.Status, step.fixture)
+		assert.Equal(suite.T(), []string{"gh2"}, reviewerIDs(pr), step.fixture)
+	}
+}
+
+func (suite *PullRequestIntegrationTestSuite) TestGitHubWebhook_WhenMergedAgainstPolicy_ShouldMergeAndRecordOverride() {
+	// Arrange
+	suite.createGitHubTeam()
+	response := suite.makeAuthorizedRequest("POST", "/team/settings", adminToken, dto.UpdateTeamSettingsRequest{
+		TeamName:    "github",
+		MergePolicy: &dto.MergePolicy{MinApprovals: 1},
+	})
+	suite.Require().Equal(http.StatusOK, response.Code, response.Body.String())
+	opened := suite.deliverGitHubEvent("pull_request", "pull_request_opened.json", gitHubSecret)
+	suite.Require().Equal(http.StatusOK, opened.Code, opened.Body.String())
+
+	// Act
+	merged := suite.deliverGitHubEvent("pull_request", "pull_request_closed_merged.json", gitHubSecret)
+	redelivered := suite.deliverGitHubEvent("pull_request", "pull_request_closed_merged.json", gitHubSecret)
+
+	// Assert
+	suite.Require().Equal(http.StatusOK, merged.Code, merged.Body.String())
+	assert.Equal(suite.T(), string(enums.PRStatusMerged), suite.parsePR(merged).Status)
+	suite.Require().Equal(http.StatusOK, redelivered.Code, redelivered.Body.String())
+	assert.Equal(suite.T(), string(enums.PRStatusMerged), suite.parsePR(redelivered).Status)
+
+	var actorID, reason string
+	err := suite.db.QueryRowContext(suite.ctx,
+		`SELECT actor_id, reason FROM merge_overrides WHERE pr_id = $1`, gitHubFixturePRID).Scan(&actorID, &reason)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), "gh2", actorID)
+	assert.Equal(suite.T(), "смерджен в github", reason)
+}
+
+func (suite *PullRequestIntegrationTestSuite) TestGitHubWebhook_WhenClosedRedelivered_ShouldIgnoreRepeat() {
+	// Arrange
+	suite.createGitHubTeam()
+	opened := suite.deliverGitHubEvent("pull_request", "pull_request_opened.json", gitHubSecret)
+	suite.Require().Equal(http.StatusOK, opened.Code, opened.Body.String())
+
+	// Act
+	closed := suite.deliverGitHubEvent("pull_request", "pull_request_closed.json", gitHubSecret)
+	redelivered := suite.deliverGitHubEvent("pull_request", "pull_request_closed.json", gitHubSecret)
+
+	// Assert
+	suite.Require().Equal(http.StatusOK, closed.Code, closed.Body.String())
+	assert.Equal(suite.T(), string(enums.PRStatusClosed), suite.parsePR(closed).Status)
+	assert.Equal(suite.T(), http.StatusNoContent, redelivered.Code, redelivered.Body.String())
+
+	response := suite.makeRequest("GET", "/pullRequest/history?pull_request_id="+gitHubFixturePRID, nil)
+	suite.Require().Equal(http.StatusOK, response.Code, response.Body.String())
+	var history dto.PullRequestHistoryResponse
+	suite.Require().NoError(json.Unmarshal(response.Body.Bytes(), &history))
+	closedEvents := 0
+	for _, event := range history.Events {
+		if event.Type == enums.EventClosed {
+			closedEvents++
+		}
+	}
+	assert.Equal(suite.T(), 1, closedEvents)
+}
+
+func (suite *PullRequestIntegrationTestSuite) TestGitHubWebhook_WhenSignatureInvalid_ShouldRejectEvent() {
+	// Arrange
+	suite.createGitHubTeam()
+
+	// Act
+	ping := suite.deliverGitHubEvent("ping", "ping.json", gitHubSecret)
+	forged := suite.deliverGitHubEvent("pull_request", "pull_request_opened.json", "not-the-secret")
+
+	// Assert
+	assert.Equal(suite.T(), http.StatusNoContent, ping.Code, ping.Body.String())
+
+	suite.Require().Equal(http.StatusUnauthorized, forged.Code, forged.Body.String())
+	var errorResponse dto.ErrorResponse
+	suite.Require().NoError(json.Unmarshal(forged.Body.Bytes(), &errorResponse))
+	assert.Equal(suite.T(), enums.CodeInvalidSignature, errorResponse.Code)
+
+	response := suite.makeRequest("GET", "/pullRequest/history?pull_request_id="+gitHubFixturePRID, nil)
+	assert.Equal(suite.T(), http.StatusNotFound, response.Code)
+}
+
+func (suite *PullRequestIntegrationTestSuite) TestGitHubWebhook_WhenAuthorLoginNotLinked_ShouldReturnUnknownLogin() {
+	// Arrange
+	suite.createTeam(dto.Team{
+		TeamName: "github-unlinked",
+		Members: []dto.TeamMember{
+			{UserID: "ghu1", Username: "Alice", IsActive: true},
+			{UserID: "ghu2", Username: "Bob", IsActive: true},
+		},
+	})
+	suite.linkGitHubLogin("ghu2", "octo-bob")
+
+	// Act
+	response := suite.deliverGitHubEvent("pull_request", "pull_request_opened.json", gitHubSecret)
+
+	// Assert
+	suite.Require().Equal(http.StatusUnprocessableEntity, response.Code, response.Body.String())
+	var errorResponse dto.ErrorResponse
+	suite.Require().NoError(json.Unmarshal(response.Body.Bytes(), &errorResponse))
+	assert.Equal(suite.T(), enums.CodeUnknownGitHubLogin, errorResponse.Code)
+
+	taken := suite.makeAuthorizedRequest("POST", "/admin/users/setGitHubLogin", adminToken, dto.SetGitHubLoginRequest{UserID: "ghu1", GitHubLogin: "OCTO-BOB"})
+	suite.Require().Equal(http.StatusConflict, taken.Code, taken.Body.String())
+	suite.Require().NoError(json.Unmarshal(taken.Body.Bytes(), &errorResponse))
+	assert.Equal(suite.T(), enums.CodeGitHubLoginTaken, errorResponse.Code)
+}
@@ -0,0 +1,85 @@
+package integration
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+const migrationsDir = "../../migrations"
+
+func startPostgres(ctx context.Context) (*postgres.PostgresContainer, *sql.DB, error) {
+	postgresContainer, err := postgres.RunContainer(ctx,
+		testcontainers.WithImage("postgres:15-alpine"),
+		postgres.WithDatabase("test_db"),
+		postgres.WithUsername("test_user"),
+		postgres.WithPassword("test_password"),
+		testcontainers.WithWaitStrategy(
+			wait.ForLog("database system is ready to accept connections").
+				WithOccurrence(2).
+				WithStartupTimeout(30*time.Second)),
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	connStr, err := postgresContainer.ConnectionString(ctx)
+	if err != nil {
+		return postgresContainer, nil, err
+	}
+
+	db, err := sql.Open("pgx", connStr)
+	if err != nil {
+		return postgresContainer, nil, err
+	}
+
+	if err = db.PingContext(ctx); err != nil {
+		return postgresContainer, db, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	return postgresContainer, db, nil
+}
+
+func applyMigrations(db *sql.DB, dir string) ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.up.sql"))
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return migrationVersion(files[i]) < migrationVersion(files[j])
+	})
+
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration file: %w", err)
+		}
+
+		_, err = db.Exec(string(content))
+		if err != nil {
+			return nil, fmt.Errorf("failed to execute migration %s: %w", file, err)
+		}
+	}
+
+	return files, nil
+}
+
+func migrationVersion(file string) int {
+	prefix, _, _ := strings.Cut(filepath.Base(file), "_")
+	version, err := strconv.Atoi(prefix)
+	if err != nil {
+		return 0
+	}
+	return version
+}
@@ -3,27 +3,26 @@ package integration
 import (
 	"PRReviewer/internal/adapter/server/handlers"
 	"PRReviewer/internal/core/dto"
+	"PRReviewer/internal/core/selector"
 	"PRReviewer/internal/core/service"
 	"PRReviewer/internal/infrastructure/data/repo"
+	"PRReviewer/internal/infrastructure/metrics"
+	"PRReviewer/internal/infrastructure/pubsub"
 	"bytes"
 	"context"
 	"database/sql"
 	"encoding/json"
-	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
-	"os"
 	"testing"
-	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
-	"github.com/testcontainers/testcontainers-go"
 	"github.com/testcontainers/testcontainers-go/modules/postgres"
-	"github.com/testcontainers/testcontainers-go/wait"
 
 	_ "github.com/jackc/pgx/v5/stdlib"
 )
@@ -41,51 +40,17 @@ func TestTeamIntegrationTestSuite(t *testing.T) {
 	suite.Run(t, new(TeamIntegrationTestSuite))
 }
 
-func (suite *TeamIntegrationTestSuite) migrate(db *sql.DB, relativePath string) error {
-
-	content, err := os.ReadFile("../../migrations/1_init.up.sql")
-	if err != nil {
-		return fmt.Errorf("failed to read migration file: %w", err)
-	}
-
-	_, err = db.Exec(string(content))
-	if err != nil {
-		return fmt.Errorf("failed to execute migration: %w", err)
-	}
-
-	suite.T().Logf("Migration applied successfully: %s", relativePath)
-	return nil
-}
-
 func (suite *TeamIntegrationTestSuite) SetupSuite() {
 	suite.ctx = context.Background()
 
-	postgresContainer, err := postgres.RunContainer(suite.ctx,
-		testcontainers.WithImage("postgres:15-alpine"),
-		postgres.WithDatabase("test_db"),
-		postgres.WithUsername("test_user"),
-		postgres.WithPassword("test_password"),
-		testcontainers.WithWaitStrategy(
-			wait.ForLog("database system is ready to accept connections").
-				WithOccurrence(2).
-				WithStartupTimeout(30*time.Second)),
-	)
-	suite.Require().NoError(err)
+	postgresContainer, db, err := startPostgres(suite.ctx)
 	suite.postgresContainer = postgresContainer
-
-	connStr, err := postgresContainer.ConnectionString(suite.ctx)
-	suite.Require().NoError(err)
-
-	db, err := sql.Open("pgx", connStr)
-	suite.Require().NoError(err)
-
 	suite.db = db
+	suite.Require().NoError(err)
 
-	err = db.PingContext(suite.ctx)
-	suite.Require().NoError(err, "Failed to connect to database")
-
-	err = suite.migrate(db, "migrations/1_init.up.sql")
+	migrations, err := applyMigrations(db, migrationsDir)
 	suite.Require().NoError(err, "Failed to apply migrations")
+	suite.T().Logf("Migrations applied successfully: %v", migrations)
 
 	repository := repo.New(db)
 	transactor := repo.NewSQLTransactor(db)
@@ -95,7 +60,8 @@ func (suite *TeamIntegrationTestSuite) SetupSuite() {
 	})
 	logger := slog.New(handler)
 
-	teamService := service.NewTeamService(repository, repository, transactor, logger)
+	prService := service.NewPullRequestService(repository, repository, repository, transactor, selector.NewRegistry(), metrics.New(prometheus.NewRegistry()), pubsub.NewBroker(), logger)
+	teamService := service.NewTeamService(repository, repository, prService, transactor, logger)
 	suite.teamHandler = handlers.NewTeamHandler(teamService)
 
 	suite.router = gin.Default()